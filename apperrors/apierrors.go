@@ -9,6 +9,7 @@ type APIError struct {
 	Status  int    `json:"status"`  // HTTP status returned to client.
 	Err     error  `json:"error"`   // Error that occurred.
 	Message string `json:"message"` // Additional information about error returned to client.
+	Code    string `json:"code"`    // Machine-readable code returned to client. Derived from Err or Status when empty.
 }
 
 func (e *APIError) Error() string {
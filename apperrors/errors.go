@@ -0,0 +1,69 @@
+package apperrors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Errors that describe why an operation against an upstream platform failed.
+// They are wrapped by the errors returned from the repositories and services,
+// and can be detected with errors.Is.
+var (
+	// ErrNotFound indicates that the requested resource does not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrSuspended indicates that the requested user has been suspended.
+	ErrSuspended = errors.New("suspended")
+
+	// ErrProtected indicates that the requested user only shares their
+	// followers and other details with the users they approve.
+	ErrProtected = errors.New("protected")
+
+	// ErrRateLimited indicates that the upstream platform's rate limit has been reached.
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrUnauthorized indicates that the server is not authorized to access the
+	// requested resource on the upstream platform.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrUpstreamUnavailable indicates that the upstream platform could not be
+	// reached or failed to handle the request.
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+
+	// ErrInvalidUsername indicates that a username is not valid on its platform.
+	ErrInvalidUsername = errors.New("invalid username")
+)
+
+// Kind describes how an error is reported to clients.
+type Kind struct {
+	Status  int    // HTTP status returned to client.
+	Code    string // Machine-readable code identifying the error.
+	Message string // Description of the error returned to client.
+}
+
+// kinds maps each error to the Kind reported to clients when it occurs.
+var kinds = []struct {
+	err  error
+	kind Kind
+}{
+	{ErrNotFound, Kind{http.StatusNotFound, "not_found", "the requested resource was not found"}},
+	{ErrSuspended, Kind{http.StatusForbidden, "suspended", "the requested user has been suspended"}},
+	{ErrProtected, Kind{http.StatusForbidden, "protected", "the requested user is protected"}},
+	{ErrRateLimited, Kind{http.StatusTooManyRequests, "rate_limited", "the upstream rate limit has been reached, try again later"}},
+	{ErrUnauthorized, Kind{http.StatusBadGateway, "unauthorized", "the server is not authorized to access the requested resource"}},
+	{ErrUpstreamUnavailable, Kind{http.StatusBadGateway, "upstream_unavailable", "the upstream platform is unavailable"}},
+	{ErrInvalidUsername, Kind{http.StatusBadRequest, "invalid_username", "the requested username is not valid"}},
+	{context.DeadlineExceeded, Kind{http.StatusGatewayTimeout, "upstream_timeout", "the upstream platform did not respond in time"}},
+}
+
+// KindOf returns the Kind of the first error in this package that the given
+// error wraps. If it does not wrap any of them, false is returned.
+func KindOf(err error) (Kind, bool) {
+	for _, k := range kinds {
+		if errors.Is(err, k.err) {
+			return k.kind, true
+		}
+	}
+	return Kind{}, false
+}
@@ -22,6 +22,26 @@ func Init(env string) {
 	replacer := strings.NewReplacer(".", "_")
 	config.SetEnvKeyReplacer(replacer)
 
+	config.SetDefault("server.errors.format", "problem")
+	config.SetDefault("server.shutdown_timeout", "10s")
+	config.SetDefault("apis.timeout", "10s")
+	config.SetDefault("apis.rate_limit.policy", "fail")
+	config.SetDefault("apis.retry.max_wait", "5s")
+	config.SetDefault("apis.twitter.auth", "app")
+	config.SetDefault("apis.twitter.oauth2.scopes", []string{"tweet.read", "users.read", "follows.read", "offline.access"})
+	config.SetDefault("apis.mastodon.instance", "mastodon.social")
+	config.SetDefault("apis.bluesky.url", "https://bsky.social")
+	config.SetDefault("apis.bluesky.public_url", "https://public.api.bsky.app")
+	config.SetDefault("apis.github.url", "https://api.github.com")
+	config.SetDefault("apis.youtube.url", "https://www.googleapis.com/youtube/v3")
+	config.SetDefault("apis.youtube.daily_quota", 10000)
+	config.SetDefault("apis.twitch.url", "https://api.twitch.tv/helix")
+	config.SetDefault("apis.twitch.token_url", "https://id.twitch.tv/oauth2/token")
+	config.SetDefault("scheduler.enabled", false)
+	config.SetDefault("scheduler.interval", "15m")
+	config.SetDefault("scheduler.min_interval", "1m")
+	config.SetDefault("scheduler.rate_limit_policy", "wait")
+
 	if err := config.ReadInConfig(); err != nil {
 		panic(err)
 	}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Account represents a user of any platform followrs supports. Fields other
+// than Platform, ID and Username are only present if the platform provides them.
+type Account struct {
+	Platform        string          `json:"platform"`                    // Platform the account belongs to, such as "twitter".
+	ID              string          `json:"id"`                          // ID of the account on its platform.
+	Username        string          `json:"username"`                    // Name the account is looked up by on its platform.
+	Name            string          `json:"name,omitempty"`              // Display name of the account.
+	Description     string          `json:"description,omitempty"`       // Biography of the account.
+	Location        string          `json:"location,omitempty"`          // Location the owner of the account gives.
+	ProfileImageURL string          `json:"profile_image_url,omitempty"` // URL of the avatar of the account.
+	URL             string          `json:"url,omitempty"`               // Website the owner of the account links to.
+	CreatedAt       *time.Time      `json:"created_at,omitempty"`        // Time the account was created.
+	Protected       bool            `json:"protected,omitempty"`         // Whether only approved followers can see the account.
+	Verified        bool            `json:"verified,omitempty"`          // Whether the platform has verified the account.
+	Metrics         *AccountMetrics `json:"metrics,omitempty"`           // Counts of the account when it was looked up.
+}
+
+// Follower represents an account in the list of the followers, or followed
+// accounts, of another account.
+type Follower struct {
+	ID              string `json:"id"`                          // ID of the account on its platform.
+	Username        string `json:"username"`                    // Name the account is looked up by on its platform.
+	Name            string `json:"name,omitempty"`              // Display name of the account.
+	ProfileImageURL string `json:"profile_image_url,omitempty"` // URL of the avatar of the account.
+}
+
+// Provider retrieves accounts from a single platform.
+type Provider interface {
+	// Platform returns the name of the platform, such as "twitter", which is
+	// used to route requests to the Provider.
+	Platform() string
+
+	// LookupUser returns the account with the given username.
+	LookupUser(ctx context.Context, username string) (*Account, error)
+
+	// ListFollowers returns the followers of the account with the given
+	// username, or ErrUnsupportedPlatform if the platform doesn't list them.
+	ListFollowers(ctx context.Context, username string) ([]Follower, error)
+
+	// ListFollowing returns the accounts that the account with the given
+	// username follows, or ErrUnsupportedPlatform if the platform doesn't list
+	// them.
+	ListFollowing(ctx context.Context, username string) ([]Follower, error)
+
+	// GetMetrics returns the current counts of the account with the given username.
+	GetMetrics(ctx context.Context, username string) (*AccountMetrics, error)
+}
+
+// IDProvider is implemented by Providers that can look accounts up by their
+// ID, which unlike their username doesn't change when the account is renamed.
+type IDProvider interface {
+	// GetMetricsByID returns the current counts of the account with the given ID.
+	GetMetricsByID(ctx context.Context, id string) (*AccountMetrics, error)
+}
+
+// StargazerProvider is implemented by Providers of platforms that host
+// repositories which accounts can star, such as GitHub.
+type StargazerProvider interface {
+	// ListStargazers returns the accounts that have starred the repository
+	// with the given name that belongs to the account with the given username.
+	ListStargazers(ctx context.Context, username string, repository string) ([]Follower, error)
+}
+
+// ProviderRegistry finds the Provider of each supported platform.
+type ProviderRegistry interface {
+	// Provider returns the Provider of the given platform, or
+	// ErrUnsupportedPlatform if no Provider is registered for it.
+	Provider(platform string) (Provider, error)
+
+	// Platforms returns the names of every platform a Provider is registered
+	// for, in alphabetical order.
+	Platforms() []string
+}
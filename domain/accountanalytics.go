@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNoAccountMetrics is returned when an account can't be analyzed because
+// no metrics of it with a visible number of followers have been recorded.
+var ErrNoAccountMetrics = errors.New("no account metrics")
+
+// AccountAnalytics summarizes how the followers of an account changed each day.
+type AccountAnalytics struct {
+	From      time.Time       `json:"from"`      // Day of the first recorded metrics the analytics are based on.
+	To        time.Time       `json:"to"`        // Day of the last recorded metrics the analytics are based on.
+	Window    int             `json:"window"`    // Number of days growth rates are computed over.
+	Days      []DailyGrowth   `json:"days"`      // Growth of each day after From that followers were counted on.
+	Anomalies []GrowthAnomaly `json:"anomalies"` // Days whose net change stands out from the rest.
+}
+
+// DailyGrowth describes the change in followers of an account on one day.
+type DailyGrowth struct {
+	Date       time.Time `json:"date"`        // Midnight UTC of the day.
+	Followers  int64     `json:"followers"`   // Followers at the end of the day.
+	NetChange  int64     `json:"net_change"`  // Change in followers since the previous day they were counted on.
+	GrowthRate float64   `json:"growth_rate"` // Fractional change in followers over the preceding window.
+}
+
+// AnomalyKind distinguishes unusual gains in followers from unusual losses.
+type AnomalyKind string
+
+const (
+	// SpikeAnomaly is an unusually large gain in followers.
+	SpikeAnomaly AnomalyKind = "spike"
+
+	// DropAnomaly is an unusually large loss of followers.
+	DropAnomaly AnomalyKind = "drop"
+)
+
+// GrowthAnomaly describes a day whose net change in followers is an outlier.
+type GrowthAnomaly struct {
+	Date      time.Time   `json:"date"`       // Midnight UTC of the day.
+	Kind      AnomalyKind `json:"kind"`       // Whether followers were gained or lost.
+	NetChange int64       `json:"net_change"` // Change in followers on the day.
+	Score     float64     `json:"score"`      // Modified z-score of the net change.
+}
+
+// AccountAnalyticsService analyzes the growth of tracked accounts.
+type AccountAnalyticsService interface {
+	// Get analyzes the metrics of the tracked account with the given ID that
+	// were recorded between from and to inclusive, computing growth rates over
+	// the given number of days. A window of zero or less uses a week. Metrics
+	// that hide the number of followers are left out. If no other metrics
+	// were recorded, ErrNoAccountMetrics is returned.
+	Get(ctx context.Context, id int64, from time.Time, to time.Time, window int) (*AccountAnalytics, error)
+}
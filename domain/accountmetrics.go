@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrInvalidResolution is returned when account metrics are requested at a
+// resolution that is not supported.
+var ErrInvalidResolution = errors.New("invalid resolution")
+
+// AccountMetrics represents the public counts of an account at a point in time.
+// Counts that the platform of the account does not have are zero.
+type AccountMetrics struct {
+	Platform        string    `json:"platform"`                   // Platform the account belongs to, such as "twitter".
+	AccountID       string    `json:"account_id"`                 // ID of the account on its platform.
+	RecordedAt      time.Time `json:"recorded_at"`                // Time the counts were retrieved.
+	Followers       int64     `json:"followers_count"`            // Number of users who follow the account.
+	Following       int64     `json:"following_count"`            // Number of users the account follows.
+	Tweets          int64     `json:"tweet_count"`                // Number of posts the account has made.
+	Listed          int64     `json:"listed_count"`               // Number of lists the account is a member of.
+	Views           int64     `json:"view_count,omitempty"`       // Number of times the posts of the account have been viewed.
+	FollowersHidden bool      `json:"followers_hidden,omitempty"` // Whether the account hides its number of followers, leaving Followers zero.
+}
+
+// Resolution determines how AccountMetrics are downsampled.
+type Resolution string
+
+const (
+	// RawResolution keeps every recorded AccountMetrics.
+	RawResolution Resolution = "raw"
+
+	// HourlyResolution keeps the last AccountMetrics recorded in each hour.
+	HourlyResolution Resolution = "hourly"
+
+	// DailyResolution keeps the last AccountMetrics recorded in each day.
+	DailyResolution Resolution = "daily"
+
+	// WeeklyResolution keeps the last AccountMetrics recorded in each week,
+	// with weeks starting on Monday.
+	WeeklyResolution Resolution = "weekly"
+)
+
+// AccountMetricsService presents the recorded metrics of tracked accounts.
+type AccountMetricsService interface {
+	// Get returns the metrics of the tracked account with the given ID that
+	// were recorded between from and to inclusive, ordered from oldest to
+	// newest and downsampled to the given resolution. Downsampled metrics are
+	// timestamped with the start of their bucket, in UTC.
+	Get(ctx context.Context, id int64, from time.Time, to time.Time, resolution Resolution) ([]AccountMetrics, error)
+
+	// Record retrieves the current metrics of the account with the given
+	// username from the Provider of the given platform, and stores them.
+	Record(ctx context.Context, platform string, username string) (*AccountMetrics, error)
+
+	// RecordByID is like Record, but looks the account up by the given ID if
+	// the Provider is an IDProvider, so that the same account is recorded
+	// after it is renamed. Otherwise it is looked up by the given username.
+	RecordByID(ctx context.Context, platform string, id string, username string) (*AccountMetrics, error)
+}
+
+// AccountMetricsRepository stores the metrics recorded for accounts.
+type AccountMetricsRepository interface {
+	// Save stores the given metrics.
+	Save(ctx context.Context, metrics *AccountMetrics) error
+
+	// List returns every metrics of the account recorded between from and to
+	// inclusive, ordered from oldest to newest.
+	List(ctx context.Context, platform string, accountID string, from time.Time, to time.Time) ([]AccountMetrics, error)
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNoFollowerSnapshot is returned when there is no earlier FollowerSnapshot
+// of an account to compare its current followers against.
+var ErrNoFollowerSnapshot = errors.New("no follower snapshot recorded")
+
+// FollowerChanges represents the followers an account gained and lost between
+// two FollowerSnapshots.
+type FollowerChanges struct {
+	From   time.Time     `json:"from"`   // Time the earlier snapshot was taken.
+	To     time.Time     `json:"to"`     // Time the later snapshot was taken.
+	Gained []TwitterUser `json:"gained"` // Users who followed the account between the snapshots.
+	Lost   []TwitterUser `json:"lost"`   // Users who unfollowed the account between the snapshots.
+}
+
+type FollowerDiffService interface {
+	RecordSnapshot(ctx context.Context, username string) (*FollowerSnapshot, error)
+
+	// RecordSnapshotByID is like RecordSnapshot, but looks the account up by
+	// its ID, which unlike its username doesn't change when it is renamed.
+	RecordSnapshotByID(ctx context.Context, id string) (*FollowerSnapshot, error)
+
+	// GetChanges compares the followers of the account in its most recent
+	// snapshot with those it had at since, without taking a new snapshot. The followers gained and lost are looked up as the
+	// TwitterLookupOptions, if given, select.
+	GetChanges(ctx context.Context, username string, since time.Time, opts ...TwitterLookupOptions) (*FollowerChanges, error)
+}
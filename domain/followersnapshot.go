@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// FollowerSnapshot represents the followers an account had at a point in time.
+type FollowerSnapshot struct {
+	Platform    string    `json:"platform"`     // Platform the account belongs to, such as "twitter".
+	AccountID   string    `json:"account_id"`   // ID of the account on its platform.
+	TakenAt     time.Time `json:"taken_at"`     // Time the followers were retrieved.
+	FollowerIDs []string  `json:"follower_ids"` // Platform IDs of every follower of the account.
+}
+
+// FollowerSnapshotRepository stores FollowerSnapshots for tracked accounts.
+type FollowerSnapshotRepository interface {
+	// Save stores the given snapshot.
+	Save(ctx context.Context, snapshot *FollowerSnapshot) error
+
+	// At returns the most recent snapshot of the account taken at or before the
+	// given time. If no such snapshot exists, nil is returned.
+	At(ctx context.Context, platform string, accountID string, t time.Time) (*FollowerSnapshot, error)
+
+	// List returns every snapshot of the account taken between from and to
+	// inclusive, ordered from oldest to newest.
+	List(ctx context.Context, platform string, accountID string, from time.Time, to time.Time) ([]FollowerSnapshot, error)
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+)
+
+// RateLimit represents how many more requests may be made to a rate limited
+// API before it stops accepting them.
+type RateLimit struct {
+	Remaining int64     `json:"remaining"` // Requests that may still be made in the current window.
+	Reset     time.Time `json:"reset"`     // Time the current window ends.
+}
+
+// Exhausted determines whether no more requests may be made until the rate
+// limit resets.
+func (r RateLimit) Exhausted(now time.Time) bool {
+	return r.Remaining == 0 && now.Before(r.Reset)
+}
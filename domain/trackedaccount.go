@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrTrackedAccountNotFound is returned when a TrackedAccount does not exist.
+	ErrTrackedAccountNotFound = errors.New("tracked account not found")
+
+	// ErrTrackedAccountExists is returned when an account is already tracked.
+	ErrTrackedAccountExists = errors.New("account is already tracked")
+
+	// ErrUnsupportedPlatform is returned when accounts on a platform cannot be tracked.
+	ErrUnsupportedPlatform = errors.New("platform is not supported")
+)
+
+// TrackedAccount represents an account on a platform whose followers the
+// server keeps track of.
+type TrackedAccount struct {
+	ID             int64        `json:"id"`
+	Platform       string       `json:"platform"`         // Platform the account belongs to, such as "twitter".
+	PlatformUserID string       `json:"platform_user_id"` // ID of the account on its platform.
+	Username       string       `json:"username"`         // Username of the account when it was added.
+	AddedAt        time.Time    `json:"added_at"`
+	Poll           PollSettings `json:"poll"`
+}
+
+// PollSettings configures how the followers of a TrackedAccount are polled.
+type PollSettings struct {
+	Enabled  bool     `json:"enabled"`  // Whether the account is polled at all.
+	Interval Duration `json:"interval"` // Time between polls. The scheduler's default is used when zero.
+}
+
+// Duration is a time.Duration that is represented in JSON as a string such as "1h30m".
+type Duration time.Duration
+
+// MarshalJSON encodes the duration as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON decodes a duration from a string.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+type TrackedAccountService interface {
+	Track(ctx context.Context, platform string, username string, poll PollSettings) (*TrackedAccount, error)
+	Get(ctx context.Context, id int64) (*TrackedAccount, error)
+	List(ctx context.Context) ([]TrackedAccount, error)
+	Untrack(ctx context.Context, id int64) error
+}
+
+// TrackedAccountRepository stores the accounts tracked by the server.
+type TrackedAccountRepository interface {
+	// Add stores the given account and assigns it an ID. If an account with the
+	// same platform and platform user ID is already stored, ErrTrackedAccountExists
+	// is returned.
+	Add(ctx context.Context, account *TrackedAccount) error
+
+	// Get returns the account with the given ID, or ErrTrackedAccountNotFound.
+	Get(ctx context.Context, id int64) (*TrackedAccount, error)
+
+	// List returns every stored account ordered by ID.
+	List(ctx context.Context) ([]TrackedAccount, error)
+
+	// Delete removes the account with the given ID, or returns ErrTrackedAccountNotFound.
+	Delete(ctx context.Context, id int64) error
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrTwitterConnectionNotFound is returned when a Twitter account has not been connected.
+	ErrTwitterConnectionNotFound = errors.New("twitter account is not connected")
+
+	// ErrInvalidAuthorizationState is returned when an authorization callback
+	// does not belong to an authorization that is in progress.
+	ErrInvalidAuthorizationState = errors.New("authorization state is invalid or expired")
+
+	// ErrInvalidSession is returned when a session token was not issued by
+	// followrs or has expired.
+	ErrInvalidSession = errors.New("session is invalid or expired")
+)
+
+// TwitterConnection is a Twitter account that its owner has connected to
+// followrs, allowing requests to be made on its behalf.
+type TwitterConnection struct {
+	UserID       string    `json:"user_id"`
+	Username     string    `json:"username"`
+	AccessToken  string    `json:"-"`
+	RefreshToken string    `json:"-"`
+	Expiry       time.Time `json:"-"` // Zero if the access token doesn't expire.
+	Scopes       []string  `json:"scopes"`
+	ConnectedAt  time.Time `json:"connected_at"`
+}
+
+type TwitterConnectService interface {
+	// Authorize begins connecting a Twitter account and returns the URL that
+	// its owner should be sent to in order to authorize the connection, along
+	// with the state that identifies the authorization in the callback.
+	Authorize(ctx context.Context) (authorizationURL string, state string, err error)
+
+	// Complete finishes connecting the Twitter account whose owner authorized
+	// the connection, given the state and code passed to the callback.
+	Complete(ctx context.Context, state string, code string) (*TwitterConnection, error)
+
+	// OnBehalfOf returns a copy of ctx whose requests to Twitter are made on
+	// behalf of the connected account with the given user ID.
+	OnBehalfOf(ctx context.Context, userID string) (context.Context, error)
+
+	// Session returns a token that identifies the owner of the connected
+	// account with the given user ID in their later requests, until it expires.
+	Session(ctx context.Context, userID string) (string, error)
+
+	// SessionUser returns the user ID of the connected account whose owner
+	// the session token was issued to.
+	SessionUser(ctx context.Context, token string) (string, error)
+}
+
+type TwitterConnectionRepository interface {
+	// Save stores the connection, replacing any connection of the same account.
+	Save(ctx context.Context, connection *TwitterConnection) error
+
+	// Get returns the connection of the account with the given user ID.
+	Get(ctx context.Context, userID string) (*TwitterConnection, error)
+}
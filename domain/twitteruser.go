@@ -1,17 +1,79 @@
 package domain
 
-import "github.com/jake-hansen/followrs/repositories/apis/twitter"
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrUnknownUserField is returned when an optional field that doesn't exist is
+// selected for the Twitter users being looked up.
+var ErrUnknownUserField = errors.New("unknown user field")
 
 type TwitterUser struct {
-	ID string `json:"id"`
-	Name string `json:"name"`
-	Username string `json:"username"`
+	ID              string                `json:"id"`
+	Name            string                `json:"name"`
+	Username        string                `json:"username"`
+	CreatedAt       *time.Time            `json:"created_at,omitempty"`
+	Description     string                `json:"description,omitempty"`
+	Location        string                `json:"location,omitempty"`
+	ProfileImageURL string                `json:"profile_image_url,omitempty"`
+	Protected       bool                  `json:"protected,omitempty"`
+	PublicMetrics   *TwitterPublicMetrics `json:"public_metrics,omitempty"`
+	URL             string                `json:"url,omitempty"`
+	Verified        bool                  `json:"verified,omitempty"`
 }
 
-type TwitterService interface {
-	GetUser(username string) (*TwitterUser, error)
+// TwitterPublicMetrics contains the public activity counts of a Twitter user.
+type TwitterPublicMetrics struct {
+	Followers int64 `json:"followers_count"` // Number of users who follow the user.
+	Following int64 `json:"following_count"` // Number of users the user follows.
+	Tweets    int64 `json:"tweet_count"`     // Number of Tweets, including Retweets, the user has posted.
+	Listed    int64 `json:"listed_count"`    // Number of lists the user is a member of.
+}
+
+// TwitterLookupOptions changes how Twitter users are looked up.
+type TwitterLookupOptions struct {
+	// Fields lists the optional fields looked up for each user, such as
+	// public_metrics or description. All of them are looked up if Fields is
+	// nil, and none of them if it is empty.
+	Fields []string
+}
+
+// TwitterLookupFailure describes a Twitter user that could not be returned by
+// a lookup of several users.
+type TwitterLookupFailure struct {
+	Value string // Username or ID that was looked up.
+	Err   error  // Reason the user could not be looked up.
 }
 
-type TwitterRepository interface {
-	GetUser(username string) (*twitter.User, error)
+// TwitterUsers is the result of looking up several Twitter users at once.
+// Users that could not be looked up are described by Failures rather than
+// failing the whole lookup.
+type TwitterUsers struct {
+	Users    []TwitterUser
+	Failures []TwitterLookupFailure
+}
+
+// TwitterRelationships describes how the followers of a Twitter user relate to
+// the users that user follows.
+type TwitterRelationships struct {
+	Mutuals      []TwitterUser `json:"mutuals"`       // Users who follow and are followed by the user.
+	Fans         []TwitterUser `json:"fans"`          // Users who follow the user but are not followed back.
+	NonFollowers []TwitterUser `json:"non_followers"` // Users followed by the user who do not follow back.
+}
+
+// TwitterService looks up Twitter users. The users returned by each lookup
+// have the optional fields selected by the TwitterLookupOptions, if given.
+type TwitterService interface {
+	GetUser(ctx context.Context, username string, opts ...TwitterLookupOptions) (*TwitterUser, error)
+	GetUserByID(ctx context.Context, id string, opts ...TwitterLookupOptions) (*TwitterUser, error)
+	GetUsers(ctx context.Context, usernames []string, opts ...TwitterLookupOptions) (*TwitterUsers, error)
+	GetUsersByID(ctx context.Context, ids []string, opts ...TwitterLookupOptions) (*TwitterUsers, error)
+	GetAuthenticatedUser(ctx context.Context) (*TwitterUser, error)
+	GetFollowers(ctx context.Context, username string, opts ...TwitterLookupOptions) ([]TwitterUser, error)
+	GetFollowersByID(ctx context.Context, id string, opts ...TwitterLookupOptions) ([]TwitterUser, error)
+	GetFollowersRateLimit() RateLimit
+	GetFollowing(ctx context.Context, username string, opts ...TwitterLookupOptions) ([]TwitterUser, error)
+	GetRelationships(ctx context.Context, username string, opts ...TwitterLookupOptions) (*TwitterRelationships, error)
 }
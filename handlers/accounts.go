@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/domain"
+)
+
+// AccountsHandler presents the accounts tracked by the server.
+type AccountsHandler struct {
+	TrackedAccountService   domain.TrackedAccountService   // TrackedAccountService to use for performing operations on domain.
+	AccountMetricsService   domain.AccountMetricsService   // AccountMetricsService to use for retrieving recorded metrics.
+	AccountAnalyticsService domain.AccountAnalyticsService // AccountAnalyticsService to use for analyzing recorded metrics.
+	MinPollInterval         time.Duration                  // Shortest interval accounts may be polled at.
+}
+
+// trackAccountRequest is the body of a request to track an account.
+type trackAccountRequest struct {
+	Platform string               `json:"platform" binding:"required"`
+	Username string               `json:"username" binding:"required"`
+	Poll     *domain.PollSettings `json:"poll"` // Polling is enabled at the default interval when omitted.
+}
+
+// NewAccountsHandler initializes the endpoints for tracked accounts. Accounts
+// can't be tracked with a poll interval shorter than minPollInterval.
+func NewAccountsHandler(parentGroup *gin.RouterGroup, service domain.TrackedAccountService, metricsService domain.AccountMetricsService, analyticsService domain.AccountAnalyticsService, minPollInterval time.Duration) {
+	handler := &AccountsHandler{
+		TrackedAccountService:   service,
+		AccountMetricsService:   metricsService,
+		AccountAnalyticsService: analyticsService,
+		MinPollInterval:         minPollInterval,
+	}
+
+	accountsGroup := parentGroup.Group("accounts")
+	{
+		accountsGroup.POST("", handler.Track)                  // POST /accounts
+		accountsGroup.GET("", handler.List)                    // GET /accounts
+		accountsGroup.GET("/:id", handler.Get)                 // GET /accounts/:id
+		accountsGroup.DELETE("/:id", handler.Untrack)          // DELETE /accounts/:id
+		accountsGroup.GET("/:id/metrics", handler.Metrics)     // GET /accounts/:id/metrics
+		accountsGroup.GET("/:id/analytics", handler.Analytics) // GET /accounts/:id/analytics
+	}
+}
+
+// Track starts tracking the account described by the request body. A poll
+// interval of zero selects the scheduler's default; any other interval must be
+// at least MinPollInterval.
+func (a *AccountsHandler) Track(c *gin.Context) {
+	var request trackAccountRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		apiError := &apperrors.APIError{
+			Status:  http.StatusBadRequest,
+			Err:     err,
+			Message: "the request must contain a platform and username",
+			Code:    "invalid_body",
+		}
+		c.Error(apiError).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	poll := domain.PollSettings{Enabled: true}
+	if request.Poll != nil {
+		poll = *request.Poll
+	}
+	if interval := time.Duration(poll.Interval); interval < 0 || (interval > 0 && interval < a.MinPollInterval) {
+		apiError := &apperrors.APIError{
+			Status:  http.StatusBadRequest,
+			Err:     fmt.Errorf("poll interval of %s", interval),
+			Message: fmt.Sprintf("the poll interval [%s] must be at least %s", interval, a.MinPollInterval),
+			Code:    "invalid_parameter",
+		}
+		c.Error(apiError).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	account, err := a.TrackedAccountService.Track(c.Request.Context(), request.Platform, request.Username, poll)
+	if err == nil {
+		c.JSON(http.StatusCreated, *account)
+		return
+	}
+
+	var apiError error
+	switch {
+	case errors.Is(err, domain.ErrUnsupportedPlatform):
+		apiError = &apperrors.APIError{
+			Status:  http.StatusBadRequest,
+			Err:     err,
+			Message: fmt.Sprintf("accounts on the platform [%s] cannot be tracked", request.Platform),
+			Code:    "unsupported_platform",
+		}
+	case errors.Is(err, domain.ErrTrackedAccountExists):
+		apiError = &apperrors.APIError{
+			Status:  http.StatusConflict,
+			Err:     err,
+			Message: fmt.Sprintf("the user [%s] is already tracked", request.Username),
+			Code:    "already_tracked",
+		}
+	default:
+		apiError = userError(request.Username, err)
+	}
+	c.Error(apiError).SetType(gin.ErrorTypePublic)
+}
+
+// List retrieves every tracked account.
+func (a *AccountsHandler) List(c *gin.Context) {
+	accounts, err := a.TrackedAccountService.List(c.Request.Context())
+	if err == nil {
+		c.JSON(http.StatusOK, accounts)
+	} else {
+		apiError := &apperrors.APIError{
+			Status:  http.StatusInternalServerError,
+			Err:     err,
+			Message: "An error occurred while retrieving the tracked accounts",
+		}
+		c.Error(apiError).SetType(gin.ErrorTypePublic)
+	}
+}
+
+// Get retrieves the tracked account with the ID given in the path.
+func (a *AccountsHandler) Get(c *gin.Context) {
+	id, ok := accountID(c)
+	if !ok {
+		return
+	}
+
+	account, err := a.TrackedAccountService.Get(c.Request.Context(), id)
+	if err == nil {
+		c.JSON(http.StatusOK, *account)
+	} else {
+		c.Error(trackedAccountError(id, err)).SetType(gin.ErrorTypePublic)
+	}
+}
+
+// Untrack stops tracking the account with the ID given in the path.
+func (a *AccountsHandler) Untrack(c *gin.Context) {
+	id, ok := accountID(c)
+	if !ok {
+		return
+	}
+
+	err := a.TrackedAccountService.Untrack(c.Request.Context(), id)
+	if err == nil {
+		c.Status(http.StatusNoContent)
+	} else {
+		c.Error(trackedAccountError(id, err)).SetType(gin.ErrorTypePublic)
+	}
+}
+
+// Metrics retrieves the metrics recorded for the tracked account with the ID
+// given in the path. The from and to query parameters bound the time the
+// metrics were recorded, and may be RFC 3339 timestamps or durations, such as
+// 24h, before the current time. They default to the beginning of time and the
+// current time. The resolution query parameter may be hourly, daily or weekly
+// to keep only the last metrics recorded in each bucket.
+func (a *AccountsHandler) Metrics(c *gin.Context) {
+	id, ok := accountID(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	from, ok := timeParameter(c, "from", time.Unix(0, 0), now)
+	if !ok {
+		return
+	}
+	to, ok := timeParameter(c, "to", now, now)
+	if !ok {
+		return
+	}
+
+	resolution := c.Query("resolution")
+	metrics, err := a.AccountMetricsService.Get(c.Request.Context(), id, from, to, domain.Resolution(resolution))
+	switch {
+	case err == nil:
+		if metrics == nil {
+			metrics = []domain.AccountMetrics{}
+		}
+		c.JSON(http.StatusOK, metrics)
+	case errors.Is(err, domain.ErrInvalidResolution):
+		apiError := &apperrors.APIError{
+			Status:  http.StatusBadRequest,
+			Err:     err,
+			Message: fmt.Sprintf("the resolution parameter [%s] must be raw, hourly, daily or weekly", resolution),
+			Code:    "invalid_parameter",
+		}
+		c.Error(apiError).SetType(gin.ErrorTypePublic)
+	default:
+		c.Error(trackedAccountError(id, err)).SetType(gin.ErrorTypePublic)
+	}
+}
+
+// Analytics reports the daily growth of the tracked account with the ID given
+// in the path, and the days it gained or lost an unusual number of followers.
+// The from and to query parameters bound the metrics analyzed as they do for
+// Metrics. The window query parameter is the number of days growth rates are
+// computed over, a week by default.
+func (a *AccountsHandler) Analytics(c *gin.Context) {
+	id, ok := accountID(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	from, ok := timeParameter(c, "from", time.Unix(0, 0), now)
+	if !ok {
+		return
+	}
+	to, ok := timeParameter(c, "to", now, now)
+	if !ok {
+		return
+	}
+
+	window := 0
+	if value := c.Query("window"); value != "" {
+		var err error
+		window, err = strconv.Atoi(value)
+		if err == nil && window < 1 {
+			err = fmt.Errorf("window of %d days", window)
+		}
+		if err != nil {
+			apiError := &apperrors.APIError{
+				Status:  http.StatusBadRequest,
+				Err:     err,
+				Message: fmt.Sprintf("the window parameter [%s] is not a positive number of days", value),
+				Code:    "invalid_parameter",
+			}
+			c.Error(apiError).SetType(gin.ErrorTypePublic)
+			return
+		}
+	}
+
+	analytics, err := a.AccountAnalyticsService.Get(c.Request.Context(), id, from, to, window)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, *analytics)
+	case errors.Is(err, domain.ErrNoAccountMetrics):
+		apiError := &apperrors.APIError{
+			Status:  http.StatusNotFound,
+			Err:     err,
+			Message: fmt.Sprintf("no metrics of the account [%d] have been recorded in the given time, try again later", id),
+			Code:    "no_metrics",
+		}
+		c.Error(apiError).SetType(gin.ErrorTypePublic)
+	default:
+		c.Error(trackedAccountError(id, err)).SetType(gin.ErrorTypePublic)
+	}
+}
+
+// timeParameter parses the query parameter with the given name as a timestamp
+// or a duration before now, returning def if it is omitted. If it is invalid,
+// an error is reported to the client and false is returned.
+func timeParameter(c *gin.Context, name string, def time.Time, now time.Time) (time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return def, true
+	}
+
+	t, err := parseSince(value, now)
+	if err != nil {
+		apiError := &apperrors.APIError{
+			Status:  http.StatusBadRequest,
+			Err:     err,
+			Message: fmt.Sprintf("the %s parameter [%s] is not a timestamp or duration", name, value),
+			Code:    "invalid_parameter",
+		}
+		c.Error(apiError).SetType(gin.ErrorTypePublic)
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// accountID parses the account ID in the path. If the ID is invalid, an
+// error is reported to the client and false is returned.
+func accountID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		apiError := &apperrors.APIError{
+			Status:  http.StatusBadRequest,
+			Err:     err,
+			Message: fmt.Sprintf("the account id [%s] is not valid", c.Param("id")),
+			Code:    "invalid_parameter",
+		}
+		c.Error(apiError).SetType(gin.ErrorTypePublic)
+		return 0, false
+	}
+	return id, true
+}
+
+// trackedAccountError converts an error returned while retrieving a tracked
+// account into an error suitable for the client.
+func trackedAccountError(id int64, err error) error {
+	if errors.Is(err, domain.ErrTrackedAccountNotFound) {
+		return &apperrors.APIError{
+			Status:  http.StatusNotFound,
+			Err:     err,
+			Message: fmt.Sprintf("the account [%d] is not tracked", id),
+		}
+	}
+	return err
+}
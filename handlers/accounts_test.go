@@ -0,0 +1,298 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/handlers"
+	"github.com/jake-hansen/followrs/middleware"
+	"github.com/jake-hansen/followrs/services/mocks"
+)
+
+func newAccountsRouter(service domain.TrackedAccountService, metricsService domain.AccountMetricsService, analyticsService domain.AccountAnalyticsService) *gin.Engine {
+	router := gin.Default()
+	router.Use(middleware.PublicErrorHandler())
+	handlers.NewAccountsHandler(router.Group("test"), service, metricsService, analyticsService, time.Minute)
+	return router
+}
+
+func TestTrack(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		poll := domain.PollSettings{Enabled: true, Interval: domain.Duration(time.Hour)}
+		account := &domain.TrackedAccount{ID: 1, Platform: "twitter", PlatformUserID: "2", Username: "test", Poll: poll}
+
+		mockService := new(mocks.TrackedAccountService)
+		mockService.On("Track", mock.Anything, "twitter", "test", poll).Return(account, nil)
+		router := newAccountsRouter(mockService, nil, nil)
+
+		body := `{"platform": "twitter", "username": "test", "poll": {"enabled": true, "interval": "1h"}}`
+		req, err := http.NewRequest("POST", "/test/accounts", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var retrievedAccount domain.TrackedAccount
+		json.Unmarshal(w.Body.Bytes(), &retrievedAccount)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, account.PlatformUserID, retrievedAccount.PlatformUserID)
+		assert.Equal(t, poll, retrievedAccount.Poll)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("polling-enabled-by-default", func(t *testing.T) {
+		mockService := new(mocks.TrackedAccountService)
+		mockService.On("Track", mock.Anything, "twitter", "test", domain.PollSettings{Enabled: true}).Return(&domain.TrackedAccount{}, nil)
+		router := newAccountsRouter(mockService, nil, nil)
+
+		req, _ := http.NewRequest("POST", "/test/accounts", strings.NewReader(`{"platform": "twitter", "username": "test"}`))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	for _, interval := range []string{"-1h", "1ns", "59s"} {
+		t.Run("invalid-interval-"+interval, func(t *testing.T) {
+			mockService := new(mocks.TrackedAccountService)
+			router := newAccountsRouter(mockService, nil, nil)
+
+			body := fmt.Sprintf(`{"platform": "twitter", "username": "test", "poll": {"enabled": true, "interval": %q}}`, interval)
+			req, _ := http.NewRequest("POST", "/test/accounts", strings.NewReader(body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "invalid_parameter")
+			mockService.AssertNotCalled(t, "Track", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("missing-username", func(t *testing.T) {
+		mockService := new(mocks.TrackedAccountService)
+		router := newAccountsRouter(mockService, nil, nil)
+
+		req, _ := http.NewRequest("POST", "/test/accounts", strings.NewReader(`{"platform": "twitter"}`))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "Track")
+	})
+
+	t.Run("already-tracked", func(t *testing.T) {
+		mockService := new(mocks.TrackedAccountService)
+		mockService.On("Track", mock.Anything, "twitter", "test", domain.PollSettings{Enabled: true}).
+			Return(nil, fmt.Errorf("could not track test on twitter: %w", domain.ErrTrackedAccountExists))
+		router := newAccountsRouter(mockService, nil, nil)
+
+		req, _ := http.NewRequest("POST", "/test/accounts", strings.NewReader(`{"platform": "twitter", "username": "test"}`))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestUntrack(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(mocks.TrackedAccountService)
+		mockService.On("Untrack", mock.Anything, int64(1)).Return(nil)
+		router := newAccountsRouter(mockService, nil, nil)
+
+		req, _ := http.NewRequest("DELETE", "/test/accounts/1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("not-tracked", func(t *testing.T) {
+		mockService := new(mocks.TrackedAccountService)
+		mockService.On("Untrack", mock.Anything, int64(1)).Return(domain.ErrTrackedAccountNotFound)
+		router := newAccountsRouter(mockService, nil, nil)
+
+		req, _ := http.NewRequest("DELETE", "/test/accounts/1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid-id", func(t *testing.T) {
+		mockService := new(mocks.TrackedAccountService)
+		router := newAccountsRouter(mockService, nil, nil)
+
+		req, _ := http.NewRequest("DELETE", "/test/accounts/abc", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "Untrack")
+	})
+}
+
+func TestMetrics(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		from := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2021, time.March, 8, 0, 0, 0, 0, time.UTC)
+		metrics := []domain.AccountMetrics{{Platform: "twitter", AccountID: "2", RecordedAt: from, Followers: 10}}
+
+		mockMetrics := new(mocks.AccountMetricsService)
+		mockMetrics.On("Get", mock.Anything, int64(1), from, to, domain.DailyResolution).Return(metrics, nil)
+		router := newAccountsRouter(new(mocks.TrackedAccountService), mockMetrics, nil)
+
+		req, _ := http.NewRequest("GET", "/test/accounts/1/metrics?from=2021-03-01T00:00:00Z&to=2021-03-08T00:00:00Z&resolution=daily", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var retrievedMetrics []domain.AccountMetrics
+		json.Unmarshal(w.Body.Bytes(), &retrievedMetrics)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, metrics, retrievedMetrics)
+		mockMetrics.AssertExpectations(t)
+	})
+
+	t.Run("none-recorded", func(t *testing.T) {
+		mockMetrics := new(mocks.AccountMetricsService)
+		mockMetrics.On("Get", mock.Anything, int64(1), mock.Anything, mock.Anything, domain.Resolution("")).Return(nil, nil)
+		router := newAccountsRouter(new(mocks.TrackedAccountService), mockMetrics, nil)
+
+		req, _ := http.NewRequest("GET", "/test/accounts/1/metrics", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[]`, w.Body.String())
+	})
+
+	t.Run("invalid-resolution", func(t *testing.T) {
+		mockMetrics := new(mocks.AccountMetricsService)
+		mockMetrics.On("Get", mock.Anything, int64(1), mock.Anything, mock.Anything, domain.Resolution("monthly")).Return(nil, domain.ErrInvalidResolution)
+		router := newAccountsRouter(new(mocks.TrackedAccountService), mockMetrics, nil)
+
+		req, _ := http.NewRequest("GET", "/test/accounts/1/metrics?resolution=monthly", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid-from", func(t *testing.T) {
+		mockMetrics := new(mocks.AccountMetricsService)
+		router := newAccountsRouter(new(mocks.TrackedAccountService), mockMetrics, nil)
+
+		req, _ := http.NewRequest("GET", "/test/accounts/1/metrics?from=yesterday", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockMetrics.AssertNotCalled(t, "Get")
+	})
+
+	t.Run("not-tracked", func(t *testing.T) {
+		mockMetrics := new(mocks.AccountMetricsService)
+		mockMetrics.On("Get", mock.Anything, int64(1), mock.Anything, mock.Anything, domain.Resolution("")).Return(nil, domain.ErrTrackedAccountNotFound)
+		router := newAccountsRouter(new(mocks.TrackedAccountService), mockMetrics, nil)
+
+		req, _ := http.NewRequest("GET", "/test/accounts/1/metrics", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestAnalytics(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		from := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2021, time.March, 8, 0, 0, 0, 0, time.UTC)
+		analytics := &domain.AccountAnalytics{
+			From:      from,
+			To:        to,
+			Window:    3,
+			Days:      []domain.DailyGrowth{{Date: to, Followers: 10, NetChange: 2, GrowthRate: 0.25}},
+			Anomalies: []domain.GrowthAnomaly{{Date: to, Kind: domain.SpikeAnomaly, NetChange: 2, Score: 4}},
+		}
+
+		mockAnalytics := new(mocks.AccountAnalyticsService)
+		mockAnalytics.On("Get", mock.Anything, int64(1), from, to, 3).Return(analytics, nil)
+		router := newAccountsRouter(new(mocks.TrackedAccountService), nil, mockAnalytics)
+
+		req, _ := http.NewRequest("GET", "/test/accounts/1/analytics?from=2021-03-01T00:00:00Z&to=2021-03-08T00:00:00Z&window=3", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var retrievedAnalytics domain.AccountAnalytics
+		json.Unmarshal(w.Body.Bytes(), &retrievedAnalytics)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, *analytics, retrievedAnalytics)
+		mockAnalytics.AssertExpectations(t)
+	})
+
+	t.Run("default-window", func(t *testing.T) {
+		mockAnalytics := new(mocks.AccountAnalyticsService)
+		mockAnalytics.On("Get", mock.Anything, int64(1), mock.Anything, mock.Anything, 0).Return(&domain.AccountAnalytics{}, nil)
+		router := newAccountsRouter(new(mocks.TrackedAccountService), nil, mockAnalytics)
+
+		req, _ := http.NewRequest("GET", "/test/accounts/1/analytics", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockAnalytics.AssertExpectations(t)
+	})
+
+	for _, window := range []string{"0", "-1", "week"} {
+		t.Run(fmt.Sprintf("invalid-window-%s", window), func(t *testing.T) {
+			mockAnalytics := new(mocks.AccountAnalyticsService)
+			router := newAccountsRouter(new(mocks.TrackedAccountService), nil, mockAnalytics)
+
+			req, _ := http.NewRequest("GET", "/test/accounts/1/analytics?window="+window, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockAnalytics.AssertNotCalled(t, "Get")
+		})
+	}
+
+	t.Run("no-metrics", func(t *testing.T) {
+		mockAnalytics := new(mocks.AccountAnalyticsService)
+		mockAnalytics.On("Get", mock.Anything, int64(1), mock.Anything, mock.Anything, 0).Return(nil, domain.ErrNoAccountMetrics)
+		router := newAccountsRouter(new(mocks.TrackedAccountService), nil, mockAnalytics)
+
+		req, _ := http.NewRequest("GET", "/test/accounts/1/analytics", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "no_metrics")
+	})
+
+	t.Run("not-tracked", func(t *testing.T) {
+		mockAnalytics := new(mocks.AccountAnalyticsService)
+		mockAnalytics.On("Get", mock.Anything, int64(1), mock.Anything, mock.Anything, 0).Return(nil, domain.ErrTrackedAccountNotFound)
+		router := newAccountsRouter(new(mocks.TrackedAccountService), nil, mockAnalytics)
+
+		req, _ := http.NewRequest("GET", "/test/accounts/1/analytics", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/middleware"
+)
+
+// stateCookie is the name of the cookie that binds an authorization to the
+// browser that began it, so that a callback can't be completed by another.
+const stateCookie = "followrs_connect_state"
+
+// stateCookieMaxAge is how long the browser keeps the state cookie, which is
+// as long as the owner of an account has to authorize a connection.
+const stateCookieMaxAge = 10 * time.Minute
+
+// sessionCookieMaxAge is how long the browser keeps the session cookie.
+const sessionCookieMaxAge = 30 * 24 * time.Hour
+
+// ConnectHandler lets users connect their accounts on other platforms.
+type ConnectHandler struct {
+	TwitterConnectService domain.TwitterConnectService // TwitterConnectService to use for connecting Twitter accounts.
+	CallbackPath          string                       // Path that the state cookie is sent to.
+}
+
+// NewConnectHandler initializes the endpoints for connecting accounts.
+func NewConnectHandler(parentGroup *gin.RouterGroup, twitterConnectService domain.TwitterConnectService) {
+	connectGroup := parentGroup.Group("connect")
+	handler := &ConnectHandler{
+		TwitterConnectService: twitterConnectService,
+		CallbackPath:          connectGroup.BasePath() + "/twitter/callback",
+	}
+
+	{
+		connectGroup.GET("/twitter", handler.ConnectTwitter)                  // GET /connect/twitter
+		connectGroup.GET("/twitter/callback", handler.CompleteTwitterConnect) // GET /connect/twitter/callback
+	}
+}
+
+// ConnectTwitter redirects the user to Twitter to authorize the connection of
+// their account. The authorization's state is kept in a cookie so that the
+// callback can check that it is completed by the same browser.
+func (h *ConnectHandler) ConnectTwitter(c *gin.Context) {
+	authorizationURL, state, err := h.TwitterConnectService.Authorize(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(stateCookie, state, int(stateCookieMaxAge.Seconds()), h.CallbackPath, "", true, true)
+	c.Redirect(http.StatusFound, authorizationURL)
+}
+
+// CompleteTwitterConnect handles the user being redirected back from Twitter
+// after they authorized, or refused to authorize, the connection. Once the
+// connection is stored, the user is given a session cookie with which later
+// requests are made on behalf of their account.
+func (h *ConnectHandler) CompleteTwitterConnect(c *gin.Context) {
+	cookieState, _ := c.Cookie(stateCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(stateCookie, "", -1, h.CallbackPath, "", true, true)
+
+	if reason := c.Query("error"); reason != "" {
+		apiError := &apperrors.APIError{
+			Status:  http.StatusForbidden,
+			Err:     errors.New(reason),
+			Message: "the connection of the Twitter account was not authorized",
+			Code:    "authorization_denied",
+		}
+		c.Error(apiError).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	state, code := c.Query("state"), c.Query("code")
+	if state == "" || code == "" {
+		apiError := &apperrors.APIError{
+			Status:  http.StatusBadRequest,
+			Err:     errors.New("missing state or code"),
+			Message: "the state and code parameters are required",
+			Code:    "invalid_parameter",
+		}
+		c.Error(apiError).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(state), []byte(cookieState)) != 1 {
+		invalidStateError(c, errors.New("state does not match cookie"))
+		return
+	}
+
+	connection, err := h.TwitterConnectService.Complete(c.Request.Context(), state, code)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAuthorizationState) {
+			invalidStateError(c, err)
+		} else {
+			c.Error(err).SetType(gin.ErrorTypePublic)
+		}
+		return
+	}
+
+	session, err := h.TwitterConnectService.Session(c.Request.Context(), connection.UserID)
+	if err != nil {
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.SetCookie(middleware.SessionCookie, session, int(sessionCookieMaxAge.Seconds()), "/", "", true, true)
+	c.JSON(http.StatusOK, *connection)
+}
+
+// invalidStateError responds that the authorization being completed is not the
+// one that the user began.
+func invalidStateError(c *gin.Context, err error) {
+	apiError := &apperrors.APIError{
+		Status:  http.StatusBadRequest,
+		Err:     err,
+		Message: "the authorization is invalid or has expired, try connecting again",
+		Code:    "invalid_state",
+	}
+	c.Error(apiError).SetType(gin.ErrorTypePublic)
+}
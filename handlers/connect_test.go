@@ -0,0 +1,128 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/handlers"
+	"github.com/jake-hansen/followrs/middleware"
+	"github.com/jake-hansen/followrs/services/mocks"
+)
+
+func newConnectRouter(service domain.TwitterConnectService) *gin.Engine {
+	router := gin.Default()
+	router.Use(middleware.PublicErrorHandler())
+	handlers.NewConnectHandler(router.Group("test"), service)
+	return router
+}
+
+func TestConnectTwitter(t *testing.T) {
+	mockService := new(mocks.TwitterConnectService)
+	mockService.On("Authorize", mock.Anything).Return("https://twitter.com/i/oauth2/authorize?state=state", "state", nil)
+	router := newConnectRouter(mockService)
+
+	req, _ := http.NewRequest("GET", "/test/connect/twitter", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://twitter.com/i/oauth2/authorize?state=state", w.Header().Get("Location"))
+
+	cookie := responseCookie(w, "followrs_connect_state")
+	if assert.NotNil(t, cookie) {
+		assert.Equal(t, "state", cookie.Value)
+		assert.Equal(t, "/test/connect/twitter/callback", cookie.Path)
+		assert.True(t, cookie.Secure)
+		assert.True(t, cookie.HttpOnly)
+	}
+}
+
+// responseCookie returns the cookie with the given name that the response
+// sets, or nil if it sets none.
+func responseCookie(w *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
+
+// newCallbackRequest creates a request to the callback with the given query,
+// from a browser whose state cookie has the given value, if any.
+func newCallbackRequest(query string, cookieState string) *http.Request {
+	req, _ := http.NewRequest("GET", "/test/connect/twitter/callback?"+query, nil)
+	if cookieState != "" {
+		req.AddCookie(&http.Cookie{Name: "followrs_connect_state", Value: cookieState})
+	}
+	return req
+}
+
+func TestCompleteTwitterConnect(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		connection := &domain.TwitterConnection{UserID: "1", Username: "test", AccessToken: "secret"}
+		mockService := new(mocks.TwitterConnectService)
+		mockService.On("Complete", mock.Anything, "state", "code").Return(connection, nil)
+		mockService.On("Session", mock.Anything, "1").Return("session", nil)
+		router := newConnectRouter(mockService)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newCallbackRequest("state=state&code=code", "state"))
+
+		var retrievedConnection map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &retrievedConnection)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "1", retrievedConnection["user_id"])
+		assert.NotContains(t, w.Body.String(), "secret")
+		mockService.AssertExpectations(t)
+
+		session := responseCookie(w, middleware.SessionCookie)
+		if assert.NotNil(t, session) {
+			assert.Equal(t, "session", session.Value)
+			assert.True(t, session.Secure)
+			assert.True(t, session.HttpOnly)
+		}
+	})
+
+	errorTests := map[string]struct {
+		query       string
+		cookieState string
+		err         error
+		status      int
+		code        string
+	}{
+		"authorization-denied": {"error=access_denied&state=state", "state", nil, http.StatusForbidden, "authorization_denied"},
+		"missing-code":         {"state=state", "state", nil, http.StatusBadRequest, "invalid_parameter"},
+		"invalid-state":        {"state=state&code=code", "state", domain.ErrInvalidAuthorizationState, http.StatusBadRequest, "invalid_state"},
+		"missing-state-cookie": {"state=state&code=code", "", nil, http.StatusBadRequest, "invalid_state"},
+		"other-browser":        {"state=state&code=code", "other", nil, http.StatusBadRequest, "invalid_state"},
+	}
+	for name, test := range errorTests {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(mocks.TwitterConnectService)
+			mockService.On("Complete", mock.Anything, "state", "code").Return(nil, test.err)
+			router := newConnectRouter(mockService)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, newCallbackRequest(test.query, test.cookieState))
+
+			var problem middleware.ProblemJSON
+			json.Unmarshal(w.Body.Bytes(), &problem)
+
+			assert.Equal(t, test.status, w.Code)
+			assert.Equal(t, test.code, problem.Code)
+			assert.Nil(t, responseCookie(w, middleware.SessionCookie))
+			if test.err == nil {
+				mockService.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
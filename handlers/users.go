@@ -1,50 +1,476 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/jake-hansen/followrs/apperrors"
 	"github.com/jake-hansen/followrs/domain"
 	"net/http"
 	"strings"
+	"time"
 )
 
+// maxLookupUsernames is the most users that can be looked up by a single
+// request.
+const maxLookupUsernames = 100
+
+// twitterUsersResponse is the response to a lookup of several Twitter users.
+type twitterUsersResponse struct {
+	Users  []domain.TwitterUser    `json:"users"`
+	Errors []lookupFailureResponse `json:"errors"` // Users that could not be looked up.
+}
+
+// lookupFailureResponse describes a user that could not be looked up.
+type lookupFailureResponse struct {
+	Value   string `json:"value"`   // Username that was looked up.
+	Code    string `json:"code"`    // Machine-readable code identifying why the lookup failed.
+	Message string `json:"message"` // Description of why the lookup failed.
+}
+
+// twitterPlatform is the name of the platform of Twitter accounts, which
+// support requests that other platforms do not.
+const twitterPlatform = "twitter"
+
 type UsersHandler struct {
-	TwitterService *domain.TwitterService
+	TwitterService      *domain.TwitterService
+	FollowerDiffService domain.FollowerDiffService
+	Providers           domain.ProviderRegistry
 }
 
-func NewUsersHandler(parentGroup *gin.RouterGroup, twitterService *domain.TwitterService) {
+func NewUsersHandler(parentGroup *gin.RouterGroup, twitterService *domain.TwitterService, diffService domain.FollowerDiffService, providers domain.ProviderRegistry) {
 	handler := &UsersHandler{
-		TwitterService: twitterService,
+		TwitterService:      twitterService,
+		FollowerDiffService: diffService,
+		Providers:           providers,
 	}
 
 	usersGroup := parentGroup.Group("users")
 	{
-		twitterGroup := usersGroup.Group("/twitter")
-		{
-			twitterGroup.GET("/:username", func(c *gin.Context) {
-				username := c.Param("username")
-				handler.GetTwitterUser(username, c)
-			})
+		usersGroup.GET("/:platform", handler.GetTwitterUsers)                                 // GET /users/:platform
+		usersGroup.GET("/:platform/:username", handler.GetUser)                               // GET /users/:platform/:username
+		usersGroup.GET("/:platform/:username/followers", handler.GetFollowers)                // GET /users/:platform/:username/followers
+		usersGroup.GET("/:platform/:username/following", handler.GetFollowing)                // GET /users/:platform/:username/following
+		usersGroup.GET("/:platform/:username/metrics", handler.GetMetrics)                    // GET /users/:platform/:username/metrics
+		usersGroup.GET("/:platform/:username/repos/:repo/stargazers", handler.GetStargazers)  // GET /users/:platform/:username/repos/:repo/stargazers
+		usersGroup.GET("/:platform/:username/relationships", handler.GetTwitterRelationships) // GET /users/:platform/:username/relationships
+		usersGroup.GET("/:platform/:username/changes", handler.GetTwitterFollowerChanges)     // GET /users/:platform/:username/changes
+		usersGroup.POST("/:platform/:username/snapshots", handler.RecordTwitterSnapshot)      // POST /users/:platform/:username/snapshots
+	}
+}
+
+// GetUser looks up the account with the username given in the path on the
+// platform given in the path. Twitter users are returned with every field of
+// domain.TwitterUser, as they were before other platforms were supported, and
+// their optional fields, such as public_metrics, may be selected by the fields
+// query parameter, as for every lookup of Twitter users.
+func (u *UsersHandler) GetUser(c *gin.Context) {
+	provider, opts, ok := u.provider(c)
+	if !ok {
+		return
+	}
+
+	username := c.Param("username")
+	var account interface{}
+	var err error
+	if provider.Platform() == twitterPlatform {
+		account, err = (*u.TwitterService).GetUser(c.Request.Context(), username, opts...)
+	} else {
+		account, err = provider.LookupUser(c.Request.Context(), username)
+	}
+
+	if err == nil {
+		c.JSON(http.StatusOK, account)
+	} else {
+		c.Error(userError(username, err)).SetType(gin.ErrorTypePublic)
+	}
+}
+
+// GetFollowers lists the followers of the account given in the path. The
+// followers of Twitter users are listed as domain.TwitterUsers, as by GetUser.
+func (u *UsersHandler) GetFollowers(c *gin.Context) {
+	provider, opts, ok := u.provider(c)
+	if !ok {
+		return
+	}
+
+	username := c.Param("username")
+	var followers interface{}
+	var err error
+	if provider.Platform() == twitterPlatform {
+		followers, err = (*u.TwitterService).GetFollowers(c.Request.Context(), username, opts...)
+	} else {
+		followers, err = provider.ListFollowers(c.Request.Context(), username)
+	}
+
+	if err == nil {
+		c.JSON(http.StatusOK, followers)
+	} else if errors.Is(err, domain.ErrUnsupportedPlatform) {
+		c.Error(unsupportedPlatformError(provider.Platform(), err)).SetType(gin.ErrorTypePublic)
+	} else {
+		c.Error(userError(username, err)).SetType(gin.ErrorTypePublic)
+	}
+}
+
+// GetFollowing lists the accounts that the account given in the path follows.
+// The accounts Twitter users follow are listed as domain.TwitterUsers, as by
+// GetUser.
+func (u *UsersHandler) GetFollowing(c *gin.Context) {
+	provider, opts, ok := u.provider(c)
+	if !ok {
+		return
+	}
+
+	username := c.Param("username")
+	var following interface{}
+	var err error
+	if provider.Platform() == twitterPlatform {
+		following, err = (*u.TwitterService).GetFollowing(c.Request.Context(), username, opts...)
+	} else {
+		following, err = provider.ListFollowing(c.Request.Context(), username)
+	}
+
+	if err == nil {
+		c.JSON(http.StatusOK, following)
+	} else if errors.Is(err, domain.ErrUnsupportedPlatform) {
+		c.Error(unsupportedPlatformError(provider.Platform(), err)).SetType(gin.ErrorTypePublic)
+	} else {
+		c.Error(userError(username, err)).SetType(gin.ErrorTypePublic)
+	}
+}
+
+// GetMetrics retrieves the current counts of the account given in the path.
+func (u *UsersHandler) GetMetrics(c *gin.Context) {
+	provider, _, ok := u.provider(c)
+	if !ok {
+		return
+	}
+
+	username := c.Param("username")
+	metrics, err := provider.GetMetrics(c.Request.Context(), username)
+
+	if err == nil {
+		c.JSON(http.StatusOK, *metrics)
+	} else {
+		c.Error(userError(username, err)).SetType(gin.ErrorTypePublic)
+	}
+}
+
+// GetStargazers lists the accounts that have starred the repository given in
+// the path, which belongs to the account given in the path. Only platforms
+// whose Provider is a domain.StargazerProvider, such as GitHub, support it.
+func (u *UsersHandler) GetStargazers(c *gin.Context) {
+	provider, _, ok := u.provider(c)
+	if !ok {
+		return
+	}
+	stargazerProvider, ok := provider.(domain.StargazerProvider)
+	if !ok {
+		err := fmt.Errorf("%s has no repositories: %w", provider.Platform(), domain.ErrUnsupportedPlatform)
+		c.Error(unsupportedPlatformError(provider.Platform(), err)).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	username, repository := c.Param("username"), c.Param("repo")
+	stargazers, err := stargazerProvider.ListStargazers(c.Request.Context(), username, repository)
+
+	if err == nil {
+		c.JSON(http.StatusOK, stargazers)
+	} else if errors.Is(err, apperrors.ErrNotFound) {
+		apiError := &apperrors.APIError{
+			Status:  http.StatusNotFound,
+			Err:     err,
+			Message: fmt.Sprintf("the repository [%s/%s] was not found", username, repository),
 		}
+		c.Error(apiError).SetType(gin.ErrorTypePublic)
+	} else {
+		c.Error(userError(username, err)).SetType(gin.ErrorTypePublic)
 	}
 }
 
-func (u *UsersHandler) GetTwitterUser(username string, c *gin.Context) {
-	user, err := (*u.TwitterService).GetUser(username)
+// GetTwitterUsers looks up the Twitter users whose usernames are given,
+// separated by commas, by the usernames query parameter. Users that can't be
+// looked up are reported alongside the users that were found.
+func (u *UsersHandler) GetTwitterUsers(c *gin.Context) {
+	if !requireTwitter(c) {
+		return
+	}
+
+	usernames := splitList(c.Query("usernames"))
+	if len(usernames) == 0 || len(usernames) > maxLookupUsernames {
+		apiError := &apperrors.APIError{
+			Status:  http.StatusBadRequest,
+			Err:     fmt.Errorf("%d usernames given", len(usernames)),
+			Message: fmt.Sprintf("the usernames parameter must list between 1 and %d usernames", maxLookupUsernames),
+			Code:    "invalid_parameter",
+		}
+		c.Error(apiError).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	opts, ok := lookupOptions(c)
+	if !ok {
+		return
+	}
+
+	lookup, err := (*u.TwitterService).GetUsers(c.Request.Context(), usernames, opts...)
+	if err != nil {
+		c.Error(fieldsError(err)).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	response := twitterUsersResponse{
+		Users:  lookup.Users,
+		Errors: make([]lookupFailureResponse, 0, len(lookup.Failures)),
+	}
+	for _, failure := range lookup.Failures {
+		response.Errors = append(response.Errors, lookupFailure(failure))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetTwitterRelationships sorts the followers of the Twitter user given in the
+// path, and the users they follow, into mutuals, fans and non-followers.
+func (u *UsersHandler) GetTwitterRelationships(c *gin.Context) {
+	if !requireTwitter(c) {
+		return
+	}
+	opts, ok := lookupOptions(c)
+	if !ok {
+		return
+	}
+
+	username := c.Param("username")
+	relationships, err := (*u.TwitterService).GetRelationships(c.Request.Context(), username, opts...)
+
+	if err == nil {
+		c.JSON(http.StatusOK, *relationships)
+	} else {
+		c.Error(userError(username, err)).SetType(gin.ErrorTypePublic)
+	}
+}
+
+// GetTwitterFollowerChanges reports the followers the Twitter user given in
+// the path gained and lost between the time given by the since query parameter
+// and their most recent snapshot. The parameter may be an RFC 3339 timestamp or
+// a duration, such as 24h, before the current time. If it is omitted, the
+// changes since the snapshot before the most recent one are reported. Only
+// snapshots already recorded are compared; new ones are taken by the scheduler
+// or by RecordTwitterSnapshot.
+func (u *UsersHandler) GetTwitterFollowerChanges(c *gin.Context) {
+	if !requireTwitter(c) {
+		return
+	}
+
+	username := c.Param("username")
+	since, err := parseSince(c.Query("since"), time.Now())
+	if err != nil {
+		apiError := &apperrors.APIError{
+			Status:  http.StatusBadRequest,
+			Err:     err,
+			Message: fmt.Sprintf("the since parameter [%s] is not a timestamp or duration", c.Query("since")),
+			Code:    "invalid_parameter",
+		}
+		c.Error(apiError).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	opts, ok := lookupOptions(c)
+	if !ok {
+		return
+	}
+
+	changes, err := u.FollowerDiffService.GetChanges(c.Request.Context(), username, since, opts...)
+
+	if err == nil {
+		c.JSON(http.StatusOK, *changes)
+	} else if errors.Is(err, domain.ErrNoFollowerSnapshot) {
+		apiError := &apperrors.APIError{
+			Status:  http.StatusNotFound,
+			Err:     err,
+			Message: fmt.Sprintf("no earlier followers of the user [%s] have been recorded, try again later", username),
+			Code:    "no_snapshot",
+		}
+		c.Error(apiError).SetType(gin.ErrorTypePublic)
+	} else {
+		c.Error(userError(username, err)).SetType(gin.ErrorTypePublic)
+	}
+}
+
+// RecordTwitterSnapshot records a snapshot of the current followers of the
+// Twitter user given in the path, which later changes are compared with.
+func (u *UsersHandler) RecordTwitterSnapshot(c *gin.Context) {
+	if !requireTwitter(c) {
+		return
+	}
+
+	username := c.Param("username")
+	snapshot, err := u.FollowerDiffService.RecordSnapshot(c.Request.Context(), username)
 
 	if err == nil {
-		c.JSON(http.StatusOK, *user)
+		c.JSON(http.StatusCreated, *snapshot)
 	} else {
-		apiError := err
+		c.Error(userError(username, err)).SetType(gin.ErrorTypePublic)
+	}
+}
+
+// provider returns the Provider of the platform given in the path, and the
+// options to look up Twitter users with, as by lookupOptions. If the platform
+// is not supported, the error is reported and false is returned.
+func (u *UsersHandler) provider(c *gin.Context) (domain.Provider, []domain.TwitterLookupOptions, bool) {
+	platform := c.Param("platform")
+	provider, err := u.Providers.Provider(platform)
+	if err != nil {
+		c.Error(unsupportedPlatformError(platform, err)).SetType(gin.ErrorTypePublic)
+		return nil, nil, false
+	}
+
+	opts, ok := lookupOptions(c)
+	if !ok {
+		return nil, nil, false
+	}
+	return provider, opts, true
+}
+
+// lookupOptions returns the options to look up Twitter users with, which
+// select the optional user fields listed by the fields query parameter. All
+// of them are looked up if the parameter is omitted. If it is given for a
+// platform other than Twitter, the error is reported and false is returned.
+func lookupOptions(c *gin.Context) ([]domain.TwitterLookupOptions, bool) {
+	fields, ok := c.GetQuery("fields")
+	if !ok {
+		return nil, true
+	}
 
-		if strings.Contains(err.Error(), "user not found") {
-			apiError = &apperrors.APIError{
-				Status:  http.StatusNotFound,
-				Err:     err,
-				Message: fmt.Sprintf("the user [%s] was not found", username),
-			}
+	if platform := c.Param("platform"); platform != twitterPlatform {
+		apiError := &apperrors.APIError{
+			Status:  http.StatusBadRequest,
+			Err:     fmt.Errorf("fields given for %s", platform),
+			Message: fmt.Sprintf("the fields parameter is not supported for the platform [%s]", platform),
+			Code:    "invalid_parameter",
 		}
 		c.Error(apiError).SetType(gin.ErrorTypePublic)
+		return nil, false
+	}
+
+	opts := domain.TwitterLookupOptions{Fields: []string{}}
+	opts.Fields = append(opts.Fields, splitList(fields)...)
+	return []domain.TwitterLookupOptions{opts}, true
+}
+
+// fieldsError converts the error returned when the fields query parameter
+// lists an unknown field into an error suitable for the client. Other errors
+// are returned unchanged.
+func fieldsError(err error) error {
+	if !errors.Is(err, domain.ErrUnknownUserField) {
+		return err
+	}
+	return &apperrors.APIError{
+		Status:  http.StatusBadRequest,
+		Err:     err,
+		Message: "the fields parameter lists an unknown field",
+		Code:    "invalid_parameter",
+	}
+}
+
+// requireTwitter determines whether the platform given in the path is Twitter,
+// for requests that only Twitter supports. If it is not, the error is reported
+// and false is returned.
+func requireTwitter(c *gin.Context) bool {
+	platform := c.Param("platform")
+	if platform == twitterPlatform {
+		return true
+	}
+	err := fmt.Errorf("request only supported for %s: %w", twitterPlatform, domain.ErrUnsupportedPlatform)
+	c.Error(unsupportedPlatformError(platform, err)).SetType(gin.ErrorTypePublic)
+	return false
+}
+
+// unsupportedPlatformError describes a request for a platform that does not
+// support it.
+func unsupportedPlatformError(platform string, err error) error {
+	return &apperrors.APIError{
+		Status:  http.StatusNotFound,
+		Err:     err,
+		Message: fmt.Sprintf("the platform [%s] does not support this request", platform),
+		Code:    "unsupported_platform",
+	}
+}
+
+// splitList splits a comma-separated query parameter into its non-empty items.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseSince parses the value of a since query parameter relative to now.
+func parseSince(since string, now time.Time) (time.Time, error) {
+	if since == "" {
+		return now, nil
+	}
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(since)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return now.Add(-d), nil
+}
+
+// lookupFailure describes a user that could not be looked up in the same
+// terms as a failed lookup of that user alone.
+func lookupFailure(failure domain.TwitterLookupFailure) lookupFailureResponse {
+	result := lookupFailureResponse{
+		Value:   failure.Value,
+		Code:    "unknown_error",
+		Message: fmt.Sprintf("the user [%s] could not be looked up", failure.Value),
+	}
+	if kind, ok := apperrors.KindOf(failure.Err); ok {
+		result.Code = kind.Code
+		result.Message = kind.Message
+	}
+	var apiError *apperrors.APIError
+	if errors.As(userError(failure.Value, failure.Err), &apiError) {
+		result.Message = apiError.Message
 	}
-}
\ No newline at end of file
+	return result
+}
+
+// userError converts an error returned while looking up a user into an error
+// suitable for the client, as does fieldsError. Errors that do not concern the
+// user are returned unchanged so that they can be reported by their kind.
+func userError(username string, err error) error {
+	switch {
+	case errors.Is(err, apperrors.ErrNotFound):
+		return &apperrors.APIError{
+			Status:  http.StatusNotFound,
+			Err:     err,
+			Message: fmt.Sprintf("the user [%s] was not found", username),
+		}
+	case errors.Is(err, apperrors.ErrSuspended):
+		return &apperrors.APIError{
+			Status:  http.StatusForbidden,
+			Err:     err,
+			Message: fmt.Sprintf("the user [%s] has been suspended", username),
+		}
+	case errors.Is(err, apperrors.ErrProtected):
+		return &apperrors.APIError{
+			Status:  http.StatusForbidden,
+			Err:     err,
+			Message: fmt.Sprintf("the user [%s] is protected", username),
+		}
+	case errors.Is(err, apperrors.ErrInvalidUsername):
+		return &apperrors.APIError{
+			Status:  http.StatusBadRequest,
+			Err:     err,
+			Message: fmt.Sprintf("the username [%s] is not valid", username),
+		}
+	}
+	return fieldsError(err)
+}
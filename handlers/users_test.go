@@ -0,0 +1,583 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/handlers"
+	"github.com/jake-hansen/followrs/middleware"
+	"github.com/jake-hansen/followrs/repositories/apis/bluesky"
+	"github.com/jake-hansen/followrs/repositories/apis/github"
+	"github.com/jake-hansen/followrs/repositories/apis/mastodon"
+	"github.com/jake-hansen/followrs/repositories/apis/twitch"
+	"github.com/jake-hansen/followrs/repositories/apis/youtube"
+	repomocks "github.com/jake-hansen/followrs/repositories/mocks"
+	"github.com/jake-hansen/followrs/services"
+	"github.com/jake-hansen/followrs/services/mocks"
+)
+
+func newUsersRouter(twitterService domain.TwitterService, diffService domain.FollowerDiffService, providers ...domain.Provider) *gin.Engine {
+	router := gin.Default()
+	router.Use(middleware.PublicErrorHandler())
+	providers = append(providers, services.NewTwitterProvider(twitterService))
+	handlers.NewUsersHandler(router.Group("test"), &twitterService, diffService, services.NewProviderRegistry(providers...))
+	return router
+}
+
+// newMockProvider creates a Provider of the platform "example".
+func newMockProvider() *mocks.Provider {
+	provider := new(mocks.Provider)
+	provider.On("Platform").Return("example")
+	return provider
+}
+
+// newFullTwitterUser creates a Twitter user with every optional field set.
+func newFullTwitterUser(id string, username string) *domain.TwitterUser {
+	createdAt := time.Date(2010, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &domain.TwitterUser{
+		ID:              id,
+		Name:            "Test",
+		Username:        username,
+		CreatedAt:       &createdAt,
+		Description:     "description",
+		Location:        "location",
+		ProfileImageURL: "https://pbs.twimg.com/profile_images/1/test.jpg",
+		Protected:       true,
+		PublicMetrics:   &domain.TwitterPublicMetrics{Followers: 10, Following: 5, Tweets: 100, Listed: 1},
+		URL:             "https://example.com",
+		Verified:        true,
+	}
+}
+
+// fullTwitterUserJSON is the JSON that Twitter users created by
+// newFullTwitterUser are returned as, which clients depend on.
+func fullTwitterUserJSON(id string, username string) string {
+	return fmt.Sprintf(`{
+		"id": %q,
+		"name": "Test",
+		"username": %q,
+		"created_at": "2010-01-02T03:04:05Z",
+		"description": "description",
+		"location": "location",
+		"profile_image_url": "https://pbs.twimg.com/profile_images/1/test.jpg",
+		"protected": true,
+		"public_metrics": {"followers_count": 10, "following_count": 5, "tweet_count": 100, "listed_count": 1},
+		"url": "https://example.com",
+		"verified": true
+	}`, id, username)
+}
+
+func TestGetTwitterUser(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockTwitterService := new(mocks.TwitterService)
+		mockTwitterService.On("GetUser", mock.Anything, "test").Return(&domain.TwitterUser{ID: "1", Username: "test"}, nil)
+		router := newUsersRouter(mockTwitterService, new(mocks.FollowerDiffService))
+
+		req, err := http.NewRequest("GET", "/test/users/twitter/test", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var user domain.TwitterUser
+		json.Unmarshal(w.Body.Bytes(), &user)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, domain.TwitterUser{ID: "1", Username: "test"}, user)
+		mockTwitterService.AssertExpectations(t)
+	})
+
+	t.Run("json-shape", func(t *testing.T) {
+		mockTwitterService := new(mocks.TwitterService)
+		mockTwitterService.On("GetUser", mock.Anything, "test").Return(newFullTwitterUser("1", "test"), nil)
+		router := newUsersRouter(mockTwitterService, new(mocks.FollowerDiffService))
+
+		req, _ := http.NewRequest("GET", "/test/users/twitter/test", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, fullTwitterUserJSON("1", "test"), w.Body.String())
+	})
+
+	t.Run("selected-fields", func(t *testing.T) {
+		mockTwitterService := new(mocks.TwitterService)
+		mockTwitterService.On("GetUser", mock.Anything, "test", domain.TwitterLookupOptions{Fields: []string{"public_metrics", "verified"}}).Return(&domain.TwitterUser{ID: "1", Username: "test"}, nil)
+		router := newUsersRouter(mockTwitterService, new(mocks.FollowerDiffService))
+
+		req, _ := http.NewRequest("GET", "/test/users/twitter/test?fields=public_metrics,verified", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockTwitterService.AssertExpectations(t)
+	})
+
+	t.Run("unknown-field", func(t *testing.T) {
+		mockTwitterService := new(mocks.TwitterService)
+		mockTwitterService.On("GetUser", mock.Anything, "test", domain.TwitterLookupOptions{Fields: []string{"pinned_tweet"}}).Return(nil, fmt.Errorf("%w %q", domain.ErrUnknownUserField, "pinned_tweet"))
+		router := newUsersRouter(mockTwitterService, new(mocks.FollowerDiffService))
+
+		req, _ := http.NewRequest("GET", "/test/users/twitter/test?fields=pinned_tweet", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid_parameter")
+	})
+
+	errorCases := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"not-found", apperrors.ErrNotFound, http.StatusNotFound},
+		{"suspended", apperrors.ErrSuspended, http.StatusForbidden},
+		{"protected", apperrors.ErrProtected, http.StatusForbidden},
+		{"invalid-username", apperrors.ErrInvalidUsername, http.StatusBadRequest},
+		{"rate-limited", apperrors.ErrRateLimited, http.StatusTooManyRequests},
+		{"unauthorized", apperrors.ErrUnauthorized, http.StatusBadGateway},
+		{"upstream-unavailable", apperrors.ErrUpstreamUnavailable, http.StatusBadGateway},
+		{"unknown", fmt.Errorf("example error"), http.StatusInternalServerError},
+	}
+	for _, errorCase := range errorCases {
+		errorCase := errorCase
+		t.Run(errorCase.name, func(t *testing.T) {
+			mockTwitterService := new(mocks.TwitterService)
+			mockTwitterService.On("GetUser", mock.Anything, "test").Return(nil, fmt.Errorf("could not retrieve test: %w", errorCase.err))
+			router := newUsersRouter(mockTwitterService, new(mocks.FollowerDiffService))
+
+			req, _ := http.NewRequest("GET", "/test/users/twitter/test", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, errorCase.status, w.Code)
+		})
+	}
+}
+
+func TestGetTwitterUsers(t *testing.T) {
+	t.Run("success-with-failures", func(t *testing.T) {
+		mockTwitterService := new(mocks.TwitterService)
+		mockTwitterService.On("GetUsers", mock.Anything, []string{"a", "b", "c"}).Return(&domain.TwitterUsers{
+			Users: []domain.TwitterUser{{ID: "1", Username: "a"}},
+			Failures: []domain.TwitterLookupFailure{
+				{Value: "b", Err: fmt.Errorf("user %w", apperrors.ErrNotFound)},
+				{Value: "c", Err: fmt.Errorf("user %w", apperrors.ErrSuspended)},
+			},
+		}, nil)
+		router := newUsersRouter(mockTwitterService, new(mocks.FollowerDiffService))
+
+		req, _ := http.NewRequest("GET", "/test/users/twitter?usernames=a,b,c", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response struct {
+			Users  []domain.TwitterUser `json:"users"`
+			Errors []map[string]string  `json:"errors"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []domain.TwitterUser{{ID: "1", Username: "a"}}, response.Users)
+		assert.Equal(t, []map[string]string{
+			{"value": "b", "code": "not_found", "message": "the user [b] was not found"},
+			{"value": "c", "code": "suspended", "message": "the user [c] has been suspended"},
+		}, response.Errors)
+	})
+
+	t.Run("no-usernames", func(t *testing.T) {
+		router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService))
+
+		req, _ := http.NewRequest("GET", "/test/users/twitter", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("too-many-usernames", func(t *testing.T) {
+		router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService))
+
+		usernames := strings.TrimSuffix(strings.Repeat("a,", 101), ",")
+		req, _ := http.NewRequest("GET", "/test/users/twitter?usernames="+usernames, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("lookup-failed", func(t *testing.T) {
+		mockTwitterService := new(mocks.TwitterService)
+		mockTwitterService.On("GetUsers", mock.Anything, []string{"a"}).Return(nil, fmt.Errorf("could not look up users: %w", apperrors.ErrRateLimited))
+		router := newUsersRouter(mockTwitterService, new(mocks.FollowerDiffService))
+
+		req, _ := http.NewRequest("GET", "/test/users/twitter?usernames=a", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
+}
+
+func TestGetUser(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		account := &domain.Account{Platform: "example", ID: "1", Username: "test", Name: "Test"}
+		provider := newMockProvider()
+		provider.On("LookupUser", mock.Anything, "test").Return(account, nil)
+		router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService), provider)
+
+		req, _ := http.NewRequest("GET", "/test/users/example/test", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var retrievedAccount domain.Account
+		json.Unmarshal(w.Body.Bytes(), &retrievedAccount)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, *account, retrievedAccount)
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		provider := newMockProvider()
+		provider.On("LookupUser", mock.Anything, "test").Return(nil, fmt.Errorf("could not look up test: %w", apperrors.ErrNotFound))
+		router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService), provider)
+
+		req, _ := http.NewRequest("GET", "/test/users/example/test", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("unsupported-platform", func(t *testing.T) {
+		router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService))
+
+		req, _ := http.NewRequest("GET", "/test/users/myspace/test", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "unsupported_platform")
+	})
+
+	t.Run("mastodon", func(t *testing.T) {
+		mastodonRepo := new(repomocks.MastodonRepository)
+		mastodonRepo.On("LookupAccount", mock.Anything, "@alice@mastodon.example").Return(&mastodon.Account{ID: "1", Acct: "alice", Instance: "mastodon.example"}, nil)
+		router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService), services.NewMastodonProvider(mastodonRepo))
+
+		req, _ := http.NewRequest("GET", "/test/users/mastodon/@alice@mastodon.example", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var retrievedAccount domain.Account
+		json.Unmarshal(w.Body.Bytes(), &retrievedAccount)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "mastodon", retrievedAccount.Platform)
+		assert.Equal(t, "alice@mastodon.example", retrievedAccount.Username)
+	})
+
+	t.Run("mastodon-invalid-acct", func(t *testing.T) {
+		mastodonRepo := new(repomocks.MastodonRepository)
+		mastodonRepo.On("LookupAccount", mock.Anything, "alice@").Return(nil, apperrors.ErrInvalidUsername)
+		router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService), services.NewMastodonProvider(mastodonRepo))
+
+		req, _ := http.NewRequest("GET", "/test/users/mastodon/alice@", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("bluesky", func(t *testing.T) {
+		blueskyRepo := new(repomocks.BlueskyRepository)
+		blueskyRepo.On("GetProfile", mock.Anything, "alice.bsky.social").Return(&bluesky.Profile{DID: "did:plc:alice", Handle: "alice.bsky.social", FollowersCount: 10}, nil)
+		router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService), services.NewBlueskyProvider(blueskyRepo))
+
+		req, _ := http.NewRequest("GET", "/test/users/bluesky/alice.bsky.social", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var retrievedAccount domain.Account
+		json.Unmarshal(w.Body.Bytes(), &retrievedAccount)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "did:plc:alice", retrievedAccount.ID)
+		assert.Equal(t, int64(10), retrievedAccount.Metrics.Followers)
+	})
+
+	t.Run("github", func(t *testing.T) {
+		githubRepo := new(repomocks.GitHubRepository)
+		githubRepo.On("GetUser", mock.Anything, "octocat").Return(&github.User{Login: "octocat", ID: 583231, Followers: 20}, nil)
+		router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService), services.NewGitHubProvider(githubRepo))
+
+		req, _ := http.NewRequest("GET", "/test/users/github/octocat", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var retrievedAccount domain.Account
+		json.Unmarshal(w.Body.Bytes(), &retrievedAccount)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "583231", retrievedAccount.ID)
+		assert.Equal(t, int64(20), retrievedAccount.Metrics.Followers)
+	})
+
+	t.Run("youtube", func(t *testing.T) {
+		youtubeRepo := new(repomocks.YouTubeRepository)
+		channel := &youtube.Channel{
+			ID:         "UCBR8-60-B28hp2BmDPdntcQ",
+			Snippet:    youtube.ChannelSnippet{CustomURL: "@youtube"},
+			Statistics: youtube.ChannelStatistics{ViewCount: 3000, HiddenSubscriberCount: true},
+		}
+		youtubeRepo.On("GetChannel", mock.Anything, "@youtube").Return(channel, nil)
+		router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService), services.NewYouTubeProvider(youtubeRepo))
+
+		req, _ := http.NewRequest("GET", "/test/users/youtube/@youtube", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var retrievedAccount domain.Account
+		json.Unmarshal(w.Body.Bytes(), &retrievedAccount)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "youtube", retrievedAccount.Username)
+		assert.Equal(t, int64(3000), retrievedAccount.Metrics.Views)
+		assert.True(t, retrievedAccount.Metrics.FollowersHidden)
+	})
+
+	t.Run("twitch", func(t *testing.T) {
+		twitchRepo := new(repomocks.TwitchRepository)
+		twitchRepo.On("GetUser", mock.Anything, "twitchdev").Return(&twitch.User{ID: "141981764", Login: "twitchdev"}, nil)
+		twitchRepo.On("GetFollowerCount", mock.Anything, "141981764").Return(int64(8), nil)
+		router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService), services.NewTwitchProvider(twitchRepo))
+
+		req, _ := http.NewRequest("GET", "/test/users/twitch/twitchdev", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var retrievedAccount domain.Account
+		json.Unmarshal(w.Body.Bytes(), &retrievedAccount)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "141981764", retrievedAccount.ID)
+		assert.Equal(t, int64(8), retrievedAccount.Metrics.Followers)
+	})
+
+	t.Run("fields-not-supported", func(t *testing.T) {
+		provider := newMockProvider()
+		router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService), provider)
+
+		req, _ := http.NewRequest("GET", "/test/users/example/test?fields=verified", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		provider.AssertNotCalled(t, "LookupUser", mock.Anything, "test")
+	})
+}
+
+func TestGetFollowers(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		followers := []domain.Follower{{ID: "2", Username: "two"}, {ID: "3", Username: "three"}}
+		provider := newMockProvider()
+		provider.On("ListFollowers", mock.Anything, "test").Return(followers, nil)
+		router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService), provider)
+
+		req, _ := http.NewRequest("GET", "/test/users/example/test/followers", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var retrievedFollowers []domain.Follower
+		json.Unmarshal(w.Body.Bytes(), &retrievedFollowers)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, followers, retrievedFollowers)
+	})
+
+	t.Run("twitter", func(t *testing.T) {
+		mockTwitterService := new(mocks.TwitterService)
+		mockTwitterService.On("GetFollowers", mock.Anything, "test").Return([]domain.TwitterUser{*newFullTwitterUser("2", "two")}, nil)
+		router := newUsersRouter(mockTwitterService, new(mocks.FollowerDiffService))
+
+		req, _ := http.NewRequest("GET", "/test/users/twitter/test/followers", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, "["+fullTwitterUserJSON("2", "two")+"]", w.Body.String())
+	})
+
+	t.Run("twitter-selected-fields", func(t *testing.T) {
+		mockTwitterService := new(mocks.TwitterService)
+		mockTwitterService.On("GetFollowers", mock.Anything, "test", domain.TwitterLookupOptions{Fields: []string{"public_metrics"}}).Return([]domain.TwitterUser{{ID: "2", Username: "two"}}, nil)
+		router := newUsersRouter(mockTwitterService, new(mocks.FollowerDiffService))
+
+		req, _ := http.NewRequest("GET", "/test/users/twitter/test/followers?fields=public_metrics", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockTwitterService.AssertExpectations(t)
+	})
+
+	t.Run("not-listed", func(t *testing.T) {
+		router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService), services.NewYouTubeProvider(new(repomocks.YouTubeRepository)))
+
+		req, _ := http.NewRequest("GET", "/test/users/youtube/youtube/followers", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "unsupported_platform")
+	})
+}
+
+func TestGetFollowing(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		following := []domain.Follower{{ID: "2", Username: "two"}}
+		provider := newMockProvider()
+		provider.On("ListFollowing", mock.Anything, "test").Return(following, nil)
+		router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService), provider)
+
+		req, _ := http.NewRequest("GET", "/test/users/example/test/following", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var retrievedFollowing []domain.Follower
+		json.Unmarshal(w.Body.Bytes(), &retrievedFollowing)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, following, retrievedFollowing)
+	})
+
+	t.Run("twitter", func(t *testing.T) {
+		mockTwitterService := new(mocks.TwitterService)
+		mockTwitterService.On("GetFollowing", mock.Anything, "test").Return([]domain.TwitterUser{*newFullTwitterUser("2", "two")}, nil)
+		router := newUsersRouter(mockTwitterService, new(mocks.FollowerDiffService))
+
+		req, _ := http.NewRequest("GET", "/test/users/twitter/test/following", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, "["+fullTwitterUserJSON("2", "two")+"]", w.Body.String())
+	})
+}
+
+func TestGetStargazers(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		githubRepo := new(repomocks.GitHubRepository)
+		githubRepo.On("GetStargazers", mock.Anything, "octocat", "hello-world").Return([]github.User{{Login: "hubot", ID: 1}}, nil)
+		router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService), services.NewGitHubProvider(githubRepo))
+
+		req, _ := http.NewRequest("GET", "/test/users/github/octocat/repos/hello-world/stargazers", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var stargazers []domain.Follower
+		json.Unmarshal(w.Body.Bytes(), &stargazers)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []domain.Follower{{ID: "1", Username: "hubot"}}, stargazers)
+	})
+
+	t.Run("repository-not-found", func(t *testing.T) {
+		githubRepo := new(repomocks.GitHubRepository)
+		githubRepo.On("GetStargazers", mock.Anything, "octocat", "missing").Return(nil, apperrors.ErrNotFound)
+		router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService), services.NewGitHubProvider(githubRepo))
+
+		req, _ := http.NewRequest("GET", "/test/users/github/octocat/repos/missing/stargazers", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "the repository [octocat/missing] was not found")
+	})
+
+	t.Run("no-repositories", func(t *testing.T) {
+		router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService), newMockProvider())
+
+		req, _ := http.NewRequest("GET", "/test/users/example/test/repos/test/stargazers", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "unsupported_platform")
+	})
+}
+
+func TestGetUserMetrics(t *testing.T) {
+	metrics := &domain.AccountMetrics{Platform: "example", AccountID: "1", Followers: 10, Following: 5}
+	provider := newMockProvider()
+	provider.On("GetMetrics", mock.Anything, "test").Return(metrics, nil)
+	router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService), provider)
+
+	req, _ := http.NewRequest("GET", "/test/users/example/test/metrics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var retrievedMetrics domain.AccountMetrics
+	json.Unmarshal(w.Body.Bytes(), &retrievedMetrics)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, *metrics, retrievedMetrics)
+}
+
+func TestTwitterOnlyRequests(t *testing.T) {
+	for _, path := range []string{"/test/users/example?usernames=a", "/test/users/example/test/relationships", "/test/users/example/test/changes"} {
+		t.Run(path, func(t *testing.T) {
+			router := newUsersRouter(new(mocks.TwitterService), new(mocks.FollowerDiffService), newMockProvider())
+
+			req, _ := http.NewRequest("GET", path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.Contains(t, w.Body.String(), "unsupported_platform")
+		})
+	}
+}
+
+func TestRecordTwitterSnapshot(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		snapshot := &domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), FollowerIDs: []string{"2"}}
+		diffService := new(mocks.FollowerDiffService)
+		diffService.On("RecordSnapshot", mock.Anything, "test").Return(snapshot, nil)
+		router := newUsersRouter(new(mocks.TwitterService), diffService)
+
+		req, _ := http.NewRequest("POST", "/test/users/twitter/test/snapshots", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var recordedSnapshot domain.FollowerSnapshot
+		json.Unmarshal(w.Body.Bytes(), &recordedSnapshot)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, *snapshot, recordedSnapshot)
+	})
+
+	t.Run("unsupported-platform", func(t *testing.T) {
+		diffService := new(mocks.FollowerDiffService)
+		router := newUsersRouter(new(mocks.TwitterService), diffService, newMockProvider())
+
+		req, _ := http.NewRequest("POST", "/test/users/example/test/snapshots", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		diffService.AssertNotCalled(t, "RecordSnapshot", mock.Anything, mock.Anything)
+	})
+}
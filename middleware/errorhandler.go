@@ -3,46 +3,146 @@ package middleware
 import (
 	"errors"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jake-hansen/followrs/apperrors"
 )
 
+// ErrorFormat determines how errors are represented in responses.
+type ErrorFormat string
+
+const (
+	// ProblemFormat reports errors as RFC 7807 problem details.
+	ProblemFormat ErrorFormat = "problem"
+
+	// LegacyFormat reports errors as an APIErrorJSON.
+	LegacyFormat ErrorFormat = "legacy"
+)
+
+// problemContentType is the media type of an RFC 7807 problem details response.
+const problemContentType = "application/problem+json"
+
+// problemTypePrefix is prepended to an error's code to form the URI that
+// identifies its problem type.
+const problemTypePrefix = "urn:followrs:problem:"
+
 // APIErrorJSON represents an error message.
 type APIErrorJSON struct {
 	Error string `json:"error"`
 }
 
+// ProblemJSON represents an error as RFC 7807 problem details.
+type ProblemJSON struct {
+	Type     string `json:"type"`               // URI identifying the kind of problem.
+	Title    string `json:"title"`              // Short summary of the kind of problem.
+	Status   int    `json:"status"`             // HTTP status of the response.
+	Detail   string `json:"detail,omitempty"`   // Explanation of this occurrence of the problem.
+	Instance string `json:"instance,omitempty"` // Path of the request the problem occurred on.
+	Code     string `json:"code"`               // Machine-readable code identifying the kind of problem.
+}
+
+// retryAfterError is implemented by errors that know when the failed request
+// can be retried, such as *apis.RateLimitError.
+type retryAfterError interface {
+	RetryAfter() time.Duration
+}
+
+// publicError contains everything about an error that is reported to the client.
+type publicError struct {
+	status  int
+	code    string
+	message string
+}
+
 // PublicErrorHandler middleware handles public errors for the Gin framework.
+// Errors are reported as RFC 7807 problem details.
 func PublicErrorHandler() gin.HandlerFunc {
-	return handlePublicErrors()
+	return handlePublicErrors(ProblemFormat)
+}
+
+// PublicErrorHandlerWithFormat is like PublicErrorHandler, but reports errors
+// in the given format. Unrecognized formats are treated as ProblemFormat.
+func PublicErrorHandlerWithFormat(format ErrorFormat) gin.HandlerFunc {
+	return handlePublicErrors(format)
 }
 
 // handlePublicErrors reports errors to the client in a meaningful way.
 // If an APIError is available, the proivded error message will be returned
 // to the client along with the provied HTTP status. If an APIError is not
-// available, a generic error message is returned along with a 500 status.
-func handlePublicErrors() gin.HandlerFunc {
+// available but the error wraps one of the errors in apperrors, the status
+// and message for that error are returned. Otherwise, a generic error message
+// is returned along with a 500 status.
+func handlePublicErrors(format ErrorFormat) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
 
 		err := c.Errors.ByType(gin.ErrorTypePublic).Last()
 		if err != nil {
+			var displayError publicError
 			var apiError *apperrors.APIError
 			if errors.As(err.Err, &apiError) {
-				displayError := APIErrorJSON{
-					Error: apiError.Message,
+				displayError = publicError{
+					status:  apiError.Status,
+					code:    apiError.Code,
+					message: apiError.Message,
+				}
+				if displayError.code == "" {
+					if kind, ok := apperrors.KindOf(apiError.Err); ok && kind.Status == apiError.Status {
+						displayError.code = kind.Code
+					}
 				}
 				log.Print(apiError.Err.Error())
-				c.JSON(apiError.Status, displayError)
+			} else if kind, ok := apperrors.KindOf(err.Err); ok {
+				displayError = publicError{
+					status:  kind.Status,
+					code:    kind.Code,
+					message: kind.Message,
+				}
+				log.Print(err.Error())
 			} else {
-				displayError := APIErrorJSON{
-					Error: "unknown error occurred.",
+				displayError = publicError{
+					status:  http.StatusInternalServerError,
+					message: "unknown error occurred.",
 				}
 				log.Print(err.Error())
-				c.JSON(http.StatusInternalServerError, displayError)
 			}
+			if displayError.code == "" {
+				displayError.code = statusCode(displayError.status)
+			}
+
+			var retryable retryAfterError
+			if errors.As(err.Err, &retryable) {
+				seconds := math.Ceil(retryable.RetryAfter().Seconds())
+				c.Header("Retry-After", strconv.Itoa(int(seconds)))
+			}
+
+			if format == LegacyFormat {
+				c.JSON(displayError.status, APIErrorJSON{
+					Error: displayError.message,
+				})
+				return
+			}
+
+			c.Header("Content-Type", problemContentType)
+			c.JSON(displayError.status, ProblemJSON{
+				Type:     problemTypePrefix + displayError.code,
+				Title:    http.StatusText(displayError.status),
+				Status:   displayError.status,
+				Detail:   displayError.message,
+				Instance: c.Request.URL.Path,
+				Code:     displayError.code,
+			})
 		}
 	}
 }
+
+// statusCode derives an error code from an HTTP status, such as not_found
+// from 404.
+func statusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
@@ -0,0 +1,141 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/middleware"
+	"github.com/jake-hansen/followrs/repositories/apis"
+)
+
+func newErrorRouter(handler gin.HandlerFunc, err error) *gin.Engine {
+	router := gin.New()
+	router.Use(handler)
+	router.GET("/test", func(c *gin.Context) {
+		c.Error(err).SetType(gin.ErrorTypePublic)
+	})
+	return router
+}
+
+func TestPublicErrorHandler(t *testing.T) {
+	t.Run("api-error", func(t *testing.T) {
+		apiError := &apperrors.APIError{
+			Status:  http.StatusNotFound,
+			Err:     fmt.Errorf("user %w", apperrors.ErrNotFound),
+			Message: "the user [test] was not found",
+		}
+		router := newErrorRouter(middleware.PublicErrorHandler(), apiError)
+
+		req, _ := http.NewRequest("GET", "/test", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var problem middleware.ProblemJSON
+		json.Unmarshal(w.Body.Bytes(), &problem)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		assert.Equal(t, middleware.ProblemJSON{
+			Type:     "urn:followrs:problem:not_found",
+			Title:    "Not Found",
+			Status:   http.StatusNotFound,
+			Detail:   "the user [test] was not found",
+			Instance: "/test",
+			Code:     "not_found",
+		}, problem)
+	})
+
+	t.Run("api-error-with-code", func(t *testing.T) {
+		apiError := &apperrors.APIError{
+			Status:  http.StatusConflict,
+			Err:     errors.New("example error"),
+			Message: "the user [test] is already tracked",
+			Code:    "already_tracked",
+		}
+		router := newErrorRouter(middleware.PublicErrorHandler(), apiError)
+
+		req, _ := http.NewRequest("GET", "/test", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var problem middleware.ProblemJSON
+		json.Unmarshal(w.Body.Bytes(), &problem)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "already_tracked", problem.Code)
+		assert.Equal(t, "urn:followrs:problem:already_tracked", problem.Type)
+	})
+
+	t.Run("wrapped-kind", func(t *testing.T) {
+		router := newErrorRouter(middleware.PublicErrorHandler(), fmt.Errorf("example: %w", apperrors.ErrRateLimited))
+
+		req, _ := http.NewRequest("GET", "/test", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var problem middleware.ProblemJSON
+		json.Unmarshal(w.Body.Bytes(), &problem)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "rate_limited", problem.Code)
+		assert.Equal(t, "Too Many Requests", problem.Title)
+	})
+
+	t.Run("retry-after", func(t *testing.T) {
+		rateLimitError := &apis.RateLimitError{
+			Key:   apis.RateLimitKey{Endpoint: "GET /users/:id"},
+			Reset: time.Now().Add(90 * time.Second),
+		}
+		router := newErrorRouter(middleware.PublicErrorHandler(), fmt.Errorf("example: %w", rateLimitError))
+
+		req, _ := http.NewRequest("GET", "/test", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "90", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("unknown-error", func(t *testing.T) {
+		router := newErrorRouter(middleware.PublicErrorHandler(), errors.New("example error"))
+
+		req, _ := http.NewRequest("GET", "/test", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var problem middleware.ProblemJSON
+		json.Unmarshal(w.Body.Bytes(), &problem)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "internal_server_error", problem.Code)
+	})
+
+	t.Run("legacy-format", func(t *testing.T) {
+		apiError := &apperrors.APIError{
+			Status:  http.StatusNotFound,
+			Err:     errors.New("example error"),
+			Message: "the user [test] was not found",
+		}
+		router := newErrorRouter(middleware.PublicErrorHandlerWithFormat(middleware.LegacyFormat), apiError)
+
+		req, _ := http.NewRequest("GET", "/test", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var displayError middleware.APIErrorJSON
+		json.Unmarshal(w.Body.Bytes(), &displayError)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		assert.Equal(t, "the user [test] was not found", displayError.Error)
+	})
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/domain"
+)
+
+// SessionCookie is the name of the cookie carrying the session token that
+// identifies the owner of a connected Twitter account.
+const SessionCookie = "followrs_session"
+
+// TwitterUser middleware makes the requests to Twitter needed to handle a
+// request on behalf of the connected Twitter account whose owner is identified
+// by the request's session cookie. Requests without a session are handled with
+// the server's own credentials.
+func TwitterUser(service domain.TwitterConnectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, err := c.Cookie(SessionCookie)
+		if err != nil || token == "" {
+			c.Next()
+			return
+		}
+
+		userID, err := service.SessionUser(c.Request.Context(), token)
+		if err != nil {
+			if errors.Is(err, domain.ErrInvalidSession) {
+				err = &apperrors.APIError{
+					Status:  http.StatusUnauthorized,
+					Err:     err,
+					Message: "the session is invalid or has expired, connect your Twitter account again",
+					Code:    "invalid_session",
+				}
+			}
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			c.Abort()
+			return
+		}
+
+		ctx, err := service.OnBehalfOf(c.Request.Context(), userID)
+		if err != nil {
+			if errors.Is(err, domain.ErrTwitterConnectionNotFound) {
+				err = &apperrors.APIError{
+					Status:  http.StatusForbidden,
+					Err:     err,
+					Message: fmt.Sprintf("the Twitter account [%s] has not been connected", userID),
+					Code:    "not_connected",
+				}
+			}
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
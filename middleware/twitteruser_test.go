@@ -0,0 +1,97 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/middleware"
+	"github.com/jake-hansen/followrs/services/mocks"
+)
+
+type userContextKey struct{}
+
+func newTwitterUserRouter(service domain.TwitterConnectService) *gin.Engine {
+	router := gin.New()
+	router.Use(middleware.PublicErrorHandler())
+	router.Use(middleware.TwitterUser(service))
+	router.GET("/test", func(c *gin.Context) {
+		userID, _ := c.Request.Context().Value(userContextKey{}).(string)
+		c.String(http.StatusOK, userID)
+	})
+	return router
+}
+
+func TestTwitterUser(t *testing.T) {
+	t.Run("on-behalf-of-connected-user", func(t *testing.T) {
+		mockService := new(mocks.TwitterConnectService)
+		mockService.On("SessionUser", mock.Anything, "session").Return("1", nil)
+		mockService.On("OnBehalfOf", mock.Anything, "1").Return(context.WithValue(context.Background(), userContextKey{}, "1"), nil)
+		router := newTwitterUserRouter(mockService)
+
+		req, _ := http.NewRequest("GET", "/test", nil)
+		req.AddCookie(&http.Cookie{Name: middleware.SessionCookie, Value: "session"})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "1", w.Body.String())
+	})
+
+	t.Run("no-session", func(t *testing.T) {
+		mockService := new(mocks.TwitterConnectService)
+		router := newTwitterUserRouter(mockService)
+
+		req, _ := http.NewRequest("GET", "/test?as=1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Body.String())
+		mockService.AssertNotCalled(t, "OnBehalfOf", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid-session", func(t *testing.T) {
+		mockService := new(mocks.TwitterConnectService)
+		mockService.On("SessionUser", mock.Anything, "forged").Return("", domain.ErrInvalidSession)
+		router := newTwitterUserRouter(mockService)
+
+		req, _ := http.NewRequest("GET", "/test", nil)
+		req.AddCookie(&http.Cookie{Name: middleware.SessionCookie, Value: "forged"})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var problem middleware.ProblemJSON
+		json.Unmarshal(w.Body.Bytes(), &problem)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, "invalid_session", problem.Code)
+		mockService.AssertNotCalled(t, "OnBehalfOf", mock.Anything, mock.Anything)
+	})
+
+	t.Run("user-not-connected", func(t *testing.T) {
+		mockService := new(mocks.TwitterConnectService)
+		mockService.On("SessionUser", mock.Anything, "session").Return("1", nil)
+		mockService.On("OnBehalfOf", mock.Anything, "1").Return(nil, fmt.Errorf("could not find user: %w", domain.ErrTwitterConnectionNotFound))
+		router := newTwitterUserRouter(mockService)
+
+		req, _ := http.NewRequest("GET", "/test", nil)
+		req.AddCookie(&http.Cookie{Name: middleware.SessionCookie, Value: "session"})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var problem middleware.ProblemJSON
+		json.Unmarshal(w.Body.Bytes(), &problem)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "not_connected", problem.Code)
+	})
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+)
+
+// InMemoryAccountMetricsRepository is an AccountMetricsRepository that keeps
+// every recorded metrics in memory. Metrics are lost when the program exits.
+type InMemoryAccountMetricsRepository struct {
+	mu      sync.RWMutex
+	metrics map[string][]domain.AccountMetrics
+}
+
+// NewInMemoryAccountMetricsRepository creates an empty InMemoryAccountMetricsRepository.
+func NewInMemoryAccountMetricsRepository() domain.AccountMetricsRepository {
+	return &InMemoryAccountMetricsRepository{
+		metrics: make(map[string][]domain.AccountMetrics),
+	}
+}
+
+// Save stores a copy of the given metrics.
+func (r *InMemoryAccountMetricsRepository) Save(ctx context.Context, metrics *domain.AccountMetrics) error {
+	stored := *metrics
+	stored.RecordedAt = stored.RecordedAt.UTC()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := snapshotKey(metrics.Platform, metrics.AccountID)
+	recorded := append(r.metrics[key], stored)
+	sort.SliceStable(recorded, func(i, j int) bool {
+		return recorded[i].RecordedAt.Before(recorded[j].RecordedAt)
+	})
+	r.metrics[key] = recorded
+
+	return nil
+}
+
+// List returns every metrics of the account recorded between from and to.
+func (r *InMemoryAccountMetricsRepository) List(ctx context.Context, platform string, accountID string, from time.Time, to time.Time) ([]domain.AccountMetrics, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var metrics []domain.AccountMetrics
+	for _, recorded := range r.metrics[snapshotKey(platform, accountID)] {
+		if !recorded.RecordedAt.Before(from) && !recorded.RecordedAt.After(to) {
+			metrics = append(metrics, recorded)
+		}
+	}
+
+	return metrics, nil
+}
@@ -0,0 +1,95 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories"
+)
+
+// metricsRepositories returns a new, empty instance of every AccountMetricsRepository implementation.
+func metricsRepositories(t *testing.T) map[string]domain.AccountMetricsRepository {
+	db, err := repositories.OpenSQLiteDatabase(":memory:")
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	sqliteRepo, err := repositories.NewSQLiteAccountMetricsRepository(db)
+	assert.NoError(t, err)
+
+	return map[string]domain.AccountMetricsRepository{
+		"in-memory": repositories.NewInMemoryAccountMetricsRepository(),
+		"sqlite":    sqliteRepo,
+	}
+}
+
+func TestAccountMetricsRepository_List(t *testing.T) {
+	now := time.Now().UTC()
+	first := domain.AccountMetrics{Platform: "twitter", AccountID: "1", RecordedAt: now.Add(-2 * time.Hour), Followers: 10, Following: 5, Tweets: 100, Listed: 1}
+	second := domain.AccountMetrics{Platform: "twitter", AccountID: "1", RecordedAt: now.Add(-time.Hour), Followers: 11, Following: 5, Tweets: 101, Listed: 1}
+	third := domain.AccountMetrics{Platform: "twitter", AccountID: "1", RecordedAt: now, Followers: 12, Following: 6, Tweets: 102, Listed: 2}
+	other := domain.AccountMetrics{Platform: "twitter", AccountID: "2", RecordedAt: now, Followers: 1}
+	hidden := domain.AccountMetrics{Platform: "youtube", AccountID: "1", RecordedAt: now, Tweets: 10, Views: 1000, FollowersHidden: true}
+
+	for name, repo := range metricsRepositories(t) {
+		t.Run(name, func(t *testing.T) {
+			assert.NoError(t, repo.Save(context.Background(), &third))
+			assert.NoError(t, repo.Save(context.Background(), &first))
+			assert.NoError(t, repo.Save(context.Background(), &second))
+			assert.NoError(t, repo.Save(context.Background(), &other))
+			assert.NoError(t, repo.Save(context.Background(), &hidden))
+
+			metrics, err := repo.List(context.Background(), "twitter", "1", now.Add(-3*time.Hour), now)
+			assert.NoError(t, err)
+			assert.Equal(t, []domain.AccountMetrics{first, second, third}, metrics)
+
+			metrics, err = repo.List(context.Background(), "twitter", "1", now.Add(-time.Hour), now.Add(-time.Minute))
+			assert.NoError(t, err)
+			assert.Equal(t, []domain.AccountMetrics{second}, metrics)
+
+			metrics, err = repo.List(context.Background(), "youtube", "1", now.Add(-3*time.Hour), now)
+			assert.NoError(t, err)
+			assert.Equal(t, []domain.AccountMetrics{hidden}, metrics)
+
+			metrics, err = repo.List(context.Background(), "twitter", "3", now.Add(-3*time.Hour), now)
+			assert.NoError(t, err)
+			assert.Empty(t, metrics)
+		})
+	}
+}
+
+func TestSQLiteAccountMetricsRepository_Migrate(t *testing.T) {
+	db, err := repositories.OpenSQLiteDatabase(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	// The table as it was created before views were recorded.
+	_, err = db.Exec(`CREATE TABLE account_metrics (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		platform TEXT NOT NULL,
+		account_id TEXT NOT NULL,
+		recorded_at INTEGER NOT NULL,
+		followers_count INTEGER NOT NULL,
+		following_count INTEGER NOT NULL,
+		tweet_count INTEGER NOT NULL,
+		listed_count INTEGER NOT NULL
+	)`)
+	assert.NoError(t, err)
+	recordedAt := time.Now().UTC()
+	_, err = db.Exec(`INSERT INTO account_metrics
+		(platform, account_id, recorded_at, followers_count, following_count, tweet_count, listed_count)
+		VALUES ('twitter', '1', ?, 10, 5, 100, 1)`, recordedAt.UnixNano())
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		repo, err := repositories.NewSQLiteAccountMetricsRepository(db)
+		assert.NoError(t, err)
+
+		metrics, err := repo.List(context.Background(), "twitter", "1", recordedAt, recordedAt)
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.AccountMetrics{{Platform: "twitter", AccountID: "1", RecordedAt: recordedAt, Followers: 10, Following: 5, Tweets: 100, Listed: 1}}, metrics)
+	}
+}
@@ -0,0 +1,163 @@
+package bluesky
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+)
+
+// maxFollowsResults is the largest page size Bluesky allows when listing the
+// follows of an actor.
+const maxFollowsResults = 100
+
+// maxHandleLength is the length of the longest handle the AT Protocol allows.
+const maxHandleLength = 253
+
+var (
+	// handlePattern matches the handles the AT Protocol allows, which are
+	// domain names such as "alice.bsky.social".
+	handlePattern = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?\.)+[a-zA-Z]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+	// didPattern matches the decentralized identifiers of accounts, such as
+	// "did:plc:z72i7hdynmk6r22z27h6tvur".
+	didPattern = regexp.MustCompile(`^did:[a-z]+:[a-zA-Z0-9._:%-]*[a-zA-Z0-9._-]$`)
+)
+
+// Profile represents the profile of a Bluesky account, which the AT Protocol
+// calls an actor. Counts are only present in detailed profiles, as returned by
+// GetProfile.
+type Profile struct {
+	DID            string     `json:"did"`
+	Handle         string     `json:"handle"`
+	DisplayName    string     `json:"displayName"`
+	Description    string     `json:"description"`
+	Avatar         string     `json:"avatar"`
+	CreatedAt      *time.Time `json:"createdAt"`
+	FollowersCount int64      `json:"followersCount"`
+	FollowsCount   int64      `json:"followsCount"`
+	PostsCount     int64      `json:"postsCount"`
+}
+
+// followersPage is a page of the response of app.bsky.graph.getFollowers.
+type followersPage struct {
+	Followers []Profile `json:"followers"`
+	Cursor    string    `json:"cursor"`
+}
+
+// followsPage is a page of the response of app.bsky.graph.getFollows.
+type followsPage struct {
+	Follows []Profile `json:"follows"`
+	Cursor  string    `json:"cursor"`
+}
+
+// GetProfile returns the detailed profile of the actor with the given handle,
+// such as "alice.bsky.social", or DID.
+func (a *API) GetProfile(ctx context.Context, actor string) (*Profile, error) {
+	actor, err := parseActor(actor)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("actor", actor)
+	profile := new(Profile)
+	if err := a.query(ctx, "app.bsky.actor.getProfile", params, profile); err != nil {
+		return nil, actorError(err)
+	}
+	return profile, nil
+}
+
+// GetFollowers returns every actor that follows the actor with the given
+// handle or DID.
+func (a *API) GetFollowers(ctx context.Context, actor string) ([]Profile, error) {
+	return a.listProfiles(ctx, "app.bsky.graph.getFollowers", actor, func() (*[]Profile, *string, interface{}) {
+		page := new(followersPage)
+		return &page.Followers, &page.Cursor, page
+	})
+}
+
+// GetFollows returns every actor that the actor with the given handle or DID
+// follows.
+func (a *API) GetFollows(ctx context.Context, actor string) ([]Profile, error) {
+	return a.listProfiles(ctx, "app.bsky.graph.getFollows", actor, func() (*[]Profile, *string, interface{}) {
+		page := new(followsPage)
+		return &page.Follows, &page.Cursor, page
+	})
+}
+
+// listProfiles calls the given method for every page of profiles related to
+// the given actor. newPage returns a page to decode each response into, along
+// with the profiles and cursor it decodes into. Pages are requested until
+// Bluesky stops returning a cursor to the next page.
+func (a *API) listProfiles(ctx context.Context, method string, actor string, newPage func() (*[]Profile, *string, interface{})) ([]Profile, error) {
+	actor, err := parseActor(actor)
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []Profile
+	cursor := ""
+	for {
+		params := url.Values{}
+		params.Set("actor", actor)
+		params.Set("limit", strconv.Itoa(maxFollowsResults))
+		if cursor != "" {
+			params.Set("cursor", cursor)
+		}
+
+		pageProfiles, nextCursor, page := newPage()
+		if err := a.query(ctx, method, params, page); err != nil {
+			return nil, actorError(err)
+		}
+		profiles = append(profiles, *pageProfiles...)
+
+		// Bluesky may return the last cursor again rather than none at all
+		// once every page has been returned.
+		if *nextCursor == "" || *nextCursor == cursor {
+			return profiles, nil
+		}
+		cursor = *nextCursor
+	}
+}
+
+// parseActor validates the given handle or DID, removing any leading @ and
+// lowercasing handles, which aren't case-sensitive.
+func parseActor(actor string) (string, error) {
+	actor = strings.TrimPrefix(actor, "@")
+	if didPattern.MatchString(actor) {
+		return actor, nil
+	}
+	if len(actor) <= maxHandleLength && handlePattern.MatchString(actor) {
+		return strings.ToLower(actor), nil
+	}
+	return "", fmt.Errorf("%w: %s", apperrors.ErrInvalidUsername, actor)
+}
+
+// actorError converts the error Bluesky responds with when an actor can't be
+// looked up into the error from apperrors that describes it. Bluesky responds
+// to requests for actors that don't exist, or that are unavailable, with 400
+// Bad Request rather than 404 Not Found.
+func actorError(err error) error {
+	var responseError *apis.ResponseError
+	if !errors.As(err, &responseError) {
+		return err
+	}
+
+	switch {
+	case responseError.Name == "AccountTakedown":
+		return fmt.Errorf("actor %w: %s", apperrors.ErrSuspended, err)
+	case responseError.Name == "AccountDeactivated",
+		responseError.Name == "ActorNotFound",
+		responseError.Name == "InvalidRequest" && strings.Contains(strings.ToLower(responseError.Message), "not found"):
+		return fmt.Errorf("actor %w: %s", apperrors.ErrNotFound, err)
+	}
+	return err
+}
@@ -0,0 +1,188 @@
+package bluesky_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+	"github.com/jake-hansen/followrs/repositories/apis/bluesky"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeJSON(t *testing.T, w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	assert.NoError(t, json.NewEncoder(w).Encode(body))
+}
+
+func writeError(w http.ResponseWriter, name string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	fmt.Fprintf(w, `{"error":%q,"message":%q}`, name, message)
+}
+
+// newTestAPI creates an anonymous API for a Bluesky server that responds with
+// the handlers registered on the returned mux.
+func newTestAPI(t *testing.T) (*http.ServeMux, *bluesky.API) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	api, err := bluesky.NewBlueskyAPI(server.URL, "", "")
+	assert.NoError(t, err)
+	return mux, api
+}
+
+// TestGetProfile tests the GetProfile function of API.
+func TestGetProfile(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mux, api := newTestAPI(t)
+		mux.HandleFunc("/xrpc/app.bsky.actor.getProfile", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "alice.bsky.social", r.URL.Query().Get("actor"))
+			fmt.Fprint(w, `{"did":"did:plc:alice","handle":"alice.bsky.social","displayName":"Alice","createdAt":"2023-04-01T00:00:00.000Z","followersCount":10,"followsCount":20,"postsCount":30}`)
+		})
+
+		profile, err := api.GetProfile(context.Background(), "@Alice.bsky.social")
+		assert.NoError(t, err)
+		assert.Equal(t, "did:plc:alice", profile.DID)
+		assert.Equal(t, "Alice", profile.DisplayName)
+		assert.True(t, time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC).Equal(*profile.CreatedAt))
+		assert.Equal(t, int64(10), profile.FollowersCount)
+		assert.Equal(t, int64(20), profile.FollowsCount)
+		assert.Equal(t, int64(30), profile.PostsCount)
+	})
+
+	t.Run("did", func(t *testing.T) {
+		mux, api := newTestAPI(t)
+		mux.HandleFunc("/xrpc/app.bsky.actor.getProfile", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "did:plc:z72i7hdynmk6r22z27h6tvur", r.URL.Query().Get("actor"))
+			writeJSON(t, w, bluesky.Profile{DID: "did:plc:z72i7hdynmk6r22z27h6tvur"})
+		})
+
+		_, err := api.GetProfile(context.Background(), "did:plc:z72i7hdynmk6r22z27h6tvur")
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid-actor", func(t *testing.T) {
+		_, api := newTestAPI(t)
+
+		for _, actor := range []string{"", "alice", "alice..bsky.social", "alice.bsky.social/", "-alice.bsky.social", "did:plc:"} {
+			profile, err := api.GetProfile(context.Background(), actor)
+			assert.Nil(t, profile, actor)
+			assert.True(t, errors.Is(err, apperrors.ErrInvalidUsername), actor)
+		}
+	})
+
+	errorCases := []struct {
+		name      string
+		errorName string
+		message   string
+		err       error
+	}{
+		{name: "not-found", errorName: "InvalidRequest", message: "Profile not found", err: apperrors.ErrNotFound},
+		{name: "deactivated", errorName: "AccountDeactivated", message: "Account is deactivated", err: apperrors.ErrNotFound},
+		{name: "taken-down", errorName: "AccountTakedown", message: "Account has been suspended", err: apperrors.ErrSuspended},
+	}
+	for _, errorCase := range errorCases {
+		errorCase := errorCase
+		t.Run(errorCase.name, func(t *testing.T) {
+			mux, api := newTestAPI(t)
+			mux.HandleFunc("/xrpc/app.bsky.actor.getProfile", func(w http.ResponseWriter, r *http.Request) {
+				writeError(w, errorCase.errorName, errorCase.message)
+			})
+
+			profile, err := api.GetProfile(context.Background(), "alice.bsky.social")
+			assert.Nil(t, profile)
+			assert.True(t, errors.Is(err, errorCase.err))
+		})
+	}
+
+	t.Run("rate-limited", func(t *testing.T) {
+		reset := time.Now().Add(5 * time.Minute).Truncate(time.Second)
+		mux, api := newTestAPI(t)
+		api.Client.RateLimitPolicy = apis.FailFast
+		mux.HandleFunc("/xrpc/app.bsky.actor.getProfile", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("RateLimit-Limit", "3000")
+			w.Header().Set("RateLimit-Remaining", "0")
+			w.Header().Set("RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			writeJSON(t, w, bluesky.Profile{DID: "did:plc:alice"})
+		})
+
+		_, err := api.GetProfile(context.Background(), "alice.bsky.social")
+		assert.NoError(t, err)
+
+		limit, ok := api.RateLimit(context.Background())
+		assert.True(t, ok)
+		assert.Equal(t, int64(0), limit.Remaining)
+		assert.True(t, reset.Equal(limit.Reset))
+
+		_, err = api.GetProfile(context.Background(), "alice.bsky.social")
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+	})
+}
+
+// TestGetFollows tests the GetFollowers and GetFollows functions of API.
+func TestGetFollows(t *testing.T) {
+	t.Run("paginates", func(t *testing.T) {
+		mux, api := newTestAPI(t)
+		for _, method := range []string{"getFollowers", "getFollows"} {
+			key := map[string]string{"getFollowers": "followers", "getFollows": "follows"}[method]
+			mux.HandleFunc("/xrpc/app.bsky.graph."+method, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "alice.bsky.social", r.URL.Query().Get("actor"))
+				assert.Equal(t, "100", r.URL.Query().Get("limit"))
+				switch r.URL.Query().Get("cursor") {
+				case "":
+					writeJSON(t, w, map[string]interface{}{key: []bluesky.Profile{{DID: "did:plc:1"}, {DID: "did:plc:2"}}, "cursor": "2"})
+				case "2":
+					writeJSON(t, w, map[string]interface{}{key: []bluesky.Profile{{DID: "did:plc:3"}}})
+				default:
+					t.Errorf("unexpected cursor %s", r.URL.Query().Get("cursor"))
+				}
+			})
+		}
+
+		followers, err := api.GetFollowers(context.Background(), "alice.bsky.social")
+		assert.NoError(t, err)
+		assert.Equal(t, []bluesky.Profile{{DID: "did:plc:1"}, {DID: "did:plc:2"}, {DID: "did:plc:3"}}, followers)
+
+		follows, err := api.GetFollows(context.Background(), "alice.bsky.social")
+		assert.NoError(t, err)
+		assert.Len(t, follows, 3)
+	})
+
+	t.Run("repeated-cursor", func(t *testing.T) {
+		mux, api := newTestAPI(t)
+		requests := 0
+		mux.HandleFunc("/xrpc/app.bsky.graph.getFollowers", func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.URL.Query().Get("cursor") == "" {
+				writeJSON(t, w, map[string]interface{}{"followers": []bluesky.Profile{{DID: "did:plc:1"}}, "cursor": "1"})
+				return
+			}
+			writeJSON(t, w, map[string]interface{}{"followers": []bluesky.Profile{}, "cursor": "1"})
+		})
+
+		followers, err := api.GetFollowers(context.Background(), "alice.bsky.social")
+		assert.NoError(t, err)
+		assert.Len(t, followers, 1)
+		assert.Equal(t, 2, requests)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mux, api := newTestAPI(t)
+		mux.HandleFunc("/xrpc/app.bsky.graph.getFollows", func(w http.ResponseWriter, r *http.Request) {
+			writeError(w, "InvalidRequest", "Profile not found")
+		})
+
+		follows, err := api.GetFollows(context.Background(), "alice.bsky.social")
+		assert.Nil(t, follows)
+		assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+	})
+}
@@ -0,0 +1,80 @@
+package bluesky
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jake-hansen/followrs/repositories/apis"
+)
+
+// rateLimitEndpoint is the name of the rate limit of every request to the
+// Bluesky API. Bluesky limits the requests of each account, or of each IP
+// address for anonymous requests, across every endpoint that is read from.
+const rateLimitEndpoint = "xrpc"
+
+// rateLimitHeaders are the headers in which Bluesky reports its rate limit.
+var rateLimitHeaders = apis.RateLimitHeaders{Remaining: "ratelimit-remaining", Reset: "ratelimit-reset"}
+
+// API provides the services needed to interact with the Bluesky API, which is
+// made up of the XRPC methods of the AT Protocol.
+type API struct {
+	Client *apis.API
+}
+
+// decodeError decodes the XRPC error a Bluesky server describes in the body
+// of a failed response, which names the error, such as "InvalidRequest", and
+// usually describes it.
+func decodeError(httpError *apis.HTTPError) *apis.ResponseError {
+	responseError := &apis.ResponseError{HTTPError: httpError}
+
+	var body struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(httpError.Body, &body); err == nil {
+		responseError.Name = body.Error
+		responseError.Message = body.Message
+	}
+	return responseError
+}
+
+// NewBlueskyAPI creates an API that requests the Bluesky server at the given
+// URL, such as "https://bsky.social", as the account with the given
+// identifier and app password. If they are empty, requests are made
+// anonymously, which requires a server that allows them, such as
+// "https://public.api.bsky.app".
+func NewBlueskyAPI(baseURL string, identifier string, password string) (*API, error) {
+	auth := NewSessionAuth(identifier, password)
+
+	api, err := apis.NewAPI(baseURL, auth, auth.Attach, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not create Bluesky API: %w", err)
+	}
+	// Unlike the IETF draft its headers are named after, Bluesky gives the
+	// time its rate limit resets in ratelimit-reset as seconds since the Unix
+	// epoch, rather than the seconds until it does.
+	api.RetryPolicy.ResetHeader = "ratelimit-reset"
+	api.RateLimitHeaders = rateLimitHeaders
+	api.DecodeError = decodeError
+
+	auth.client = api.Client
+	auth.serviceURL = strings.TrimSuffix(api.BaseURL.String(), "/")
+
+	return &API{Client: api}, nil
+}
+
+// RateLimit returns the rate limit of requests to Bluesky, and whether it is
+// known.
+func (a *API) RateLimit(ctx context.Context) (apis.RateLimit, bool) {
+	return a.Client.RateLimit(ctx, rateLimitEndpoint)
+}
+
+// query calls the XRPC query method with the given name, such as
+// "app.bsky.actor.getProfile", with the given parameters, as by apis.API's Get.
+func (a *API) query(ctx context.Context, method string, params url.Values, body interface{}) error {
+	_, err := a.Client.Get(ctx, rateLimitEndpoint, fmt.Sprintf("/xrpc/%s?%s", method, params.Encode()), body)
+	return err
+}
@@ -0,0 +1,234 @@
+package bluesky
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+)
+
+const (
+	// createSessionPath is the path of the endpoint that exchanges the
+	// identifier and password of an account for a session.
+	createSessionPath = "/xrpc/com.atproto.server.createSession"
+
+	// refreshSessionPath is the path of the endpoint that exchanges the
+	// refresh token of a session for a new session.
+	refreshSessionPath = "/xrpc/com.atproto.server.refreshSession"
+)
+
+// maxSessionResponseSize is the largest session response that is read.
+const maxSessionResponseSize = 1 << 16
+
+// expiryDelta is how long before the access JWT of a session expires that the
+// session is refreshed. Access JWTs only last a couple of hours, so the margin
+// is kept short.
+const expiryDelta = 30 * time.Second
+
+// session is a session of an account on a Bluesky server.
+type session struct {
+	DID        string `json:"did"`
+	Handle     string `json:"handle"`
+	AccessJWT  string `json:"accessJwt"`
+	RefreshJWT string `json:"refreshJwt"`
+}
+
+// SessionAuth authenticates requests to Bluesky with the access token of a
+// session of an account, which is created from the identifier and app
+// password of the account. Access tokens expire after a few hours, after which
+// the session is refreshed with its refresh token, or created again if that
+// has expired too. Without an identifier and password, requests are made
+// anonymously, which the public Bluesky API allows for reading profiles and
+// follows.
+type SessionAuth struct {
+	Identifier string // Handle, DID or email address of the account.
+	Password   string // App password of the account.
+
+	client     *retryablehttp.Client
+	serviceURL string
+	now        func() time.Time
+
+	mu      sync.Mutex
+	session session
+}
+
+// NewSessionAuth creates a SessionAuth that creates sessions of the account
+// with the given identifier and app password, which may both be empty.
+func NewSessionAuth(identifier string, password string) *SessionAuth {
+	return &SessionAuth{
+		Identifier: identifier,
+		Password:   password,
+		now:        time.Now,
+	}
+}
+
+// IsAuthenticated determines if the access token is present and unexpired,
+// or if requests are made anonymously.
+func (a *SessionAuth) IsAuthenticated() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return !a.hasCredentials() || a.unexpired(a.session.AccessJWT)
+}
+
+// Authenticate refreshes the session, or creates a new one if it can't be
+// refreshed, unless the access token is still valid.
+func (a *SessionAuth) Authenticate(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.hasCredentials() || a.unexpired(a.session.AccessJWT) {
+		return nil
+	}
+	if a.client == nil {
+		return fmt.Errorf("no Bluesky server configured to create sessions with: %w", apperrors.ErrUnauthorized)
+	}
+
+	if a.unexpired(a.session.RefreshJWT) {
+		var refreshed session
+		err := a.postSessionRequest(ctx, refreshSessionPath, a.session.RefreshJWT, nil, &refreshed)
+		if err == nil && refreshed.AccessJWT != "" {
+			a.session = refreshed
+			return nil
+		}
+	}
+
+	credentials := map[string]string{
+		"identifier": a.Identifier,
+		"password":   a.Password,
+	}
+	var created session
+	if err := a.postSessionRequest(ctx, createSessionPath, "", credentials, &created); err != nil {
+		return fmt.Errorf("could not create Bluesky session: %w", err)
+	}
+	if created.AccessJWT == "" {
+		return errors.New("could not create Bluesky session: response did not contain an access token")
+	}
+	a.session = created
+	return nil
+}
+
+// Invalidate discards the access token so that the session is refreshed by
+// the next call to Authenticate.
+func (a *SessionAuth) Invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.session.AccessJWT = ""
+}
+
+// Attach attaches the access token to a request, if there is one.
+func (a *SessionAuth) Attach(req *retryablehttp.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.session.AccessJWT != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.session.AccessJWT))
+	}
+}
+
+// hasCredentials determines if an identifier and password are configured.
+func (a *SessionAuth) hasCredentials() bool {
+	return a.Identifier != "" && a.Password != ""
+}
+
+// unexpired determines if the given token is present and, if it states when
+// it expires, hasn't expired. The caller must hold a.mu.
+func (a *SessionAuth) unexpired(token string) bool {
+	if token == "" {
+		return false
+	}
+	expiry, ok := tokenExpiry(token)
+	return !ok || a.now().Before(expiry.Add(-expiryDelta))
+}
+
+// tokenExpiry returns the time the given JWT expires, as stated by its exp
+// claim. The signature of the token isn't verified, since it is only used to
+// decide when to refresh the token.
+func tokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Expiry int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Expiry == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.Expiry, 0), true
+}
+
+// postSessionRequest posts to the session endpoint at the given path and
+// decodes the response into v. The request is authenticated with the given
+// bearer token, if any, and its body is the given value encoded as JSON, if
+// it isn't nil. The caller must hold a.mu.
+func (a *SessionAuth) postSessionRequest(ctx context.Context, path string, token string, body interface{}, v interface{}) error {
+	var rawBody []byte
+	if body != nil {
+		var err error
+		if rawBody, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	sessionURL := a.serviceURL + path
+	req, err := retryablehttp.NewRequest(http.MethodPost, sessionURL, bytes.NewReader(rawBody))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	response, err := a.client.Do(req)
+	if err != nil {
+		if response != nil {
+			response.Body.Close()
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("%w: %s", apperrors.ErrUpstreamUnavailable, err.Error())
+	}
+	defer response.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(io.LimitReader(response.Body, maxSessionResponseSize))
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return decodeError(&apis.HTTPError{
+			URL:        sessionURL,
+			StatusCode: response.StatusCode,
+			Header:     response.Header,
+			Body:       responseBody,
+		})
+	}
+
+	if err := json.Unmarshal(responseBody, v); err != nil {
+		return fmt.Errorf("could not decode body: %w", err)
+	}
+	return nil
+}
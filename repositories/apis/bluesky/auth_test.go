@@ -0,0 +1,195 @@
+package bluesky_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+	"github.com/jake-hansen/followrs/repositories/apis/bluesky"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestJWT creates an unsigned JWT that expires at the given time.
+func newTestJWT(name string, expiry time.Time) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":%q,"exp":%d}`, name, expiry.Unix())))
+	return fmt.Sprintf("%s.%s.signature", header, payload)
+}
+
+// sessionServer is a Bluesky server that issues sessions and records the
+// access tokens that getProfile is requested with.
+type sessionServer struct {
+	mux *http.ServeMux
+
+	mu           sync.Mutex
+	sessions     []map[string]string // Sessions returned by createSession or refreshSession, in order.
+	created      int
+	refreshed    int
+	credentials  map[string]string
+	refreshToken string
+	tokens       []string
+	reject       map[string]bool // Access tokens that getProfile responds to with 401.
+}
+
+func newSessionServer(t *testing.T) (*sessionServer, *httptest.Server) {
+	s := &sessionServer{mux: http.NewServeMux(), reject: make(map[string]bool)}
+	server := httptest.NewServer(s.mux)
+	t.Cleanup(server.Close)
+
+	s.mux.HandleFunc("/xrpc/com.atproto.server.createSession", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		assert.Equal(t, http.MethodPost, r.Method)
+		var credentials map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&credentials))
+		s.credentials = credentials
+		if credentials["password"] != "app-password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"AuthenticationRequired","message":"Invalid identifier or password"}`)
+			return
+		}
+		s.created++
+		s.writeSession(t, w)
+	})
+	s.mux.HandleFunc("/xrpc/com.atproto.server.refreshSession", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		assert.Equal(t, http.MethodPost, r.Method)
+		s.refreshToken = r.Header.Get("Authorization")
+		s.refreshed++
+		s.writeSession(t, w)
+	})
+	s.mux.HandleFunc("/xrpc/app.bsky.actor.getProfile", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		token := r.Header.Get("Authorization")
+		s.tokens = append(s.tokens, token)
+		if s.reject[token] {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"InvalidToken","message":"Bad token"}`)
+			return
+		}
+		writeJSON(t, w, bluesky.Profile{DID: "did:plc:alice", Handle: "alice.bsky.social"})
+	})
+	return s, server
+}
+
+// writeSession responds with the next session. The caller must hold s.mu.
+func (s *sessionServer) writeSession(t *testing.T, w http.ResponseWriter) {
+	session := s.sessions[0]
+	s.sessions = s.sessions[1:]
+	writeJSON(t, w, session)
+}
+
+func newSession(access string, refresh string) map[string]string {
+	return map[string]string{
+		"did":        "did:plc:alice",
+		"handle":     "alice.bsky.social",
+		"accessJwt":  access,
+		"refreshJwt": refresh,
+	}
+}
+
+// TestSessionAuth tests that requests are authenticated with the sessions
+// SessionAuth creates and refreshes.
+func TestSessionAuth(t *testing.T) {
+	hourFromNow := time.Now().Add(time.Hour)
+	hourAgo := time.Now().Add(-time.Hour)
+
+	t.Run("anonymous", func(t *testing.T) {
+		s, server := newSessionServer(t)
+		api, _ := bluesky.NewBlueskyAPI(server.URL, "", "")
+
+		_, err := api.GetProfile(context.Background(), "alice.bsky.social")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{""}, s.tokens)
+		assert.Equal(t, 0, s.created)
+	})
+
+	t.Run("creates-session", func(t *testing.T) {
+		s, server := newSessionServer(t)
+		access := newTestJWT("access", hourFromNow)
+		s.sessions = append(s.sessions, newSession(access, newTestJWT("refresh", hourFromNow)))
+		api, _ := bluesky.NewBlueskyAPI(server.URL, "alice.bsky.social", "app-password")
+
+		for i := 0; i < 2; i++ {
+			_, err := api.GetProfile(context.Background(), "alice.bsky.social")
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, map[string]string{"identifier": "alice.bsky.social", "password": "app-password"}, s.credentials)
+		assert.Equal(t, 1, s.created)
+		assert.Equal(t, []string{"Bearer " + access, "Bearer " + access}, s.tokens)
+	})
+
+	t.Run("refreshes-expired-session", func(t *testing.T) {
+		s, server := newSessionServer(t)
+		expired := newTestJWT("expired", hourAgo)
+		refresh := newTestJWT("refresh", hourFromNow)
+		access := newTestJWT("access", hourFromNow)
+		s.sessions = append(s.sessions, newSession(expired, refresh), newSession(access, newTestJWT("refresh2", hourFromNow)))
+		api, _ := bluesky.NewBlueskyAPI(server.URL, "alice.bsky.social", "app-password")
+
+		for i := 0; i < 2; i++ {
+			_, err := api.GetProfile(context.Background(), "alice.bsky.social")
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 1, s.created)
+		assert.Equal(t, 1, s.refreshed)
+		assert.Equal(t, "Bearer "+refresh, s.refreshToken)
+		assert.Equal(t, []string{"Bearer " + expired, "Bearer " + access}, s.tokens)
+	})
+
+	t.Run("creates-session-when-refresh-expired", func(t *testing.T) {
+		s, server := newSessionServer(t)
+		expired := newTestJWT("expired", hourAgo)
+		access := newTestJWT("access", hourFromNow)
+		s.sessions = append(s.sessions, newSession(expired, newTestJWT("refresh", hourAgo)), newSession(access, newTestJWT("refresh2", hourFromNow)))
+		api, _ := bluesky.NewBlueskyAPI(server.URL, "alice.bsky.social", "app-password")
+
+		for i := 0; i < 2; i++ {
+			_, err := api.GetProfile(context.Background(), "alice.bsky.social")
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 2, s.created)
+		assert.Equal(t, 0, s.refreshed)
+		assert.Equal(t, "Bearer "+access, s.tokens[1])
+	})
+
+	t.Run("refreshes-rejected-session", func(t *testing.T) {
+		s, server := newSessionServer(t)
+		rejected := newTestJWT("rejected", hourFromNow)
+		access := newTestJWT("access", hourFromNow)
+		s.reject["Bearer "+rejected] = true
+		s.sessions = append(s.sessions, newSession(rejected, newTestJWT("refresh", hourFromNow)), newSession(access, newTestJWT("refresh2", hourFromNow)))
+		api, _ := bluesky.NewBlueskyAPI(server.URL, "alice.bsky.social", "app-password")
+
+		_, err := api.GetProfile(context.Background(), "alice.bsky.social")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, s.refreshed)
+		assert.Equal(t, []string{"Bearer " + rejected, "Bearer " + access}, s.tokens)
+	})
+
+	t.Run("invalid-credentials", func(t *testing.T) {
+		s, server := newSessionServer(t)
+		api, _ := bluesky.NewBlueskyAPI(server.URL, "alice.bsky.social", "wrong-password")
+
+		_, err := api.GetProfile(context.Background(), "alice.bsky.social")
+		assert.True(t, errors.Is(err, apperrors.ErrUnauthorized))
+		var responseError *apis.ResponseError
+		assert.True(t, errors.As(err, &responseError))
+		assert.Equal(t, "AuthenticationRequired", responseError.Name)
+		assert.Empty(t, s.tokens)
+	})
+}
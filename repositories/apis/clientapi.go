@@ -1,12 +1,17 @@
 package apis
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jake-hansen/followrs/apperrors"
 )
 
 // RequestFunc is a function that is executed on a request and modifies
@@ -33,6 +38,30 @@ type API struct {
 
 	// AfterResponse allows a user-supplied function to be called after each response.
 	AfterResponse ResponseFunc
+
+	// Timeout limits how long each call to Do may take, including retries. Calls
+	// are only limited by their context when Timeout is zero.
+	Timeout time.Duration
+
+	// RateLimits keeps track of the API's rate limits. It may be shared with
+	// other APIs whose requests count towards the same limits.
+	RateLimits *RateLimitRegistry
+
+	// RateLimitPolicy determines what happens to requests whose rate limit is
+	// exhausted, unless their context carries a policy of its own.
+	RateLimitPolicy RateLimitPolicy
+
+	// RetryPolicy decides which failed requests the Client retries, and when.
+	RetryPolicy *RetryPolicy
+
+	// RateLimitHeaders are the headers in which the API reports the rate
+	// limits of requests made by Get. Rate limits are not updated from
+	// responses if they aren't set.
+	RateLimitHeaders RateLimitHeaders
+
+	// DecodeError, if not nil, decodes the errors the API describes in the
+	// bodies of failed responses to requests made by Get.
+	DecodeError ErrorDecoder
 }
 
 // Auth contains the functions needed to authenticate to a consumable API.
@@ -40,6 +69,15 @@ type Auth interface {
 	// IsAuthenticated determines if we are authenticated to the API at the current moment.
 	IsAuthenticated() bool
 
+	// Authenticate obtains the credentials that are attached to requests. Do calls
+	// Authenticate before each request that is sent while IsAuthenticated is false.
+	Authenticate(ctx context.Context) error
+
+	// Invalidate discards credentials that the API has rejected, so that new ones
+	// are obtained by the next call to Authenticate. Credentials that can't be
+	// obtained again should be kept, leaving IsAuthenticated true.
+	Invalidate()
+
 	// Attach attaches some sort of authentication credentials to a request. It is up to
 	// the developer to determine how often the credentials need to be attached, as well
 	// as what attaching credentials does in the context of the implementing API.
@@ -54,6 +92,91 @@ type Auth interface {
 	Attach(req *retryablehttp.Request)
 }
 
+// AttemptAuth is implemented by Auths whose credentials are only valid for a
+// single attempt of a request, such as signatures that include a nonce.
+// AttachAttempt is called on every attempt the Client makes, including retries
+// and the attempt made after credentials are invalidated, rather than once
+// like Attach. The attempt fails without being sent if it returns an error.
+type AttemptAuth interface {
+	AttachAttempt(req *http.Request) error
+}
+
+// attemptTransport is an http.RoundTripper that attaches the credentials of
+// an AttemptAuth, if the request is authenticated with one, to each attempt
+// before sending it with the underlying RoundTripper.
+type attemptTransport struct {
+	api  *API
+	base http.RoundTripper
+}
+
+// RoundTrip attaches the attempt's credentials to a copy of the request, which
+// RoundTrippers must not modify, and sends it.
+func (t *attemptTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	auth, ok := t.api.auth(req.Context()).(AttemptAuth)
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	attempt := req.Clone(req.Context())
+	if err := auth.AttachAttempt(attempt); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("could not authenticate request to %s: %w", req.URL, err)
+	}
+	return t.base.RoundTrip(attempt)
+}
+
+// UnauthenticatedClient returns a Client that retries requests like the API's
+// Client, but doesn't attach the credentials of an AttemptAuth to each
+// attempt. It is for requests that carry credentials of their own, such as
+// requests for tokens, which an AttemptAuth would otherwise replace.
+func (api *API) UnauthenticatedClient() *retryablehttp.Client {
+	httpClient := *api.Client.HTTPClient
+	if attempt, ok := httpClient.Transport.(*attemptTransport); ok {
+		httpClient.Transport = attempt.base
+	}
+
+	client := retryablehttp.NewClient()
+	client.HTTPClient = &httpClient
+	client.Logger = api.Client.Logger
+	client.RetryWaitMin = api.Client.RetryWaitMin
+	client.RetryWaitMax = api.Client.RetryWaitMax
+	client.RetryMax = api.Client.RetryMax
+	client.RequestLogHook = api.Client.RequestLogHook
+	client.ResponseLogHook = api.Client.ResponseLogHook
+	client.CheckRetry = api.Client.CheckRetry
+	client.Backoff = api.Client.Backoff
+	client.ErrorHandler = api.Client.ErrorHandler
+	return client
+}
+
+// Identifier is implemented by Auths whose requests are rate limited
+// separately from those of other Auths, such as Auths that make requests on
+// behalf of a particular user. Requests made with Auths that don't implement
+// Identifier share their rate limits.
+type Identifier interface {
+	// Identity returns a string that identifies who requests are made as. It
+	// must not contain credentials.
+	Identity() string
+}
+
+// authContextKey is the key of the Auth carried by a context.
+type authContextKey struct{}
+
+// WithAuth returns a copy of ctx carrying the given Auth. Requests made by Do
+// with the returned context are authenticated with the given Auth instead of
+// the API's own Auth, such as to make them on behalf of a particular user.
+func WithAuth(ctx context.Context, auth Auth) context.Context {
+	return context.WithValue(ctx, authContextKey{}, auth)
+}
+
+// authFromContext returns the Auth carried by ctx, or nil if there is none.
+func authFromContext(ctx context.Context) Auth {
+	auth, _ := ctx.Value(authContextKey{}).(Auth)
+	return auth
+}
+
 // NewAPI creates a new API to be consumed.
 func NewAPI(baseURL string, auth Auth, requestFunc RequestFunc, responseFunc ResponseFunc) (*API, error) {
 	base, err := url.Parse(baseURL)
@@ -62,14 +185,26 @@ func NewAPI(baseURL string, auth Auth, requestFunc RequestFunc, responseFunc Res
 	}
 
 	defaultClient := retryablehttp.NewClient()
+	// Return the final response once retries are exhausted, rather than
+	// discarding it, so that its status and body can be reported.
+	defaultClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
+
+	retryPolicy := NewRetryPolicy(DefaultMaxRetryWait)
+	defaultClient.CheckRetry = retryPolicy.CheckRetry
+	defaultClient.Backoff = retryPolicy.Backoff
+	defaultClient.RequestLogHook = countRetry
 
 	api := &API{
-		BaseURL:       base,
-		Auth:          &auth,
-		Client:        defaultClient,
-		BeforeRequest: requestFunc,
-		AfterResponse: responseFunc,
+		BaseURL:         base,
+		Auth:            &auth,
+		Client:          defaultClient,
+		BeforeRequest:   requestFunc,
+		AfterResponse:   responseFunc,
+		RateLimits:      NewRateLimitRegistry(),
+		RateLimitPolicy: FailFast,
+		RetryPolicy:     retryPolicy,
 	}
+	defaultClient.HTTPClient.Transport = &attemptTransport{api: api, base: defaultClient.HTTPClient.Transport}
 	return api, nil
 }
 
@@ -85,10 +220,31 @@ func NewAPI(baseURL string, auth Auth, requestFunc RequestFunc, responseFunc Res
 //
 // (be careful about trailing /'s)
 //
-func (api *API) Do(request *retryablehttp.Request, body interface{}) (*http.Response, error) {
-	if api.BeforeRequest != nil {
-		api.BeforeRequest(request)
+// The request is cancelled when the given context is done or the API's
+// Timeout elapses, whichever happens first. If the API responds with a status
+// other than 200 OK, the returned error is an *HTTPError.
+//
+// If the API's Auth isn't authenticated, Do authenticates before sending the
+// request. If the API rejects the request with 401 Unauthorized, the Auth's
+// credentials are invalidated and, if new ones can be obtained, the request is
+// sent once more. An Auth carried by the context, as by WithAuth, is used in
+// place of the API's Auth.
+//
+// Failed requests are retried according to the API's RetryPolicy. Retries are
+// counted by the RetryCounter carried by the context, as by WithRetryCounter,
+// and reported by any returned *HTTPError.
+func (api *API) Do(ctx context.Context, request *retryablehttp.Request, body interface{}) (*http.Response, error) {
+	if api.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, api.Timeout)
+		defer cancel()
 	}
+	counter := retryCounterFromContext(ctx)
+	if counter == nil {
+		ctx, counter = WithRetryCounter(ctx)
+	}
+	retries := counter.Retries()
+	request = request.WithContext(ctx)
 
 	newURL, err := url.Parse(fmt.Sprintf("%s%s", api.BaseURL, request.URL))
 	if err != nil {
@@ -96,13 +252,31 @@ func (api *API) Do(request *retryablehttp.Request, body interface{}) (*http.Resp
 	}
 	request.URL = newURL
 
-	response, err := api.Client.Do(request)
+	response, err := api.send(ctx, request)
 	if err != nil {
 		return nil, err
 	}
+	if response.StatusCode == http.StatusUnauthorized && api.invalidate(ctx) {
+		response.Body.Close()
+		response, err = api.send(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request to %s returned code %d", request.URL, response.StatusCode)
+		errorBody, err := ioutil.ReadAll(io.LimitReader(response.Body, maxErrorBodySize))
+		if err != nil {
+			return nil, fmt.Errorf("could not read body of failed request to %s: %w", request.URL, err)
+		}
+		return nil, &HTTPError{
+			URL:        request.URL.String(),
+			StatusCode: response.StatusCode,
+			Header:     response.Header,
+			Body:       errorBody,
+			Retries:    counter.Retries() - retries,
+		}
 	}
 
 	err = json.NewDecoder(response.Body).Decode(body)
@@ -117,3 +291,76 @@ func (api *API) Do(request *retryablehttp.Request, body interface{}) (*http.Resp
 
 	return response, err
 }
+
+// send authenticates, if needed, and sends the request. BeforeRequest is
+// called on the request before it is sent. If the context carries an Auth,
+// its credentials are attached to the request after BeforeRequest is called,
+// replacing any attached by the API's own Auth.
+func (api *API) send(ctx context.Context, request *retryablehttp.Request) (*http.Response, error) {
+	auth := api.auth(ctx)
+	if auth != nil && !auth.IsAuthenticated() {
+		if err := auth.Authenticate(ctx); err != nil {
+			return nil, fmt.Errorf("could not authenticate request to %s: %w", request.URL, err)
+		}
+	}
+
+	if api.BeforeRequest != nil {
+		api.BeforeRequest(request)
+	}
+	if contextAuth := authFromContext(ctx); contextAuth != nil {
+		contextAuth.Attach(request)
+	}
+
+	response, err := api.Client.Do(request)
+	if err != nil {
+		if response != nil {
+			response.Body.Close()
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("request to %s was not completed: %w", request.URL, ctxErr)
+		}
+		return nil, fmt.Errorf("%w: %s", apperrors.ErrUpstreamUnavailable, err.Error())
+	}
+	return response, nil
+}
+
+// invalidate invalidates the credentials of the Auth used for requests with
+// the given context after they were rejected, and reports whether new
+// credentials can be obtained.
+func (api *API) invalidate(ctx context.Context) bool {
+	auth := api.auth(ctx)
+	if auth == nil {
+		return false
+	}
+	auth.Invalidate()
+	return !auth.IsAuthenticated()
+}
+
+// auth returns the Auth used for requests with the given context: the Auth
+// carried by the context, if any, or else the API's Auth. It returns nil if
+// there is neither.
+func (api *API) auth(ctx context.Context) Auth {
+	if auth := authFromContext(ctx); auth != nil {
+		return auth
+	}
+	if api.Auth == nil {
+		return nil
+	}
+	return *api.Auth
+}
+
+// statusError returns the error from apperrors that describes an unsuccessful
+// HTTP status, or nil if there is none.
+func statusError(status int) error {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return apperrors.ErrUnauthorized
+	case status == http.StatusNotFound:
+		return apperrors.ErrNotFound
+	case status == http.StatusTooManyRequests:
+		return apperrors.ErrRateLimited
+	case status >= http.StatusInternalServerError:
+		return apperrors.ErrUpstreamUnavailable
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package apis
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// PublicDialer returns a Dialer that refuses to connect to addresses that
+// aren't public, as determined by IsPublicIP. It is meant for APIs whose hosts
+// are chosen by users, so that they can't be used to reach the network the
+// server runs on. Addresses are checked once their host has been resolved, so
+// names that resolve to other addresses, even after having been checked, are
+// refused as well.
+func PublicDialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+				return fmt.Errorf("refusing to connect to %s, which is not a public address", host)
+			}
+			return nil
+		},
+	}
+}
+
+// IsPublicIP determines if the IP address is reachable on the public internet,
+// rather than being a loopback, private, link-local, multicast or unspecified
+// address.
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || isPrivateIP(ip) || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// privateNetworks are the networks reserved for private use by RFC 1918 and
+// RFC 4193, and the shared address space of RFC 6598.
+var privateNetworks = []*net.IPNet{
+	mustParseCIDR("10.0.0.0/8"),
+	mustParseCIDR("172.16.0.0/12"),
+	mustParseCIDR("192.168.0.0/16"),
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("fc00::/7"),
+}
+
+// isPrivateIP determines if the IP address belongs to one of privateNetworks.
+func isPrivateIP(ip net.IP) bool {
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+// ResolvePublic resolves the given host, which may include a port, and fails
+// if any of its addresses is not public, as determined by IsPublicIP.
+func ResolvePublic(ctx context.Context, host string) error {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("could not resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if !IsPublicIP(addr.IP) {
+			return fmt.Errorf("%s resolves to %s, which is not a public address", host, addr.IP)
+		}
+	}
+	return nil
+}
+
+// SetDialer makes the API connect to hosts with the given Dialer.
+func (api *API) SetDialer(dialer *net.Dialer) {
+	transport := api.Client.HTTPClient.Transport
+	if attempt, ok := transport.(*attemptTransport); ok {
+		transport = attempt.base
+	}
+	if t, ok := transport.(*http.Transport); ok {
+		t.DialContext = dialer.DialContext
+	}
+}
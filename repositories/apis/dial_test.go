@@ -0,0 +1,60 @@
+package apis_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jake-hansen/followrs/repositories/apis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIsPublicIP tests the IsPublicIP function.
+func TestIsPublicIP(t *testing.T) {
+	for address, public := range map[string]bool{
+		"93.184.216.34":        true,
+		"2606:2800:220:1::248": true,
+		"127.0.0.1":            false,
+		"::1":                  false,
+		"10.1.2.3":             false,
+		"172.20.0.1":           false,
+		"192.168.1.1":          false,
+		"100.64.0.1":           false,
+		"169.254.169.254":      false,
+		"fe80::1":              false,
+		"fd00::1":              false,
+		"0.0.0.0":              false,
+		"224.0.0.1":            false,
+		"::ffff:127.0.0.1":     false,
+	} {
+		assert.Equal(t, public, apis.IsPublicIP(net.ParseIP(address)), address)
+	}
+}
+
+// TestResolvePublic tests the ResolvePublic function.
+func TestResolvePublic(t *testing.T) {
+	assert.NoError(t, apis.ResolvePublic(context.Background(), "93.184.216.34:443"))
+	assert.Error(t, apis.ResolvePublic(context.Background(), "127.0.0.1:8080"))
+	assert.Error(t, apis.ResolvePublic(context.Background(), "localhost"))
+}
+
+// TestSetDialer tests that an API with a PublicDialer refuses to connect to
+// addresses that aren't public.
+func TestSetDialer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not have been sent")
+	}))
+	defer server.Close()
+
+	api, err := apis.NewAPI(server.URL, nil, nil, nil)
+	assert.NoError(t, err)
+	api.SetDialer(apis.PublicDialer())
+	api.Client.RetryMax = 0
+
+	req, _ := retryablehttp.NewRequest("GET", "/", nil)
+	_, err = api.Do(context.Background(), req, new(struct{}))
+	assert.Error(t, err)
+}
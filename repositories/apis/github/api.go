@@ -0,0 +1,100 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+)
+
+// apiVersion is the version of the GitHub REST API that requests are made to.
+const apiVersion = "2022-11-28"
+
+// userAgent identifies followrs to GitHub, which rejects requests without a
+// User-Agent.
+const userAgent = "followrs"
+
+// coreResource is the rate limit resource of the REST API endpoints that
+// aren't limited separately, which includes every endpoint that is used.
+const coreResource = "core"
+
+// rateLimitHeaders are the headers in which GitHub reports the rate limit of
+// the resource a request counts towards. GitHub limits requests by resource,
+// such as "core" or "search", rather than by endpoint.
+var rateLimitHeaders = apis.RateLimitHeaders{
+	Remaining: "x-ratelimit-remaining",
+	Reset:     "x-ratelimit-reset",
+	Resource:  "x-ratelimit-resource",
+}
+
+// API provides the services needed to interact with the GitHub REST API.
+type API struct {
+	Client *apis.API
+}
+
+// decodeError decodes the error GitHub describes in the body of a failed
+// response. GitHub rejects requests that exceed a rate limit with 403
+// Forbidden, rather than 429 Too Many Requests, so they are told apart from
+// requests that are forbidden otherwise by their headers: requests that
+// exceed the primary rate limit are rejected once none remain, and those that
+// exceed a secondary rate limit are told when to retry.
+func decodeError(httpError *apis.HTTPError) *apis.ResponseError {
+	responseError := &apis.ResponseError{HTTPError: httpError}
+
+	var body struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(httpError.Body, &body); err == nil {
+		responseError.Message = body.Message
+	}
+
+	if httpError.StatusCode == http.StatusForbidden || httpError.StatusCode == http.StatusTooManyRequests {
+		limit, ok := rateLimitHeaders.Parse(httpError.Header)
+		if (ok && limit.Remaining == 0) || httpError.Header.Get("Retry-After") != "" {
+			responseError.Kind = apperrors.ErrRateLimited
+		}
+	}
+	return responseError
+}
+
+// NewGitHubAPI creates an API that requests the GitHub REST API at the given
+// URL, such as "https://api.github.com", authenticated with the given personal
+// access token, if it isn't empty.
+func NewGitHubAPI(baseURL string, token string) (*API, error) {
+	auth := NewTokenAuth(token)
+
+	setHeaders := func(req *retryablehttp.Request) {
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("X-GitHub-Api-Version", apiVersion)
+		req.Header.Set("User-Agent", userAgent)
+		auth.Attach(req)
+	}
+
+	api, err := apis.NewAPI(baseURL, auth, setHeaders, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not create GitHub API: %w", err)
+	}
+	// Secondary rate limits are reported with Retry-After, but the primary
+	// rate limit only reports when it resets.
+	api.RetryPolicy.ResetHeader = "x-ratelimit-reset"
+	api.RateLimitHeaders = rateLimitHeaders
+	api.DecodeError = decodeError
+
+	return &API{Client: api}, nil
+}
+
+// RateLimit returns the rate limit of the core resource, which requests to
+// every endpoint that is used count towards, and whether it is known.
+func (a *API) RateLimit(ctx context.Context) (apis.RateLimit, bool) {
+	return a.Client.RateLimit(ctx, coreResource)
+}
+
+// get requests the given path under the rate limit of the core resource, as
+// by apis.API's Get.
+func (a *API) get(ctx context.Context, path string, body interface{}) (*http.Response, error) {
+	return a.Client.Get(ctx, coreResource, path, body)
+}
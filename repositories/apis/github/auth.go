@@ -0,0 +1,46 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// TokenAuth authenticates requests to GitHub with a personal access token,
+// which raises the rate limit of requests from 60 to 5,000 an hour. Personal
+// access tokens are created from the developer settings of an account, which
+// is also where they are renewed once they expire. Without a token, requests
+// are made anonymously.
+type TokenAuth struct {
+	Token string
+}
+
+// NewTokenAuth creates a TokenAuth with the given personal access token,
+// which may be empty.
+func NewTokenAuth(token string) *TokenAuth {
+	return &TokenAuth{
+		Token: token,
+	}
+}
+
+// IsAuthenticated returns true, since requests can be made with or without a
+// token.
+func (a *TokenAuth) IsAuthenticated() bool {
+	return true
+}
+
+// Authenticate does nothing, since the token can't be obtained.
+func (a *TokenAuth) Authenticate(ctx context.Context) error {
+	return nil
+}
+
+// Invalidate does nothing, since the token can't be obtained again.
+func (a *TokenAuth) Invalidate() {}
+
+// Attach sets the token as the bearer token of the request, if there is one.
+func (a *TokenAuth) Attach(req *retryablehttp.Request) {
+	if a.Token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.Token))
+	}
+}
@@ -0,0 +1,135 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/jake-hansen/followrs/apperrors"
+)
+
+// maxUsersResults is the largest page size GitHub allows when listing users,
+// such as the follows of a user or the stargazers of a repository.
+const maxUsersResults = 100
+
+// maxLoginLength is the length of the longest login GitHub allows.
+const maxLoginLength = 39
+
+// maxRepoNameLength is the length of the longest repository name GitHub
+// allows.
+const maxRepoNameLength = 100
+
+var (
+	// loginPattern matches the logins GitHub allows, which are made of
+	// alphanumeric characters separated by single hyphens.
+	loginPattern = regexp.MustCompile(`^[A-Za-z0-9]+(-[A-Za-z0-9]+)*$`)
+
+	// repoNamePattern matches the characters GitHub allows in repository
+	// names. GitHub replaces any others with hyphens when a repository is
+	// created.
+	repoNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+)
+
+// User represents a GitHub user or organization. Only Login, ID, Type,
+// AvatarURL and HTMLURL are present in lists of users.
+type User struct {
+	Login           string     `json:"login"`
+	ID              int64      `json:"id"`
+	Type            string     `json:"type"` // "User" or "Organization".
+	Name            string     `json:"name"`
+	Company         string     `json:"company"`
+	Blog            string     `json:"blog"`
+	Location        string     `json:"location"`
+	Bio             string     `json:"bio"`
+	TwitterUsername string     `json:"twitter_username"`
+	AvatarURL       string     `json:"avatar_url"`
+	HTMLURL         string     `json:"html_url"`
+	PublicRepos     int64      `json:"public_repos"`
+	PublicGists     int64      `json:"public_gists"`
+	Followers       int64      `json:"followers"`
+	Following       int64      `json:"following"`
+	CreatedAt       *time.Time `json:"created_at"`
+}
+
+// GetUser returns the user with the given login.
+func (a *API) GetUser(ctx context.Context, login string) (*User, error) {
+	if err := validateLogin(login); err != nil {
+		return nil, err
+	}
+
+	user := new(User)
+	if _, err := a.get(ctx, fmt.Sprintf("/users/%s", url.PathEscape(login)), user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetUserByID returns the user with the given ID, which unlike their login
+// doesn't change when they are renamed.
+func (a *API) GetUserByID(ctx context.Context, id int64) (*User, error) {
+	user := new(User)
+	if _, err := a.get(ctx, fmt.Sprintf("/user/%d", id), user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetFollowers returns every user that follows the user with the given login.
+func (a *API) GetFollowers(ctx context.Context, login string) ([]User, error) {
+	if err := validateLogin(login); err != nil {
+		return nil, err
+	}
+	return a.listUsers(ctx, fmt.Sprintf("/users/%s/followers", url.PathEscape(login)))
+}
+
+// GetFollowing returns every user that the user with the given login follows.
+func (a *API) GetFollowing(ctx context.Context, login string) ([]User, error) {
+	if err := validateLogin(login); err != nil {
+		return nil, err
+	}
+	return a.listUsers(ctx, fmt.Sprintf("/users/%s/following", url.PathEscape(login)))
+}
+
+// GetStargazers returns every user that has starred the repository with the
+// given name that belongs to the user or organization with the given login.
+func (a *API) GetStargazers(ctx context.Context, owner string, repo string) ([]User, error) {
+	if err := validateLogin(owner); err != nil {
+		return nil, err
+	}
+	if len(repo) > maxRepoNameLength || !repoNamePattern.MatchString(repo) || repo == "." || repo == ".." {
+		return nil, fmt.Errorf("repository %s/%s %w", owner, repo, apperrors.ErrNotFound)
+	}
+	return a.listUsers(ctx, fmt.Sprintf("/repos/%s/%s/stargazers", url.PathEscape(owner), url.PathEscape(repo)))
+}
+
+// listUsers requests every page of the list of users at the given path.
+// Pages are requested until GitHub stops linking to a next page.
+func (a *API) listUsers(ctx context.Context, path string) ([]User, error) {
+	var users []User
+	path = fmt.Sprintf("%s?per_page=%d", path, maxUsersResults)
+	for path != "" {
+		var page []User
+		response, err := a.get(ctx, path, &page)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, page...)
+
+		path, err = a.Client.NextPage(response.Header)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+// validateLogin fails with apperrors.ErrInvalidUsername if the given login
+// isn't one GitHub allows.
+func validateLogin(login string) error {
+	if len(login) > maxLoginLength || !loginPattern.MatchString(login) {
+		return fmt.Errorf("%w: %s", apperrors.ErrInvalidUsername, login)
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package github_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+	"github.com/jake-hansen/followrs/repositories/apis/github"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestAPI creates an API for a GitHub server that responds with the
+// handlers registered on the returned mux, authenticated with the given token.
+func newTestAPI(t *testing.T, token string) (*http.ServeMux, *httptest.Server, *github.API) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	api, err := github.NewGitHubAPI(server.URL, token)
+	assert.NoError(t, err)
+	api.Client.RateLimitPolicy = apis.FailFast
+	return mux, server, api
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	assert.NoError(t, json.NewEncoder(w).Encode(body))
+}
+
+func setRateLimit(w http.ResponseWriter, remaining int, reset time.Time) {
+	w.Header().Set("X-RateLimit-Limit", "5000")
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	w.Header().Set("X-RateLimit-Resource", "core")
+}
+
+// TestGetUser tests the GetUser function of API.
+func TestGetUser(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mux, _, api := newTestAPI(t, "token")
+		mux.HandleFunc("/users/octocat", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			assert.Equal(t, "application/vnd.github+json", r.Header.Get("Accept"))
+			assert.NotEmpty(t, r.Header.Get("User-Agent"))
+			assert.NotEmpty(t, r.Header.Get("X-GitHub-Api-Version"))
+			fmt.Fprint(w, `{"login":"octocat","id":583231,"type":"User","name":"The Octocat","followers":20,"following":9,"public_repos":8,"created_at":"2011-01-25T18:44:36Z"}`)
+		})
+
+		user, err := api.GetUser(context.Background(), "octocat")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(583231), user.ID)
+		assert.Equal(t, "The Octocat", user.Name)
+		assert.Equal(t, int64(20), user.Followers)
+		assert.Equal(t, int64(9), user.Following)
+		assert.True(t, time.Date(2011, time.January, 25, 18, 44, 36, 0, time.UTC).Equal(*user.CreatedAt))
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		mux, _, api := newTestAPI(t, "")
+		mux.HandleFunc("/users/octocat", func(w http.ResponseWriter, r *http.Request) {
+			assert.Empty(t, r.Header.Get("Authorization"))
+			writeJSON(t, w, github.User{Login: "octocat"})
+		})
+
+		_, err := api.GetUser(context.Background(), "octocat")
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid-login", func(t *testing.T) {
+		_, _, api := newTestAPI(t, "")
+
+		for _, login := range []string{"", "-octocat", "octocat-", "octo--cat", "octo/cat", strings.Repeat("a", 40)} {
+			user, err := api.GetUser(context.Background(), login)
+			assert.Nil(t, user, login)
+			assert.True(t, errors.Is(err, apperrors.ErrInvalidUsername), login)
+		}
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mux, _, api := newTestAPI(t, "")
+		mux.HandleFunc("/users/octocat", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found","documentation_url":"https://docs.github.com/rest"}`)
+		})
+
+		user, err := api.GetUser(context.Background(), "octocat")
+		assert.Nil(t, user)
+		assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+		var responseError *apis.ResponseError
+		assert.True(t, errors.As(err, &responseError))
+		assert.Equal(t, "Not Found", responseError.Message)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		mux, _, api := newTestAPI(t, "token")
+		mux.HandleFunc("/users/octocat", func(w http.ResponseWriter, r *http.Request) {
+			setRateLimit(w, 4999, time.Now().Add(time.Hour))
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"Resource not accessible by personal access token"}`)
+		})
+
+		_, err := api.GetUser(context.Background(), "octocat")
+		assert.True(t, errors.Is(err, apperrors.ErrUnauthorized))
+		assert.False(t, errors.Is(err, apperrors.ErrRateLimited))
+	})
+}
+
+// TestGetUserByID tests the GetUserByID function of API.
+func TestGetUserByID(t *testing.T) {
+	mux, _, api := newTestAPI(t, "token")
+	mux.HandleFunc("/user/583231", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"login":"renamed","id":583231,"type":"User","followers":20,"following":9}`)
+	})
+
+	user, err := api.GetUserByID(context.Background(), 583231)
+	assert.NoError(t, err)
+	assert.Equal(t, "renamed", user.Login)
+	assert.Equal(t, int64(20), user.Followers)
+}
+
+// TestRateLimit tests that the rate limit is tracked from the x-ratelimit
+// headers of GitHub's responses.
+func TestRateLimit(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	t.Run("tracks-core-resource", func(t *testing.T) {
+		mux, _, api := newTestAPI(t, "")
+		mux.HandleFunc("/users/octocat", func(w http.ResponseWriter, r *http.Request) {
+			setRateLimit(w, 0, reset)
+			writeJSON(t, w, github.User{Login: "octocat"})
+		})
+
+		_, err := api.GetUser(context.Background(), "octocat")
+		assert.NoError(t, err)
+
+		limit, ok := api.RateLimit(context.Background())
+		assert.True(t, ok)
+		assert.Equal(t, int64(0), limit.Remaining)
+		assert.True(t, reset.Equal(limit.Reset))
+
+		_, err = api.GetUser(context.Background(), "octocat")
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+	})
+
+	t.Run("primary-limit-exceeded", func(t *testing.T) {
+		mux, _, api := newTestAPI(t, "")
+		mux.HandleFunc("/users/octocat", func(w http.ResponseWriter, r *http.Request) {
+			setRateLimit(w, 0, reset)
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"API rate limit exceeded for 127.0.0.1."}`)
+		})
+
+		_, err := api.GetUser(context.Background(), "octocat")
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+		assert.False(t, errors.Is(err, apperrors.ErrUnauthorized))
+
+		limit, ok := api.RateLimit(context.Background())
+		assert.True(t, ok)
+		assert.Equal(t, int64(0), limit.Remaining)
+	})
+
+	t.Run("secondary-limit-exceeded", func(t *testing.T) {
+		mux, _, api := newTestAPI(t, "")
+		mux.HandleFunc("/users/octocat", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"You have exceeded a secondary rate limit."}`)
+		})
+
+		_, err := api.GetUser(context.Background(), "octocat")
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+	})
+}
+
+// TestGetFollows tests the GetFollowers and GetFollowing functions of API.
+func TestGetFollows(t *testing.T) {
+	t.Run("paginates", func(t *testing.T) {
+		mux, server, api := newTestAPI(t, "")
+		for _, list := range []string{"followers", "following"} {
+			list := list
+			mux.HandleFunc("/users/octocat/"+list, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "100", r.URL.Query().Get("per_page"))
+				switch r.URL.Query().Get("page") {
+				case "":
+					w.Header().Set("Link", fmt.Sprintf(`<%s/users/octocat/%s?per_page=100&page=2>; rel="next", <%s/users/octocat/%s?per_page=100&page=2>; rel="last"`, server.URL, list, server.URL, list))
+					writeJSON(t, w, []github.User{{Login: "one", ID: 1}, {Login: "two", ID: 2}})
+				case "2":
+					w.Header().Set("Link", fmt.Sprintf(`<%s/users/octocat/%s?per_page=100&page=1>; rel="prev", <%s/users/octocat/%s?per_page=100&page=1>; rel="first"`, server.URL, list, server.URL, list))
+					writeJSON(t, w, []github.User{{Login: "three", ID: 3}})
+				}
+			})
+		}
+
+		followers, err := api.GetFollowers(context.Background(), "octocat")
+		assert.NoError(t, err)
+		assert.Equal(t, []github.User{{Login: "one", ID: 1}, {Login: "two", ID: 2}, {Login: "three", ID: 3}}, followers)
+
+		following, err := api.GetFollowing(context.Background(), "octocat")
+		assert.NoError(t, err)
+		assert.Len(t, following, 3)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mux, _, api := newTestAPI(t, "")
+		mux.HandleFunc("/users/octocat/following", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+		})
+
+		following, err := api.GetFollowing(context.Background(), "octocat")
+		assert.Nil(t, following)
+		assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+	})
+}
+
+// TestGetStargazers tests the GetStargazers function of API.
+func TestGetStargazers(t *testing.T) {
+	t.Run("paginates", func(t *testing.T) {
+		mux, server, api := newTestAPI(t, "")
+		mux.HandleFunc("/repos/octocat/hello-world/stargazers", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "100", r.URL.Query().Get("per_page"))
+			switch r.URL.Query().Get("page") {
+			case "":
+				w.Header().Set("Link", fmt.Sprintf(`<%s/repos/octocat/hello-world/stargazers?per_page=100&page=2>; rel="next"`, server.URL))
+				writeJSON(t, w, []github.User{{Login: "one", ID: 1}})
+			case "2":
+				writeJSON(t, w, []github.User{{Login: "two", ID: 2}})
+			}
+		})
+
+		stargazers, err := api.GetStargazers(context.Background(), "octocat", "hello-world")
+		assert.NoError(t, err)
+		assert.Equal(t, []github.User{{Login: "one", ID: 1}, {Login: "two", ID: 2}}, stargazers)
+	})
+
+	t.Run("invalid-repository", func(t *testing.T) {
+		_, _, api := newTestAPI(t, "")
+
+		for _, repo := range []string{"", ".", "..", "hello/world", strings.Repeat("a", 101)} {
+			stargazers, err := api.GetStargazers(context.Background(), "octocat", repo)
+			assert.Nil(t, stargazers, repo)
+			assert.True(t, errors.Is(err, apperrors.ErrNotFound), repo)
+		}
+	})
+
+	t.Run("invalid-owner", func(t *testing.T) {
+		_, _, api := newTestAPI(t, "")
+
+		stargazers, err := api.GetStargazers(context.Background(), "-octocat", "hello-world")
+		assert.Nil(t, stargazers)
+		assert.True(t, errors.Is(err, apperrors.ErrInvalidUsername))
+	})
+}
@@ -0,0 +1,34 @@
+package apis
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// maxErrorBodySize is the largest response body that is kept in an HTTPError.
+const maxErrorBodySize = 1 << 20
+
+// HTTPError is returned by Do when the API responds with a status other than
+// 200 OK. It keeps the response's headers and raw body so that the caller can
+// decode the API's own error format.
+type HTTPError struct {
+	URL        string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Retries    int // Number of times the request was retried before failing.
+}
+
+// Error describes the request that failed and the status it returned.
+func (e *HTTPError) Error() string {
+	if kind := statusError(e.StatusCode); kind != nil {
+		return fmt.Sprintf("request to %s returned code %d: %s", e.URL, e.StatusCode, kind.Error())
+	}
+	return fmt.Sprintf("request to %s returned code %d", e.URL, e.StatusCode)
+}
+
+// Unwrap returns the error from apperrors that describes the response's
+// status, or nil if there is none.
+func (e *HTTPError) Unwrap() error {
+	return statusError(e.StatusCode)
+}
@@ -0,0 +1,60 @@
+package apis
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ParseLinks parses the value of a Link header, as defined by RFC 8288, into
+// the target URL of each link by its relation type, such as "next".
+func ParseLinks(header string) map[string]string {
+	links := make(map[string]string)
+	for _, link := range strings.Split(header, ",") {
+		parts := strings.Split(link, ";")
+		target := strings.TrimSpace(parts[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+
+		for _, param := range parts[1:] {
+			name, value := splitParam(param)
+			if name != "rel" {
+				continue
+			}
+			for _, rel := range strings.Fields(value) {
+				links[strings.ToLower(rel)] = target
+			}
+		}
+	}
+	return links
+}
+
+// splitParam splits a link parameter into its lowercase name and unquoted value.
+func splitParam(param string) (string, string) {
+	parts := strings.SplitN(param, "=", 2)
+	name := strings.ToLower(strings.TrimSpace(parts[0]))
+	if len(parts) == 1 {
+		return name, ""
+	}
+	return name, strings.Trim(strings.TrimSpace(parts[1]), `"`)
+}
+
+// NextPage returns the URL of the next page of a paginated response, as given
+// by the "next" link of its Link header, relative to the API's BaseURL so that
+// it can be requested with Do. It returns an empty string if there is no next
+// page, and fails if the next page is not part of the API.
+func (api *API) NextPage(header http.Header) (string, error) {
+	next, ok := ParseLinks(header.Get("Link"))["next"]
+	if !ok {
+		return "", nil
+	}
+
+	base := strings.TrimSuffix(api.BaseURL.String(), "/")
+	relative := strings.TrimPrefix(next, base)
+	if relative == next || !(strings.HasPrefix(relative, "/") || strings.HasPrefix(relative, "?")) {
+		return "", fmt.Errorf("next page %s is not part of the API at %s", next, base)
+	}
+	return relative, nil
+}
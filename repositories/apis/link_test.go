@@ -0,0 +1,67 @@
+package apis_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jake-hansen/followrs/repositories/apis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLinks(t *testing.T) {
+	t.Run("several-links", func(t *testing.T) {
+		links := apis.ParseLinks(`<https://example.com/followers?max_id=2>; rel="next", <https://example.com/followers?min_id=9>; rel="prev"`)
+
+		assert.Equal(t, map[string]string{
+			"next": "https://example.com/followers?max_id=2",
+			"prev": "https://example.com/followers?min_id=9",
+		}, links)
+	})
+
+	t.Run("several-relation-types", func(t *testing.T) {
+		links := apis.ParseLinks(`<https://example.com/users?page=5>; title="end"; rel="last Next"`)
+
+		assert.Equal(t, map[string]string{
+			"last": "https://example.com/users?page=5",
+			"next": "https://example.com/users?page=5",
+		}, links)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		assert.Empty(t, apis.ParseLinks(`https://example.com; rel="next"`))
+		assert.Empty(t, apis.ParseLinks(""))
+	})
+}
+
+func TestNextPage(t *testing.T) {
+	api, _ := apis.NewAPI("https://example.com/api", nil, nil, nil)
+
+	t.Run("next-page", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("Link", `<https://example.com/api/followers?page=2>; rel="next"`)
+
+		next, err := api.NextPage(header)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "/followers?page=2", next)
+	})
+
+	t.Run("last-page", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("Link", `<https://example.com/api/followers?page=1>; rel="prev"`)
+
+		next, err := api.NextPage(header)
+
+		assert.NoError(t, err)
+		assert.Empty(t, next)
+	})
+
+	t.Run("next-page-elsewhere", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("Link", `<https://attacker.example/followers?page=2>; rel="next"`)
+
+		_, err := api.NextPage(header)
+
+		assert.Error(t, err)
+	})
+}
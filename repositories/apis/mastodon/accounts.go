@@ -0,0 +1,210 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jake-hansen/followrs/apperrors"
+)
+
+// maxFollowsResults is the largest page size Mastodon allows when listing the
+// follows of an account.
+const maxFollowsResults = 80
+
+var (
+	// usernamePattern matches the usernames that Mastodon allows.
+	usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+([A-Za-z0-9_.-]*[A-Za-z0-9_])?$`)
+
+	// domainPattern matches the domains of instances, optionally with a port.
+	domainPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9.-]*[A-Za-z0-9])?(:[0-9]+)?$`)
+)
+
+// Account represents a Mastodon account.
+type Account struct {
+	ID             string    `json:"id"`
+	Username       string    `json:"username"`
+	Acct           string    `json:"acct"` // Username, followed by @ and the domain of accounts of other instances.
+	DisplayName    string    `json:"display_name"`
+	Note           string    `json:"note"` // Biography of the account, in HTML.
+	URL            string    `json:"url"`
+	Avatar         string    `json:"avatar"`
+	Locked         bool      `json:"locked"`
+	Bot            bool      `json:"bot"`
+	Suspended      bool      `json:"suspended"`
+	CreatedAt      time.Time `json:"created_at"`
+	FollowersCount int64     `json:"followers_count"`
+	FollowingCount int64     `json:"following_count"`
+	StatusesCount  int64     `json:"statuses_count"`
+
+	// Instance is the domain of the instance the account was retrieved from.
+	Instance string `json:"-"`
+}
+
+// Handle returns the username of the account followed by @ and the domain of
+// its instance, such as "user@mastodon.social".
+func (a *Account) Handle() string {
+	if strings.Contains(a.Acct, "@") {
+		return a.Acct
+	}
+	return fmt.Sprintf("%s@%s", a.Acct, a.Instance)
+}
+
+// webFingerResponse is a JSON Resource Descriptor returned by WebFinger.
+type webFingerResponse struct {
+	Subject string `json:"subject"`
+	Links   []struct {
+		Rel  string `json:"rel"`
+		Type string `json:"type"`
+		Href string `json:"href"`
+	} `json:"links"`
+}
+
+// LookupAccount returns the account with the given address, such as
+// "@user@mastodon.social". The instance of the account is found with
+// WebFinger, and the account is looked up on that instance, so that its counts
+// are current. Addresses without a domain belong to the DefaultInstance.
+func (a *API) LookupAccount(ctx context.Context, acct string) (*Account, error) {
+	username, domain, err := a.parseAcct(acct)
+	if err != nil {
+		return nil, err
+	}
+
+	username, instanceDomain, err := a.webFinger(ctx, username, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := a.Instance(instanceDomain)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("acct", username)
+	account := new(Account)
+	_, err = instance.Get(ctx, rateLimitEndpoint, fmt.Sprintf("/api/v1/accounts/lookup?%s", query.Encode()), account)
+	if err != nil {
+		return nil, err
+	}
+	if account.Suspended {
+		return nil, fmt.Errorf("account %w", apperrors.ErrSuspended)
+	}
+
+	account.Instance = instanceDomain
+	return account, nil
+}
+
+// Followers returns every account that follows the given account, as seen by
+// its instance. Instances may hide the followers of accounts that choose to.
+func (a *API) Followers(ctx context.Context, account *Account) ([]Account, error) {
+	return a.listAccounts(ctx, account, "followers")
+}
+
+// Following returns every account that the given account follows, as seen by
+// its instance.
+func (a *API) Following(ctx context.Context, account *Account) ([]Account, error) {
+	return a.listAccounts(ctx, account, "following")
+}
+
+// listAccounts requests every page of the given list of accounts related to
+// the given account from its instance. Pages are requested until the instance
+// stops linking to a next page.
+func (a *API) listAccounts(ctx context.Context, account *Account, list string) ([]Account, error) {
+	instance, err := a.Instance(account.Instance)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []Account
+	path := fmt.Sprintf("/api/v1/accounts/%s/%s?limit=%d", url.PathEscape(account.ID), list, maxFollowsResults)
+	for path != "" {
+		var page []Account
+		response, err := instance.Get(ctx, rateLimitEndpoint, path, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range page {
+			page[i].Instance = account.Instance
+		}
+		accounts = append(accounts, page...)
+
+		path, err = instance.NextPage(response.Header)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return accounts, nil
+}
+
+// parseAcct splits an address such as "@user@mastodon.social" into its
+// username and domain. The domain is the DefaultInstance if the address
+// doesn't have one.
+func (a *API) parseAcct(acct string) (string, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(acct, "@"), "@", 2)
+	username, domain := parts[0], a.DefaultInstance
+	if len(parts) == 2 {
+		domain = strings.ToLower(parts[1])
+	}
+
+	if !usernamePattern.MatchString(username) || !domainPattern.MatchString(domain) {
+		return "", "", fmt.Errorf("%w: %s", apperrors.ErrInvalidUsername, acct)
+	}
+	return username, domain, nil
+}
+
+// webFinger asks the given domain who the account with the given username is,
+// and returns the username of the account and the domain of the instance that
+// hosts it. They differ from those asked about when a domain delegates its
+// accounts to an instance elsewhere. The instance is only trusted if its
+// domain is the one asked or the domain of the account WebFinger returns, so
+// that a domain can't direct requests to arbitrary hosts.
+func (a *API) webFinger(ctx context.Context, username string, domain string) (string, string, error) {
+	if err := a.checkDomain(ctx, domain); err != nil {
+		return "", "", err
+	}
+	instance, err := a.Instance(domain)
+	if err != nil {
+		return "", "", err
+	}
+
+	query := url.Values{}
+	query.Set("resource", fmt.Sprintf("acct:%s@%s", username, domain))
+	response := new(webFingerResponse)
+	if _, err := instance.Get(ctx, rateLimitEndpoint, fmt.Sprintf("/.well-known/webfinger?%s", query.Encode()), response); err != nil {
+		return "", "", fmt.Errorf("could not find account %s@%s: %w", username, domain, err)
+	}
+
+	subjectDomain := domain
+	if subject := strings.TrimPrefix(response.Subject, "acct:"); subject != response.Subject {
+		parts := strings.SplitN(subject, "@", 2)
+		if len(parts) != 2 || !usernamePattern.MatchString(parts[0]) || !domainPattern.MatchString(parts[1]) {
+			return "", "", fmt.Errorf("%s returned invalid subject %q for account %s: %w", domain, response.Subject, username, apperrors.ErrNotFound)
+		}
+		username, subjectDomain = parts[0], strings.ToLower(parts[1])
+	}
+
+	instanceDomain := domain
+	for _, link := range response.Links {
+		if link.Rel != "self" {
+			continue
+		}
+		if self, err := url.Parse(link.Href); err == nil && self.Host != "" {
+			instanceDomain = strings.ToLower(self.Host)
+		}
+		break
+	}
+	if instanceDomain != domain && instanceDomain != subjectDomain {
+		return "", "", fmt.Errorf("%s delegated account %s to unrelated instance %s: %w", domain, username, instanceDomain, apperrors.ErrNotFound)
+	}
+	if instanceDomain != domain {
+		if err := a.checkDomain(ctx, instanceDomain); err != nil {
+			return "", "", err
+		}
+	}
+	return username, instanceDomain, nil
+}
@@ -0,0 +1,300 @@
+package mastodon_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+	"github.com/jake-hansen/followrs/repositories/apis/mastodon"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestInstance starts a Mastodon instance that responds with the handlers
+// registered on the returned mux, and returns its domain.
+func newTestInstance(t *testing.T) (*http.ServeMux, string) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return mux, strings.TrimPrefix(server.URL, "http://")
+}
+
+// newTestAPI creates an API that requests instances over plain HTTP, which
+// are allowed to be on the loopback address.
+func newTestAPI(defaultInstance string, accessTokens map[string]string) *mastodon.API {
+	api := mastodon.NewMastodonAPI(defaultInstance, accessTokens)
+	api.Scheme = "http"
+	api.AllowPrivateAddresses = true
+	return api
+}
+
+// handleWebFinger responds to WebFinger requests for the given username on the
+// given domain with an account hosted on the given instance.
+func handleWebFinger(t *testing.T, mux *http.ServeMux, username string, domain string, instance string) {
+	handleDelegatedWebFinger(t, mux, username, domain, domain, instance)
+}
+
+// handleDelegatedWebFinger responds to WebFinger requests for the given
+// username on the given domain with an account of the subject domain, hosted
+// on the given instance.
+func handleDelegatedWebFinger(t *testing.T, mux *http.ServeMux, username string, domain string, subjectDomain string, instance string) {
+	mux.HandleFunc("/.well-known/webfinger", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("resource") != fmt.Sprintf("acct:%s@%s", username, domain) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeJSON(t, w, map[string]interface{}{
+			"subject": fmt.Sprintf("acct:%s@%s", username, subjectDomain),
+			"links": []map[string]string{
+				{"rel": "http://webfinger.net/rel/profile-page", "href": fmt.Sprintf("http://%s/@%s", instance, username)},
+				{"rel": "self", "type": "application/activity+json", "href": fmt.Sprintf("http://%s/users/%s", instance, username)},
+			},
+		})
+	})
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	assert.NoError(t, json.NewEncoder(w).Encode(body))
+}
+
+// TestLookupAccount tests the LookupAccount function of API.
+func TestLookupAccount(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mux, domain := newTestInstance(t)
+		handleWebFinger(t, mux, "alice", domain, domain)
+		mux.HandleFunc("/api/v1/accounts/lookup", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "alice", r.URL.Query().Get("acct"))
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			writeJSON(t, w, mastodon.Account{ID: "1", Username: "alice", Acct: "alice", FollowersCount: 10})
+		})
+
+		api := newTestAPI("", map[string]string{domain: "token"})
+
+		account, err := api.LookupAccount(context.Background(), fmt.Sprintf("@alice@%s", domain))
+		assert.NoError(t, err)
+		assert.Equal(t, "1", account.ID)
+		assert.Equal(t, int64(10), account.FollowersCount)
+		assert.Equal(t, domain, account.Instance)
+		assert.Equal(t, fmt.Sprintf("alice@%s", domain), account.Handle())
+	})
+
+	t.Run("default-instance", func(t *testing.T) {
+		mux, domain := newTestInstance(t)
+		handleWebFinger(t, mux, "alice", domain, domain)
+		mux.HandleFunc("/api/v1/accounts/lookup", func(w http.ResponseWriter, r *http.Request) {
+			assert.Empty(t, r.Header.Get("Authorization"))
+			writeJSON(t, w, mastodon.Account{ID: "1", Username: "alice", Acct: "alice"})
+		})
+
+		api := newTestAPI(domain, nil)
+
+		account, err := api.LookupAccount(context.Background(), "alice")
+		assert.NoError(t, err)
+		assert.Equal(t, "1", account.ID)
+	})
+
+	t.Run("delegated-domain", func(t *testing.T) {
+		hostMux, host := newTestInstance(t)
+		hostMux.HandleFunc("/api/v1/accounts/lookup", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "alice", r.URL.Query().Get("acct"))
+			writeJSON(t, w, mastodon.Account{ID: "1", Username: "alice", Acct: "alice"})
+		})
+		mux, domain := newTestInstance(t)
+		handleDelegatedWebFinger(t, mux, "alice", domain, host, host)
+
+		api := newTestAPI("", nil)
+
+		account, err := api.LookupAccount(context.Background(), fmt.Sprintf("alice@%s", domain))
+		assert.NoError(t, err)
+		assert.Equal(t, host, account.Instance)
+	})
+
+	t.Run("unrelated-instance", func(t *testing.T) {
+		hostMux, host := newTestInstance(t)
+		hostMux.HandleFunc("/api/v1/accounts/lookup", func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unrelated instance should not have been requested")
+		})
+		mux, domain := newTestInstance(t)
+		handleWebFinger(t, mux, "alice", domain, host)
+
+		api := newTestAPI("", nil)
+
+		account, err := api.LookupAccount(context.Background(), fmt.Sprintf("alice@%s", domain))
+		assert.Nil(t, account)
+		assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+	})
+
+	t.Run("private-address", func(t *testing.T) {
+		mux, domain := newTestInstance(t)
+		mux.HandleFunc("/.well-known/webfinger", func(w http.ResponseWriter, r *http.Request) {
+			t.Error("private address should not have been requested")
+		})
+
+		api := mastodon.NewMastodonAPI("", nil)
+		api.Scheme = "http"
+
+		for _, acct := range []string{fmt.Sprintf("alice@%s", domain), "alice@localhost"} {
+			account, err := api.LookupAccount(context.Background(), acct)
+			assert.Nil(t, account, acct)
+			assert.True(t, errors.Is(err, apperrors.ErrInvalidUsername), acct)
+		}
+	})
+
+	t.Run("invalid-acct", func(t *testing.T) {
+		api := newTestAPI("mastodon.example", nil)
+
+		for _, acct := range []string{"", "@", "al ice", "alice@", "alice@exa mple", "alice@-example"} {
+			account, err := api.LookupAccount(context.Background(), acct)
+			assert.Nil(t, account, acct)
+			assert.True(t, errors.Is(err, apperrors.ErrInvalidUsername), acct)
+		}
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mux, domain := newTestInstance(t)
+		handleWebFinger(t, mux, "alice", domain, domain)
+
+		api := newTestAPI(domain, nil)
+
+		account, err := api.LookupAccount(context.Background(), "bob")
+		assert.Nil(t, account)
+		assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+	})
+
+	t.Run("suspended", func(t *testing.T) {
+		mux, domain := newTestInstance(t)
+		handleWebFinger(t, mux, "alice", domain, domain)
+		mux.HandleFunc("/api/v1/accounts/lookup", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, mastodon.Account{ID: "1", Username: "alice", Acct: "alice", Suspended: true})
+		})
+
+		api := newTestAPI(domain, nil)
+
+		account, err := api.LookupAccount(context.Background(), "alice")
+		assert.Nil(t, account)
+		assert.True(t, errors.Is(err, apperrors.ErrSuspended))
+	})
+
+	t.Run("response-error", func(t *testing.T) {
+		mux, domain := newTestInstance(t)
+		handleWebFinger(t, mux, "alice", domain, domain)
+		mux.HandleFunc("/api/v1/accounts/lookup", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"The access token is invalid"}`)
+		})
+
+		api := newTestAPI(domain, nil)
+
+		account, err := api.LookupAccount(context.Background(), "alice")
+		assert.Nil(t, account)
+		var responseError *apis.ResponseError
+		assert.True(t, errors.As(err, &responseError))
+		assert.Equal(t, "The access token is invalid", responseError.Message)
+		assert.True(t, errors.Is(err, apperrors.ErrUnauthorized))
+	})
+}
+
+// TestFollows tests the Followers and Following functions of API.
+func TestFollows(t *testing.T) {
+	t.Run("paginates", func(t *testing.T) {
+		mux, domain := newTestInstance(t)
+		for _, list := range []string{"followers", "following"} {
+			list := list
+			mux.HandleFunc(fmt.Sprintf("/api/v1/accounts/1/%s", list), func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "80", r.URL.Query().Get("limit"))
+				if r.URL.Query().Get("max_id") == "" {
+					w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v1/accounts/1/%s?limit=80&max_id=2>; rel="next", <http://%s/api/v1/accounts/1/%s?limit=80&min_id=3>; rel="prev"`, domain, list, domain, list))
+					writeJSON(t, w, []mastodon.Account{{ID: "3", Acct: list + "3"}})
+					return
+				}
+				writeJSON(t, w, []mastodon.Account{{ID: "2", Acct: list + "2@elsewhere.example"}})
+			})
+		}
+
+		api := newTestAPI(domain, nil)
+		account := &mastodon.Account{ID: "1", Instance: domain}
+
+		followers, err := api.Followers(context.Background(), account)
+		assert.NoError(t, err)
+		assert.Len(t, followers, 2)
+		assert.Equal(t, fmt.Sprintf("followers3@%s", domain), followers[0].Handle())
+		assert.Equal(t, "followers2@elsewhere.example", followers[1].Handle())
+
+		following, err := api.Following(context.Background(), account)
+		assert.NoError(t, err)
+		assert.Len(t, following, 2)
+		assert.Equal(t, "3", following[0].ID)
+		assert.Equal(t, "2", following[1].ID)
+	})
+
+	t.Run("foreign-next-link", func(t *testing.T) {
+		mux, domain := newTestInstance(t)
+		mux.HandleFunc("/api/v1/accounts/1/followers", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Link", `<http://elsewhere.example/api/v1/accounts/1/followers?max_id=2>; rel="next"`)
+			writeJSON(t, w, []mastodon.Account{{ID: "3"}})
+		})
+
+		api := newTestAPI(domain, nil)
+
+		followers, err := api.Followers(context.Background(), &mastodon.Account{ID: "1", Instance: domain})
+		assert.Nil(t, followers)
+		assert.Error(t, err)
+	})
+}
+
+// TestRateLimit tests that the rate limit of each instance is tracked from the
+// headers of its responses.
+func TestRateLimit(t *testing.T) {
+	reset := time.Now().Add(5 * time.Minute).UTC().Truncate(time.Second)
+
+	limitedMux, limited := newTestInstance(t)
+	handleWebFinger(t, limitedMux, "alice", limited, limited)
+	limitedMux.HandleFunc("/api/v1/accounts/lookup", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "300")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", reset.Format(time.RFC3339Nano))
+		writeJSON(t, w, mastodon.Account{ID: "1", Acct: "alice"})
+	})
+
+	otherMux, other := newTestInstance(t)
+	handleWebFinger(t, otherMux, "bob", other, other)
+	otherMux.HandleFunc("/api/v1/accounts/lookup", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, mastodon.Account{ID: "2", Acct: "bob"})
+	})
+
+	api := newTestAPI("", nil)
+	api.Configure = func(instance *apis.API) {
+		instance.RateLimitPolicy = apis.FailFast
+	}
+
+	_, err := api.LookupAccount(context.Background(), fmt.Sprintf("alice@%s", limited))
+	assert.NoError(t, err)
+
+	limit, ok := api.RateLimit(context.Background(), limited)
+	assert.True(t, ok)
+	assert.Equal(t, int64(0), limit.Remaining)
+	assert.True(t, reset.Equal(limit.Reset))
+
+	t.Run("exhausted-instance", func(t *testing.T) {
+		_, err := api.LookupAccount(context.Background(), fmt.Sprintf("alice@%s", limited))
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+	})
+
+	t.Run("other-instance", func(t *testing.T) {
+		account, err := api.LookupAccount(context.Background(), fmt.Sprintf("bob@%s", other))
+		assert.NoError(t, err)
+		assert.Equal(t, "2", account.ID)
+
+		_, ok := api.RateLimit(context.Background(), other)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,160 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+)
+
+// rateLimitEndpoint is the name of the rate limit of every request to the
+// Mastodon API of an instance. Instances limit the requests of each account,
+// or of each IP address for anonymous requests, regardless of the endpoint.
+const rateLimitEndpoint = "api"
+
+// rateLimitHeaders are the headers in which instances report their rate
+// limit. Not every instance reports it.
+var rateLimitHeaders = apis.RateLimitHeaders{Remaining: "x-ratelimit-remaining", Reset: "x-ratelimit-reset"}
+
+// maxInstances is the most instances whose APIs are kept at once. Accounts can
+// be looked up on any domain, so the API of the instance that was used least
+// recently is discarded to make room for another.
+const maxInstances = 256
+
+// API provides the services needed to interact with Mastodon. Every account
+// belongs to the instance it was created on, so requests are made to the API
+// of that instance, which has rate limits and access tokens of its own.
+type API struct {
+	// DefaultInstance is the domain of the instance of accounts that are
+	// looked up without one, such as "mastodon.social".
+	DefaultInstance string
+
+	// Scheme is the scheme of the URLs of instances. It is "https" unless
+	// testing against an instance without TLS.
+	Scheme string
+
+	// AccessTokens are the access tokens that requests are authenticated with
+	// by the domain of the instance that issued them. Requests to instances
+	// without an access token are made anonymously.
+	AccessTokens map[string]string
+
+	// Configure, if not nil, is called with the API of each instance when it
+	// is first used, such as to set its Timeout and RateLimitPolicy.
+	Configure func(instance *apis.API)
+
+	// AllowPrivateAddresses allows requests to instances whose addresses
+	// aren't public, such as loopback and private addresses. Since anyone can
+	// choose the domains that are requested, it is false unless testing
+	// against a local instance.
+	AllowPrivateAddresses bool
+
+	mu        sync.Mutex
+	instances map[string]*instance
+	uses      uint64
+}
+
+// instance is the API of an instance, along with when it was last used.
+type instance struct {
+	api     *apis.API
+	lastUse uint64
+}
+
+// decodeError decodes the error a Mastodon instance describes in the body of
+// a failed response, which is only a message.
+func decodeError(httpError *apis.HTTPError) *apis.ResponseError {
+	responseError := &apis.ResponseError{HTTPError: httpError}
+
+	var body struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(httpError.Body, &body); err == nil {
+		responseError.Message = body.Error
+	}
+	return responseError
+}
+
+// NewMastodonAPI creates an API that looks up accounts without a domain on the
+// given default instance, and authenticates requests to instances with the
+// given access tokens by domain.
+func NewMastodonAPI(defaultInstance string, accessTokens map[string]string) *API {
+	return &API{
+		DefaultInstance: defaultInstance,
+		Scheme:          "https",
+		AccessTokens:    accessTokens,
+		instances:       make(map[string]*instance),
+	}
+}
+
+// Instance returns the API of the instance with the given domain, creating it
+// the first time the instance is used. Unless AllowPrivateAddresses is set,
+// the API refuses to connect to addresses that aren't public.
+func (a *API) Instance(domain string) (*apis.API, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.uses++
+	if cached, ok := a.instances[domain]; ok {
+		cached.lastUse = a.uses
+		return cached.api, nil
+	}
+
+	auth := NewAccessTokenAuth(a.AccessTokens[domain])
+	api, err := apis.NewAPI(fmt.Sprintf("%s://%s", a.Scheme, domain), auth, auth.Attach, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not create Mastodon API of %s: %w", domain, err)
+	}
+	if !a.AllowPrivateAddresses {
+		api.SetDialer(apis.PublicDialer())
+	}
+	// Mastodon reports when the rate limit of an instance resets in
+	// x-ratelimit-reset, as an ISO 8601 timestamp, rather than in Retry-After.
+	api.RetryPolicy.ResetHeader = "x-ratelimit-reset"
+	api.RateLimitHeaders = rateLimitHeaders
+	api.DecodeError = decodeError
+	if a.Configure != nil {
+		a.Configure(api)
+	}
+
+	if len(a.instances) >= maxInstances {
+		a.evictLeastRecentlyUsed()
+	}
+	a.instances[domain] = &instance{api: api, lastUse: a.uses}
+	return api, nil
+}
+
+// evictLeastRecentlyUsed discards the API of the instance that was used least
+// recently. The caller must hold mu.
+func (a *API) evictLeastRecentlyUsed() {
+	var oldest string
+	for domain, cached := range a.instances {
+		if oldest == "" || cached.lastUse < a.instances[oldest].lastUse {
+			oldest = domain
+		}
+	}
+	delete(a.instances, oldest)
+}
+
+// checkDomain fails if the domain of an instance resolves to an address that
+// isn't public, unless AllowPrivateAddresses is set.
+func (a *API) checkDomain(ctx context.Context, domain string) error {
+	if a.AllowPrivateAddresses {
+		return nil
+	}
+	if err := apis.ResolvePublic(ctx, domain); err != nil {
+		return fmt.Errorf("%w: %s", apperrors.ErrInvalidUsername, err.Error())
+	}
+	return nil
+}
+
+// RateLimit returns the rate limit of the instance with the given domain, and
+// whether it is known.
+func (a *API) RateLimit(ctx context.Context, domain string) (apis.RateLimit, bool) {
+	instance, err := a.Instance(domain)
+	if err != nil {
+		return apis.RateLimit{}, false
+	}
+	return instance.RateLimit(ctx, rateLimitEndpoint)
+}
@@ -0,0 +1,38 @@
+package mastodon
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInstance_Eviction tests that the API of the instance used least recently
+// is discarded once maxInstances instances have been used.
+func TestInstance_Eviction(t *testing.T) {
+	api := NewMastodonAPI("", nil)
+
+	first, err := api.Instance("first.example")
+	assert.NoError(t, err)
+	second, err := api.Instance("second.example")
+	assert.NoError(t, err)
+
+	for i := 0; i < maxInstances-2; i++ {
+		_, err := api.Instance(fmt.Sprintf("instance%d.example", i))
+		assert.NoError(t, err)
+	}
+	reused, err := api.Instance("first.example")
+	assert.NoError(t, err)
+	assert.Same(t, first, reused)
+
+	_, err = api.Instance("another.example")
+	assert.NoError(t, err)
+	assert.Len(t, api.instances, maxInstances)
+
+	reused, err = api.Instance("first.example")
+	assert.NoError(t, err)
+	assert.Same(t, first, reused)
+	recreated, err := api.Instance("second.example")
+	assert.NoError(t, err)
+	assert.NotSame(t, second, recreated)
+}
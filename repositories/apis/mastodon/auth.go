@@ -0,0 +1,47 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// AccessTokenAuth authenticates requests to a Mastodon instance with an access
+// token issued by that instance, such as one generated from the development
+// settings of an account. Such tokens don't expire unless they are revoked
+// from those settings. Without a token, requests are made anonymously, which
+// most instances allow for public accounts.
+type AccessTokenAuth struct {
+	AccessToken string
+}
+
+// NewAccessTokenAuth creates an AccessTokenAuth with the given access token,
+// which may be empty.
+func NewAccessTokenAuth(accessToken string) *AccessTokenAuth {
+	return &AccessTokenAuth{
+		AccessToken: accessToken,
+	}
+}
+
+// IsAuthenticated returns true, since requests can be made with or without an
+// access token.
+func (a *AccessTokenAuth) IsAuthenticated() bool {
+	return true
+}
+
+// Authenticate does nothing, since the access token can't be obtained.
+func (a *AccessTokenAuth) Authenticate(ctx context.Context) error {
+	return nil
+}
+
+// Invalidate does nothing, since the access token can't be obtained again.
+func (a *AccessTokenAuth) Invalidate() {}
+
+// Attach sets the access token as the bearer token of the request, if there
+// is one.
+func (a *AccessTokenAuth) Attach(req *retryablehttp.Request) {
+	if a.AccessToken != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.AccessToken))
+	}
+}
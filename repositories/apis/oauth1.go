@@ -0,0 +1,202 @@
+package apis
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jake-hansen/followrs/apperrors"
+)
+
+// formContentType is the media type of a request body whose parameters are
+// included in an OAuth 1.0a signature.
+const formContentType = "application/x-www-form-urlencoded"
+
+// OAuth1Auth authenticates requests on behalf of a user by signing them with
+// OAuth 1.0a, using the HMAC-SHA1 signature method. The access token and
+// secret are pre-generated, so they are never obtained or refreshed.
+type OAuth1Auth struct {
+	ConsumerKey       string
+	ConsumerSecret    string
+	AccessToken       string
+	AccessTokenSecret string
+}
+
+// NewOAuth1Auth creates an OAuth1Auth with the given credentials.
+func NewOAuth1Auth(consumerKey string, consumerSecret string, accessToken string, accessTokenSecret string) *OAuth1Auth {
+	return &OAuth1Auth{
+		ConsumerKey:       consumerKey,
+		ConsumerSecret:    consumerSecret,
+		AccessToken:       accessToken,
+		AccessTokenSecret: accessTokenSecret,
+	}
+}
+
+// IsAuthenticated determines if every credential is configured.
+func (a *OAuth1Auth) IsAuthenticated() bool {
+	return a.ConsumerKey != "" && a.ConsumerSecret != "" && a.AccessToken != "" && a.AccessTokenSecret != ""
+}
+
+// Authenticate fails if any credential is missing, since none of them can be
+// obtained.
+func (a *OAuth1Auth) Authenticate(ctx context.Context) error {
+	if !a.IsAuthenticated() {
+		return fmt.Errorf("OAuth 1.0a consumer key and secret and access token and secret are required: %w", apperrors.ErrUnauthorized)
+	}
+	return nil
+}
+
+// Invalidate does nothing, since the credentials can't be obtained again.
+func (a *OAuth1Auth) Invalidate() {}
+
+// Identity identifies the access token without revealing it.
+func (a *OAuth1Auth) Identity() string {
+	sum := sha256.Sum256([]byte(a.AccessToken))
+	return "oauth1:" + hex.EncodeToString(sum[:8])
+}
+
+// Attach does nothing, since requests are signed by AttachAttempt instead, so
+// that retries are not sent with a nonce that has already been used.
+func (a *OAuth1Auth) Attach(req *retryablehttp.Request) {}
+
+// AttachAttempt signs an attempt of a request with a new nonce and timestamp
+// and attaches the signature in its Authorization header. The request's URL
+// must be absolute.
+func (a *OAuth1Auth) AttachAttempt(req *http.Request) error {
+	nonce, err := newNonce()
+	if err != nil {
+		return fmt.Errorf("could not generate OAuth 1.0a nonce: %w", err)
+	}
+
+	var body []byte
+	if req.Body != nil && strings.HasPrefix(req.Header.Get("Content-Type"), formContentType) {
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return fmt.Errorf("could not read body to sign: %w", err)
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	a.sign(req, body, nonce, time.Now())
+	return nil
+}
+
+// sign attaches an Authorization header to the request containing its
+// signature and the given nonce and timestamp. The parameters of the given
+// form body, if any, are included in the signature.
+func (a *OAuth1Auth) sign(req *http.Request, body []byte, nonce string, timestamp time.Time) {
+	oauthParams := map[string]string{
+		"oauth_consumer_key":     a.ConsumerKey,
+		"oauth_nonce":            nonce,
+		"oauth_signature_method": "HMAC-SHA1",
+		"oauth_timestamp":        strconv.FormatInt(timestamp.Unix(), 10),
+		"oauth_token":            a.AccessToken,
+		"oauth_version":          "1.0",
+	}
+
+	params := url.Values{}
+	for key, values := range req.URL.Query() {
+		params[key] = append(params[key], values...)
+	}
+	if form, err := url.ParseQuery(string(body)); err == nil {
+		for key, values := range form {
+			params[key] = append(params[key], values...)
+		}
+	}
+	for key, value := range oauthParams {
+		params.Set(key, value)
+	}
+
+	baseURL := *req.URL
+	baseURL.RawQuery = ""
+	baseURL.Fragment = ""
+	baseURL.Scheme = strings.ToLower(baseURL.Scheme)
+	baseURL.Host = strings.ToLower(baseURL.Host)
+
+	signatureBase := strings.Join([]string{
+		strings.ToUpper(req.Method),
+		percentEncode(baseURL.String()),
+		percentEncode(normalizeParams(params)),
+	}, "&")
+	signingKey := percentEncode(a.ConsumerSecret) + "&" + percentEncode(a.AccessTokenSecret)
+
+	mac := hmac.New(sha1.New, []byte(signingKey))
+	mac.Write([]byte(signatureBase))
+	oauthParams["oauth_signature"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	keys := make([]string, 0, len(oauthParams))
+	for key := range oauthParams {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	header := make([]string, len(keys))
+	for i, key := range keys {
+		header[i] = fmt.Sprintf(`%s="%s"`, percentEncode(key), percentEncode(oauthParams[key]))
+	}
+	req.Header.Set("Authorization", "OAuth "+strings.Join(header, ", "))
+}
+
+// normalizeParams encodes the parameters as described by RFC 5849 section
+// 3.4.1.3.2, sorted by key and then by value.
+func normalizeParams(params url.Values) string {
+	type pair struct{ key, value string }
+
+	pairs := make([]pair, 0, len(params))
+	for key, values := range params {
+		for _, value := range values {
+			pairs = append(pairs, pair{percentEncode(key), percentEncode(value)})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].key != pairs[j].key {
+			return pairs[i].key < pairs[j].key
+		}
+		return pairs[i].value < pairs[j].value
+	})
+
+	encoded := make([]string, len(pairs))
+	for i, p := range pairs {
+		encoded[i] = p.key + "=" + p.value
+	}
+	return strings.Join(encoded, "&")
+}
+
+// percentEncode encodes s as described by RFC 5849 section 3.6, which
+// leaves only the unreserved characters of RFC 3986 unescaped.
+func percentEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// newNonce returns a random string that identifies a single request.
+func newNonce() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
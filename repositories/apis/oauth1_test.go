@@ -0,0 +1,89 @@
+package apis
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOAuth1Auth_sign tests signing a request with the example from Twitter's
+// documentation on creating a signature.
+func TestOAuth1Auth_sign(t *testing.T) {
+	auth := NewOAuth1Auth(
+		"xvz1evFS4wEEPTGEFPHBog",
+		"kAcSOqF21Fu85e7zjz7ZN2U4ZRhfV3WpwPAoE3Z7kBw",
+		"370773112-GmHxMAgYyLbNEtIKZeRNFsMKPR9EyMZeS9weJAEb",
+		"LswwdoUaIvS8ltyTt5jkRh4J50vUPVVHtR2YPi5kE",
+	)
+
+	form := url.Values{}
+	form.Set("status", "Hello Ladies + Gentlemen, a signed OAuth request!")
+	req, _ := http.NewRequest("POST", "https://api.twitter.com/1.1/statuses/update.json?include_entities=true", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", formContentType)
+
+	auth.sign(req, []byte(form.Encode()), "kYjzVBB8Y0ZFabxSWbWovY3uYSQ2pTgmZeNu2VS4cg", time.Unix(1318622958, 0))
+
+	header := req.Header.Get("Authorization")
+	assert.True(t, strings.HasPrefix(header, "OAuth "))
+	assert.Contains(t, header, `oauth_consumer_key="xvz1evFS4wEEPTGEFPHBog"`)
+	assert.Contains(t, header, `oauth_nonce="kYjzVBB8Y0ZFabxSWbWovY3uYSQ2pTgmZeNu2VS4cg"`)
+	assert.Contains(t, header, `oauth_signature="hCtSmYh%2BiHYCEqBWrE7C7hYmtUk%3D"`)
+	assert.Contains(t, header, `oauth_timestamp="1318622958"`)
+	assert.Contains(t, header, `oauth_token="370773112-GmHxMAgYyLbNEtIKZeRNFsMKPR9EyMZeS9weJAEb"`)
+}
+
+// TestOAuth1Auth_IsAuthenticated tests that every credential is required.
+func TestOAuth1Auth_IsAuthenticated(t *testing.T) {
+	t.Run("all-credentials", func(t *testing.T) {
+		auth := NewOAuth1Auth("key", "secret", "token", "token-secret")
+		assert.True(t, auth.IsAuthenticated())
+		assert.NoError(t, auth.Authenticate(context.Background()))
+	})
+
+	t.Run("missing-access-token", func(t *testing.T) {
+		auth := NewOAuth1Auth("key", "secret", "", "")
+		assert.False(t, auth.IsAuthenticated())
+		assert.Error(t, auth.Authenticate(context.Background()))
+	})
+}
+
+// TestOAuth1Auth_AttachAttempt tests that every attempt of a request, including
+// retries, is signed with a new nonce.
+func TestOAuth1Auth_AttachAttempt(t *testing.T) {
+	statuses := []int{http.StatusServiceUnavailable, http.StatusBadGateway}
+	var nonces []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		start := strings.Index(header, `oauth_nonce="`) + len(`oauth_nonce="`)
+		nonces = append(nonces, header[start:start+strings.Index(header[start:], `"`)])
+
+		if len(nonces) <= len(statuses) {
+			w.WriteHeader(statuses[len(nonces)-1])
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	api, err := NewAPI(server.URL, NewOAuth1Auth("key", "secret", "token", "token-secret"), nil, nil)
+	assert.NoError(t, err)
+	api.Client.RetryWaitMin = time.Millisecond
+	api.Client.RetryWaitMax = time.Millisecond
+
+	req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+	_, err = api.Do(context.Background(), req, new(struct{}))
+
+	assert.NoError(t, err)
+	assert.Len(t, nonces, 3)
+	assert.NotEqual(t, nonces[0], nonces[1])
+	assert.NotEqual(t, nonces[1], nonces[2])
+	assert.NotEqual(t, nonces[0], nonces[2])
+}
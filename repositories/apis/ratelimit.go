@@ -0,0 +1,278 @@
+package apis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jake-hansen/followrs/apperrors"
+)
+
+// RateLimitPolicy determines what happens to a request whose rate limit is
+// exhausted.
+type RateLimitPolicy string
+
+const (
+	// FailFast fails the request with a *RateLimitError.
+	FailFast RateLimitPolicy = "fail"
+
+	// WaitForReset blocks the request until its rate limit resets.
+	WaitForReset RateLimitPolicy = "wait"
+
+	// QueueForReset is like WaitForReset, but requests that are waiting for
+	// the same rate limit are let through one at a time, in the order they
+	// began waiting.
+	QueueForReset RateLimitPolicy = "queue"
+)
+
+// ParseRateLimitPolicy returns the RateLimitPolicy with the given name.
+func ParseRateLimitPolicy(name string) (RateLimitPolicy, error) {
+	switch policy := RateLimitPolicy(name); policy {
+	case FailFast, WaitForReset, QueueForReset:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown rate limit policy %q", name)
+}
+
+// RateLimitKey identifies a rate limit. APIs limit each endpoint separately
+// for each user, or other identity, that requests are authenticated as.
+type RateLimitKey struct {
+	Endpoint string // Template of the endpoint, such as "GET /users/:id".
+	Identity string // Identity of the Auth the requests are made with.
+}
+
+// String describes the key.
+func (k RateLimitKey) String() string {
+	if k.Identity == "" {
+		return k.Endpoint
+	}
+	return fmt.Sprintf("%s as %s", k.Endpoint, k.Identity)
+}
+
+// RateLimit is the state of a rate limit as last reported by an API.
+type RateLimit struct {
+	Remaining int64     // Number of requests remaining in the current window.
+	Reset     time.Time // Time at which the current window ends.
+}
+
+// RateLimitHeaders names the headers in which an API reports the state of a
+// rate limit in its responses, since every API names them differently.
+type RateLimitHeaders struct {
+	Remaining string // Number of requests remaining, such as "x-ratelimit-remaining".
+	Reset     string // End of the window, in seconds since the Unix epoch or as an RFC 3339 timestamp.
+	Resource  string // Optional name of the rate limit the request counted towards, if not its endpoint's.
+}
+
+// Parse parses the rate limit reported by the given response headers, and
+// reports whether both headers are present and valid.
+func (h RateLimitHeaders) Parse(header http.Header) (RateLimit, bool) {
+	remaining, err := strconv.ParseInt(header.Get(h.Remaining), 10, 64)
+	if err != nil {
+		return RateLimit{}, false
+	}
+	reset, ok := parseResetTime(header.Get(h.Reset))
+	if !ok {
+		return RateLimit{}, false
+	}
+	return RateLimit{Remaining: remaining, Reset: reset}, true
+}
+
+// parseResetTime parses the time at which a rate limit resets, given in
+// seconds since the Unix epoch or as an RFC 3339 timestamp.
+func parseResetTime(value string) (time.Time, bool) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), true
+	}
+	if timestamp, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return timestamp, true
+	}
+	return time.Time{}, false
+}
+
+// RateLimitError is returned when a request is not sent because its rate
+// limit is exhausted.
+type RateLimitError struct {
+	Key   RateLimitKey
+	Reset time.Time
+}
+
+// Error describes the exhausted rate limit.
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit of %s reached until %s", e.Key, e.Reset.Format(time.RFC3339))
+}
+
+// Unwrap returns apperrors.ErrRateLimited.
+func (e *RateLimitError) Unwrap() error {
+	return apperrors.ErrRateLimited
+}
+
+// RetryAfter returns how long to wait before the request can be retried.
+func (e *RateLimitError) RetryAfter() time.Duration {
+	if wait := time.Until(e.Reset); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// rateLimitState is the state of a single rate limit in a RateLimitRegistry.
+type rateLimitState struct {
+	limit RateLimit
+	known bool          // Whether limit describes the current window.
+	turn  chan struct{} // Held by the request at the head of the queue.
+}
+
+// RateLimitRegistry keeps track of rate limits so that requests that would
+// exceed them are not sent. It is safe for concurrent use, so a single
+// registry can be shared by every request to an API.
+type RateLimitRegistry struct {
+	mu     sync.Mutex
+	limits map[RateLimitKey]*rateLimitState
+	now    func() time.Time
+}
+
+// NewRateLimitRegistry creates an empty RateLimitRegistry.
+func NewRateLimitRegistry() *RateLimitRegistry {
+	return &RateLimitRegistry{
+		limits: make(map[RateLimitKey]*rateLimitState),
+		now:    time.Now,
+	}
+}
+
+// Get returns the rate limit with the given key, and whether it is known.
+func (r *RateLimitRegistry) Get(key RateLimitKey) (RateLimit, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state, ok := r.limits[key]
+	if !ok || !state.known {
+		return RateLimit{}, false
+	}
+	return state.limit, true
+}
+
+// Update records the rate limit with the given key as reported by an API.
+func (r *RateLimitRegistry) Update(key RateLimitKey, limit RateLimit) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state := r.state(key)
+	state.limit = limit
+	state.known = true
+}
+
+// Acquire reserves one of the requests remaining under the rate limit with
+// the given key. If none remain, Acquire fails or waits according to the
+// policy. Requests whose rate limit is unknown are not limited.
+func (r *RateLimitRegistry) Acquire(ctx context.Context, key RateLimitKey, policy RateLimitPolicy) error {
+	if policy == QueueForReset {
+		r.mu.Lock()
+		turn := r.state(key).turn
+		r.mu.Unlock()
+
+		select {
+		case turn <- struct{}{}:
+			defer func() { <-turn }()
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for rate limit of %s: %w", key, ctx.Err())
+		}
+	}
+
+	for {
+		wait, err := r.reserve(key, policy)
+		if err != nil || wait <= 0 {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("waiting for rate limit of %s: %w", key, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve reserves a request under the rate limit with the given key, or
+// returns how long to wait before trying again.
+func (r *RateLimitRegistry) reserve(key RateLimitKey, policy RateLimitPolicy) (time.Duration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state, ok := r.limits[key]
+	if !ok || !state.known {
+		return 0, nil
+	}
+
+	now := r.now()
+	if !now.Before(state.limit.Reset) {
+		// The window has ended, so the limit is unknown until an API reports
+		// the limit of the next window.
+		state.known = false
+		return 0, nil
+	}
+	if state.limit.Remaining > 0 {
+		state.limit.Remaining--
+		return 0, nil
+	}
+
+	if policy == FailFast {
+		return 0, &RateLimitError{Key: key, Reset: state.limit.Reset}
+	}
+	return state.limit.Reset.Sub(now), nil
+}
+
+// state returns the state of the rate limit with the given key, creating it
+// if needed. The caller must hold r.mu.
+func (r *RateLimitRegistry) state(key RateLimitKey) *rateLimitState {
+	state, ok := r.limits[key]
+	if !ok {
+		state = &rateLimitState{turn: make(chan struct{}, 1)}
+		r.limits[key] = state
+	}
+	return state
+}
+
+// rateLimitPolicyContextKey is the key of the RateLimitPolicy carried by a context.
+type rateLimitPolicyContextKey struct{}
+
+// WithRateLimitPolicy returns a copy of ctx carrying the given policy, which
+// takes the place of the API's RateLimitPolicy for requests made with it.
+func WithRateLimitPolicy(ctx context.Context, policy RateLimitPolicy) context.Context {
+	return context.WithValue(ctx, rateLimitPolicyContextKey{}, policy)
+}
+
+// RateLimitKey returns the key of the rate limit of the given endpoint for
+// requests made with the given context.
+func (api *API) RateLimitKey(ctx context.Context, endpoint string) RateLimitKey {
+	key := RateLimitKey{Endpoint: endpoint}
+	if identifier, ok := api.auth(ctx).(Identifier); ok {
+		key.Identity = identifier.Identity()
+	}
+	return key
+}
+
+// AcquireRateLimit reserves a request to the given endpoint under its rate
+// limit, failing or waiting according to the RateLimitPolicy if none remain.
+func (api *API) AcquireRateLimit(ctx context.Context, endpoint string) error {
+	policy := api.RateLimitPolicy
+	if contextPolicy, ok := ctx.Value(rateLimitPolicyContextKey{}).(RateLimitPolicy); ok {
+		policy = contextPolicy
+	}
+	return api.RateLimits.Acquire(ctx, api.RateLimitKey(ctx, endpoint), policy)
+}
+
+// UpdateRateLimit records the rate limit of the given endpoint, as reported
+// in response to a request made with the given context.
+func (api *API) UpdateRateLimit(ctx context.Context, endpoint string, limit RateLimit) {
+	api.RateLimits.Update(api.RateLimitKey(ctx, endpoint), limit)
+}
+
+// RateLimit returns the rate limit of the given endpoint for requests made
+// with the given context, and whether it is known.
+func (api *API) RateLimit(ctx context.Context, endpoint string) (RateLimit, bool) {
+	return api.RateLimits.Get(api.RateLimitKey(ctx, endpoint))
+}
@@ -0,0 +1,167 @@
+package apis_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+	"github.com/stretchr/testify/assert"
+)
+
+var testKey = apis.RateLimitKey{Endpoint: "GET /users/:id"}
+
+func TestRateLimitRegistry(t *testing.T) {
+	t.Run("unknown-limit-not-enforced", func(t *testing.T) {
+		registry := apis.NewRateLimitRegistry()
+
+		err := registry.Acquire(context.Background(), testKey, apis.FailFast)
+		assert.NoError(t, err)
+	})
+
+	t.Run("remaining-requests-reserved", func(t *testing.T) {
+		registry := apis.NewRateLimitRegistry()
+		registry.Update(testKey, apis.RateLimit{Remaining: 1, Reset: time.Now().Add(time.Hour)})
+
+		err := registry.Acquire(context.Background(), testKey, apis.FailFast)
+		assert.NoError(t, err)
+		err = registry.Acquire(context.Background(), testKey, apis.FailFast)
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+
+		limit, ok := registry.Get(testKey)
+		assert.True(t, ok)
+		assert.Equal(t, int64(0), limit.Remaining)
+	})
+
+	t.Run("fail-fast", func(t *testing.T) {
+		registry := apis.NewRateLimitRegistry()
+		reset := time.Now().Add(time.Hour)
+		registry.Update(testKey, apis.RateLimit{Remaining: 0, Reset: reset})
+
+		err := registry.Acquire(context.Background(), testKey, apis.FailFast)
+
+		var rateLimitError *apis.RateLimitError
+		assert.True(t, errors.As(err, &rateLimitError))
+		assert.Equal(t, testKey, rateLimitError.Key)
+		assert.Equal(t, reset, rateLimitError.Reset)
+		assert.True(t, rateLimitError.RetryAfter() > 59*time.Minute)
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+	})
+
+	t.Run("wait-for-reset", func(t *testing.T) {
+		registry := apis.NewRateLimitRegistry()
+		reset := time.Now().Add(50 * time.Millisecond)
+		registry.Update(testKey, apis.RateLimit{Remaining: 0, Reset: reset})
+
+		err := registry.Acquire(context.Background(), testKey, apis.WaitForReset)
+
+		assert.NoError(t, err)
+		assert.False(t, time.Now().Before(reset))
+	})
+
+	t.Run("wait-cancelled", func(t *testing.T) {
+		registry := apis.NewRateLimitRegistry()
+		registry.Update(testKey, apis.RateLimit{Remaining: 0, Reset: time.Now().Add(time.Hour)})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := registry.Acquire(ctx, testKey, apis.WaitForReset)
+
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("queue-for-reset", func(t *testing.T) {
+		registry := apis.NewRateLimitRegistry()
+		registry.Update(testKey, apis.RateLimit{Remaining: 0, Reset: time.Now().Add(50 * time.Millisecond)})
+
+		acquired := make(chan int, 2)
+		for i := 0; i < 2; i++ {
+			go func(i int) {
+				if err := registry.Acquire(context.Background(), testKey, apis.QueueForReset); err == nil {
+					acquired <- i
+				}
+			}(i)
+			// Let the first request join the queue before the second.
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		for i := 0; i < 2; i++ {
+			select {
+			case n := <-acquired:
+				assert.Equal(t, i, n)
+			case <-time.After(time.Second):
+				t.Fatal("queued request was not let through")
+			}
+		}
+	})
+
+	t.Run("identities-limited-separately", func(t *testing.T) {
+		registry := apis.NewRateLimitRegistry()
+		first := apis.RateLimitKey{Endpoint: testKey.Endpoint, Identity: "user:1"}
+		second := apis.RateLimitKey{Endpoint: testKey.Endpoint, Identity: "user:2"}
+		registry.Update(first, apis.RateLimit{Remaining: 0, Reset: time.Now().Add(time.Hour)})
+
+		err := registry.Acquire(context.Background(), first, apis.FailFast)
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+		err = registry.Acquire(context.Background(), second, apis.FailFast)
+		assert.NoError(t, err)
+	})
+
+	t.Run("expired-window-not-enforced", func(t *testing.T) {
+		registry := apis.NewRateLimitRegistry()
+		registry.Update(testKey, apis.RateLimit{Remaining: 0, Reset: time.Now().Add(-time.Second)})
+
+		err := registry.Acquire(context.Background(), testKey, apis.FailFast)
+		assert.NoError(t, err)
+
+		_, ok := registry.Get(testKey)
+		assert.False(t, ok)
+	})
+}
+
+func TestParseRateLimitPolicy(t *testing.T) {
+	policy, err := apis.ParseRateLimitPolicy("wait")
+	assert.NoError(t, err)
+	assert.Equal(t, apis.WaitForReset, policy)
+
+	_, err = apis.ParseRateLimitPolicy("unknown")
+	assert.Error(t, err)
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	headers := apis.RateLimitHeaders{Remaining: "x-ratelimit-remaining", Reset: "x-ratelimit-reset"}
+	reset := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("unix-seconds", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-RateLimit-Remaining", "42")
+		header.Set("X-RateLimit-Reset", "1704110400")
+
+		limit, ok := headers.Parse(header)
+		assert.True(t, ok)
+		assert.Equal(t, int64(42), limit.Remaining)
+		assert.True(t, reset.Equal(limit.Reset))
+	})
+
+	t.Run("timestamp", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-RateLimit-Remaining", "0")
+		header.Set("X-RateLimit-Reset", "2024-01-01T12:00:00.000Z")
+
+		limit, ok := headers.Parse(header)
+		assert.True(t, ok)
+		assert.Equal(t, int64(0), limit.Remaining)
+		assert.True(t, reset.Equal(limit.Reset))
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-RateLimit-Remaining", "42")
+
+		_, ok := headers.Parse(header)
+		assert.False(t, ok)
+	})
+}
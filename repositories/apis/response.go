@@ -0,0 +1,103 @@
+package apis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// ResponseError is returned by Get when the API responds with a status other
+// than 200 OK and describes the error in the response body. Name and Message
+// are the error the API reported, if it could be decoded.
+type ResponseError struct {
+	HTTPError *HTTPError
+	Name      string // Identifies the kind of error, such as "InvalidRequest", if the API names its errors.
+	Message   string // Describes the error.
+
+	// Kind is the error from apperrors that the reported error indicates, if
+	// the status of the response doesn't, such as apperrors.ErrRateLimited for
+	// APIs that reject rate limited requests with 403 Forbidden.
+	Kind error
+}
+
+// Error describes the failed request and the error the API reported.
+func (e *ResponseError) Error() string {
+	switch {
+	case e.Name != "" && e.Message != "":
+		return fmt.Sprintf("%s: %s: %s", e.HTTPError.Error(), e.Name, e.Message)
+	case e.Message != "":
+		return fmt.Sprintf("%s: %s", e.HTTPError.Error(), e.Message)
+	case e.Name != "":
+		return fmt.Sprintf("%s: %s", e.HTTPError.Error(), e.Name)
+	}
+	return e.HTTPError.Error()
+}
+
+// Unwrap returns the Kind of the error, if it has one, or else the underlying
+// HTTPError.
+func (e *ResponseError) Unwrap() error {
+	if e.Kind != nil {
+		return e.Kind
+	}
+	return e.HTTPError
+}
+
+// ErrorDecoder decodes the error an API describes in the body of a failed
+// response, since every API describes its errors differently.
+type ErrorDecoder func(httpError *HTTPError) *ResponseError
+
+// Get requests the given path under the rate limit of the given endpoint,
+// which fails or waits according to the RateLimitPolicy when it is exhausted,
+// and stores the response in the given body. The rate limit is updated from
+// the RateLimitHeaders of the response, whether or not the request succeeds.
+//
+// If the API responds with a status other than 200 OK, the returned error is
+// the *ResponseError decoded by DecodeError, or the *HTTPError if the API has
+// no DecodeError.
+func (api *API) Get(ctx context.Context, endpoint string, path string, body interface{}) (*http.Response, error) {
+	if err := api.AcquireRateLimit(ctx, endpoint); err != nil {
+		return nil, fmt.Errorf("could not perform request to %s: %w", path, err)
+	}
+
+	request, err := retryablehttp.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := api.Do(ctx, request, body)
+	if err != nil {
+		var httpError *HTTPError
+		if !errors.As(err, &httpError) {
+			return nil, err
+		}
+		api.updateRateLimitFromHeader(ctx, endpoint, httpError.Header)
+		if api.DecodeError != nil {
+			return nil, api.DecodeError(httpError)
+		}
+		return nil, err
+	}
+
+	api.updateRateLimitFromHeader(ctx, endpoint, response.Header)
+	return response, nil
+}
+
+// updateRateLimitFromHeader records the rate limit of the given endpoint
+// reported by the given response headers, if they report it. Not every
+// response does, so the rate limit is left unchanged when it doesn't. If the
+// headers name the rate limit the request counted towards, it is recorded
+// under that name instead.
+func (api *API) updateRateLimitFromHeader(ctx context.Context, endpoint string, header http.Header) {
+	limit, ok := api.RateLimitHeaders.Parse(header)
+	if !ok {
+		return
+	}
+	if api.RateLimitHeaders.Resource != "" {
+		if resource := header.Get(api.RateLimitHeaders.Resource); resource != "" {
+			endpoint = resource
+		}
+	}
+	api.UpdateRateLimit(ctx, endpoint, limit)
+}
@@ -0,0 +1,90 @@
+package apis_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+	"github.com/stretchr/testify/assert"
+)
+
+// newGetServer creates an API whose requests to /test are answered with the
+// given status and body, reporting that the given number of requests remain
+// until the given time.
+func newGetServer(t *testing.T, status int, body string, remaining int, reset time.Time) *apis.API {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Remaining", fmt.Sprint(remaining))
+		w.Header().Set("X-Reset", fmt.Sprint(reset.Unix()))
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	api, err := apis.NewAPI(server.URL, nil, nil, nil)
+	assert.NoError(t, err)
+	api.Client.RetryMax = 0
+	api.RateLimitHeaders = apis.RateLimitHeaders{Remaining: "x-remaining", Reset: "x-reset"}
+	return api
+}
+
+func TestGet(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	t.Run("success", func(t *testing.T) {
+		api := newGetServer(t, http.StatusOK, `{"name":"test"}`, 9, reset)
+
+		var body struct {
+			Name string `json:"name"`
+		}
+		_, err := api.Get(context.Background(), "test", "/test", &body)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "test", body.Name)
+		limit, ok := api.RateLimit(context.Background(), "test")
+		assert.True(t, ok)
+		assert.Equal(t, apis.RateLimit{Remaining: 9, Reset: reset}, limit)
+	})
+
+	t.Run("decoded-error", func(t *testing.T) {
+		api := newGetServer(t, http.StatusForbidden, `{"error":"slow down"}`, 0, reset)
+		api.DecodeError = func(httpError *apis.HTTPError) *apis.ResponseError {
+			return &apis.ResponseError{HTTPError: httpError, Message: string(httpError.Body), Kind: apperrors.ErrRateLimited}
+		}
+
+		_, err := api.Get(context.Background(), "test", "/test", new(struct{}))
+
+		var responseError *apis.ResponseError
+		assert.True(t, errors.As(err, &responseError))
+		assert.Equal(t, http.StatusForbidden, responseError.HTTPError.StatusCode)
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+		limit, ok := api.RateLimit(context.Background(), "test")
+		assert.True(t, ok)
+		assert.Equal(t, int64(0), limit.Remaining)
+	})
+
+	t.Run("undecoded-error", func(t *testing.T) {
+		api := newGetServer(t, http.StatusNotFound, `{}`, 8, reset)
+
+		_, err := api.Get(context.Background(), "test", "/test", new(struct{}))
+
+		var httpError *apis.HTTPError
+		assert.True(t, errors.As(err, &httpError))
+		assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+	})
+
+	t.Run("exhausted-rate-limit", func(t *testing.T) {
+		api := newGetServer(t, http.StatusOK, `{}`, 0, reset)
+		api.UpdateRateLimit(context.Background(), "test", apis.RateLimit{Remaining: 0, Reset: reset})
+
+		_, err := api.Get(context.Background(), "test", "/test", new(struct{}))
+
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+	})
+}
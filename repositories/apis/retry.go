@@ -0,0 +1,138 @@
+package apis
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// DefaultMaxRetryWait is the longest a request waits for a rate limit to reset
+// before being retried, unless configured otherwise. It is kept shorter than
+// the timeouts requests are usually made with, so that waiting doesn't use up
+// the time left for the retry itself.
+const DefaultMaxRetryWait = 5 * time.Second
+
+// RetryPolicy decides which failed requests are retried, and when. Requests
+// rejected with 429 Too Many Requests are retried once their rate limit
+// resets, as reported by the Retry-After header or the ResetHeader, rather
+// than with exponential backoff, and fail immediately if neither reports it.
+// Other failures are retried as by retryablehttp.DefaultRetryPolicy.
+type RetryPolicy struct {
+	// MaxWait is the longest a request waits for its rate limit to reset. A
+	// request whose rate limit resets later, or after its context's deadline,
+	// is not retried.
+	MaxWait time.Duration
+
+	// ResetHeader is the name of the header in which the API reports when a
+	// rate limit resets, such as "x-rate-limit-reset", in seconds since the
+	// Unix epoch or as an RFC 3339 timestamp. Only Retry-After is used when
+	// empty.
+	ResetHeader string
+
+	now func() time.Time
+}
+
+// NewRetryPolicy creates a RetryPolicy that waits up to maxWait for rate
+// limits to reset.
+func NewRetryPolicy(maxWait time.Duration) *RetryPolicy {
+	return &RetryPolicy{
+		MaxWait: maxWait,
+		now:     time.Now,
+	}
+}
+
+// CheckRetry implements retryablehttp.CheckRetry.
+func (p *RetryPolicy) CheckRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	}
+
+	wait, ok := p.resetAfter(resp)
+	if !ok || wait > p.MaxWait {
+		return false, nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && p.now().Add(wait).After(deadline) {
+		return false, nil
+	}
+	return true, nil
+}
+
+// Backoff implements retryablehttp.Backoff. Requests rejected with 429 Too
+// Many Requests wait until their rate limit resets, but for at least min.
+func (p *RetryPolicy) Backoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		if wait, ok := p.resetAfter(resp); ok {
+			if wait < min {
+				return min
+			}
+			return wait
+		}
+	}
+	return retryablehttp.DefaultBackoff(min, max, attemptNum, resp)
+}
+
+// resetAfter returns how long until the rate limit that rejected the response
+// resets, and whether the response reports it.
+func (p *RetryPolicy) resetAfter(resp *http.Response) (time.Duration, bool) {
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.ParseInt(retryAfter, 10, 64); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return date.Sub(p.now()), true
+		}
+	}
+
+	if p.ResetHeader != "" {
+		if reset, ok := parseResetTime(resp.Header.Get(p.ResetHeader)); ok {
+			return reset.Sub(p.now()), true
+		}
+	}
+
+	return 0, false
+}
+
+// RetryCounter counts the retries of the requests made with a context.
+type RetryCounter struct {
+	retries int64
+}
+
+// Retries returns the number of retries counted.
+func (c *RetryCounter) Retries() int {
+	return int(atomic.LoadInt64(&c.retries))
+}
+
+// retryCounterContextKey is the key of the RetryCounter carried by a context.
+type retryCounterContextKey struct{}
+
+// WithRetryCounter returns a copy of ctx carrying a RetryCounter, which
+// counts the retries of every request that Do makes with the returned context.
+func WithRetryCounter(ctx context.Context) (context.Context, *RetryCounter) {
+	counter := new(RetryCounter)
+	return context.WithValue(ctx, retryCounterContextKey{}, counter), counter
+}
+
+// retryCounterFromContext returns the RetryCounter carried by ctx, or nil if
+// there is none.
+func retryCounterFromContext(ctx context.Context) *RetryCounter {
+	counter, _ := ctx.Value(retryCounterContextKey{}).(*RetryCounter)
+	return counter
+}
+
+// countRetry implements retryablehttp.RequestLogHook by counting each retry
+// in the RetryCounter carried by the request's context.
+func countRetry(_ retryablehttp.Logger, req *http.Request, attemptNum int) {
+	if attemptNum == 0 {
+		return
+	}
+	if counter := retryCounterFromContext(req.Context()); counter != nil {
+		atomic.AddInt64(&counter.retries, 1)
+	}
+}
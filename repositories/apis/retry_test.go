@@ -0,0 +1,155 @@
+package apis_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRetryServer creates an API whose requests to /test are answered with
+// the given statuses in order, followed by 200 OK. Rejected requests are told
+// to retry after the given Retry-After value, if any. It returns a pointer to
+// the number of requests received.
+func newRetryServer(t *testing.T, retryAfter string, statuses ...int) (*apis.API, *httptest.Server, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= len(statuses) {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(statuses[requests-1])
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+
+	api, err := apis.NewAPI(server.URL, nil, nil, nil)
+	assert.NoError(t, err)
+	api.Client.RetryWaitMin = time.Millisecond
+	api.Client.RetryWaitMax = 10 * time.Millisecond
+
+	return api, server, &requests
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("rate-limited-request-retried-after-reset", func(t *testing.T) {
+		api, server, requests := newRetryServer(t, "0", http.StatusTooManyRequests)
+		defer server.Close()
+
+		ctx, counter := apis.WithRetryCounter(context.Background())
+		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+		_, err := api.Do(ctx, req, new(struct{}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, *requests)
+		assert.Equal(t, 1, counter.Retries())
+	})
+
+	t.Run("rate-limited-request-not-retried-past-max-wait", func(t *testing.T) {
+		api, server, requests := newRetryServer(t, "3600", http.StatusTooManyRequests)
+		defer server.Close()
+
+		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+		_, err := api.Do(context.Background(), req, new(struct{}))
+
+		var httpError *apis.HTTPError
+		assert.True(t, errors.As(err, &httpError))
+		assert.Equal(t, 0, httpError.Retries)
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+		assert.Equal(t, 1, *requests)
+	})
+
+	t.Run("rate-limited-request-not-retried-past-deadline", func(t *testing.T) {
+		api, server, requests := newRetryServer(t, "5", http.StatusTooManyRequests)
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+		_, err := api.Do(ctx, req, new(struct{}))
+
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+		assert.Equal(t, 1, *requests)
+	})
+
+	t.Run("rate-limited-request-without-reset-not-retried", func(t *testing.T) {
+		api, server, requests := newRetryServer(t, "", http.StatusTooManyRequests)
+		defer server.Close()
+
+		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+		_, err := api.Do(context.Background(), req, new(struct{}))
+
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+		assert.Equal(t, 1, *requests)
+	})
+
+	t.Run("server-errors-retried", func(t *testing.T) {
+		api, server, requests := newRetryServer(t, "", http.StatusBadGateway, http.StatusBadGateway)
+		defer server.Close()
+
+		ctx, counter := apis.WithRetryCounter(context.Background())
+		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+		_, err := api.Do(ctx, req, new(struct{}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, *requests)
+		assert.Equal(t, 2, counter.Retries())
+	})
+
+	t.Run("retries-reported-by-error", func(t *testing.T) {
+		api, server, _ := newRetryServer(t, "", http.StatusBadGateway, http.StatusBadGateway)
+		defer server.Close()
+		api.Client.RetryMax = 1
+
+		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+		_, err := api.Do(context.Background(), req, new(struct{}))
+
+		var httpError *apis.HTTPError
+		assert.True(t, errors.As(err, &httpError))
+		assert.Equal(t, http.StatusBadGateway, httpError.StatusCode)
+		assert.Equal(t, 1, httpError.Retries)
+	})
+
+	t.Run("backoff-until-reset-header", func(t *testing.T) {
+		policy := apis.NewRetryPolicy(time.Hour)
+		policy.ResetHeader = "x-rate-limit-reset"
+
+		reset := time.Now().Add(10 * time.Minute).Unix()
+		resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+		resp.Header.Set("x-rate-limit-reset", strconv.FormatInt(reset, 10))
+
+		wait := policy.Backoff(time.Second, 30*time.Second, 0, resp)
+		assert.True(t, wait > 9*time.Minute && wait <= 10*time.Minute)
+
+		retry, err := policy.CheckRetry(context.Background(), resp, nil)
+		assert.NoError(t, err)
+		assert.True(t, retry)
+
+		policy.MaxWait = time.Minute
+		retry, err = policy.CheckRetry(context.Background(), resp, nil)
+		assert.NoError(t, err)
+		assert.False(t, retry)
+	})
+
+	t.Run("backoff-until-reset-timestamp", func(t *testing.T) {
+		policy := apis.NewRetryPolicy(time.Hour)
+		policy.ResetHeader = "x-ratelimit-reset"
+
+		resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+		resp.Header.Set("x-ratelimit-reset", time.Now().Add(10*time.Minute).UTC().Format(time.RFC3339Nano))
+
+		wait := policy.Backoff(time.Second, 30*time.Second, 0, resp)
+		assert.True(t, wait > 9*time.Minute && wait <= 10*time.Minute)
+	})
+}
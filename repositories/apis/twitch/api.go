@@ -0,0 +1,76 @@
+package twitch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/jake-hansen/followrs/repositories/apis"
+)
+
+// rateLimitEndpoint is the name of the rate limit of every request to the
+// Twitch API. Twitch limits the requests of each access token with a single
+// bucket of points, regardless of the endpoint.
+const rateLimitEndpoint = "helix"
+
+// rateLimitHeaders are the headers in which Twitch reports its rate limit.
+var rateLimitHeaders = apis.RateLimitHeaders{Remaining: "ratelimit-remaining", Reset: "ratelimit-reset"}
+
+// API provides the services needed to interact with the Twitch API.
+type API struct {
+	Client *apis.API
+}
+
+// decodeError decodes the error Twitch describes in the body of a failed
+// response, which names the status, such as "Bad Request", and describes what
+// was wrong with the request.
+func decodeError(httpError *apis.HTTPError) *apis.ResponseError {
+	responseError := &apis.ResponseError{HTTPError: httpError}
+
+	var body struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(httpError.Body, &body); err == nil {
+		responseError.Name = body.Error
+		responseError.Message = body.Message
+	}
+	return responseError
+}
+
+// NewTwitchAPI creates an API that requests the Twitch API at the given URL,
+// such as "https://api.twitch.tv/helix", with app access tokens obtained from
+// the token endpoint at the given URL, such as
+// "https://id.twitch.tv/oauth2/token", for the application with the given
+// client ID and secret.
+func NewTwitchAPI(baseURL string, tokenURL string, clientID string, clientSecret string) (*API, error) {
+	auth := NewAppTokenAuth(clientID, clientSecret, tokenURL)
+
+	api, err := apis.NewAPI(baseURL, auth, auth.Attach, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not create Twitch API: %w", err)
+	}
+	// Twitch refills its bucket of points continuously, and ratelimit-reset
+	// is when it will be full again, in seconds since the Unix epoch.
+	api.RetryPolicy.ResetHeader = "ratelimit-reset"
+	api.RateLimitHeaders = rateLimitHeaders
+	api.DecodeError = decodeError
+
+	auth.client = api.Client
+
+	return &API{Client: api}, nil
+}
+
+// RateLimit returns the rate limit of requests to Twitch, and whether it is
+// known.
+func (a *API) RateLimit(ctx context.Context) (apis.RateLimit, bool) {
+	return a.Client.RateLimit(ctx, rateLimitEndpoint)
+}
+
+// get requests the given path with the given parameters, as by apis.API's
+// Get.
+func (a *API) get(ctx context.Context, path string, params url.Values, body interface{}) error {
+	_, err := a.Client.Get(ctx, rateLimitEndpoint, fmt.Sprintf("%s?%s", path, params.Encode()), body)
+	return err
+}
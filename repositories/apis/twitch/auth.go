@@ -0,0 +1,177 @@
+package twitch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+)
+
+// maxTokenResponseSize is the largest response from the Twitch token endpoint
+// that is read.
+const maxTokenResponseSize = 1 << 16
+
+// expiryDelta is how long before its expiry an app access token is replaced.
+// App access tokens last for months, so a generous margin costs nothing.
+const expiryDelta = time.Minute
+
+// tokenResponse is the response to an app access token request.
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"` // Seconds until the token expires.
+	TokenType   string `json:"token_type"`
+}
+
+// AppTokenAuth authenticates requests to the Twitch API with an app access
+// token, which is obtained from the client ID and secret of a registered
+// application using the OAuth 2.0 client credentials flow. App access tokens
+// expire after about two months, after which a new one is obtained. Twitch
+// also requires the client ID with every request, in the Client-Id header.
+type AppTokenAuth struct {
+	ClientID     string
+	ClientSecret string
+
+	client   *retryablehttp.Client
+	tokenURL string
+	now      func() time.Time
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time // Zero if Twitch didn't say when the token expires.
+}
+
+// NewAppTokenAuth creates an AppTokenAuth that obtains app access tokens for
+// the application with the given client ID and secret from the token
+// endpoint at the given URL, such as "https://id.twitch.tv/oauth2/token".
+func NewAppTokenAuth(clientID string, clientSecret string, tokenURL string) *AppTokenAuth {
+	return &AppTokenAuth{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		tokenURL:     tokenURL,
+		now:          time.Now,
+	}
+}
+
+// IsAuthenticated determines if the app access token is present and
+// unexpired.
+func (a *AppTokenAuth) IsAuthenticated() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.unexpired()
+}
+
+// Authenticate obtains a new app access token, unless the current one is
+// still valid.
+func (a *AppTokenAuth) Authenticate(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.unexpired() {
+		return nil
+	}
+	if a.ClientID == "" || a.ClientSecret == "" || a.client == nil {
+		return fmt.Errorf("no Twitch client ID and secret configured: %w", apperrors.ErrUnauthorized)
+	}
+
+	token, err := a.requestToken(ctx)
+	if err != nil {
+		return fmt.Errorf("could not obtain Twitch app access token: %w", err)
+	}
+	a.token = token.AccessToken
+	a.expiry = time.Time{}
+	if token.ExpiresIn > 0 {
+		a.expiry = a.now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return nil
+}
+
+// Invalidate discards the app access token so that a new one is obtained by
+// the next call to Authenticate, such as when Twitch has revoked it.
+func (a *AppTokenAuth) Invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.token = ""
+}
+
+// Attach attaches the client ID and the app access token to a request.
+func (a *AppTokenAuth) Attach(req *retryablehttp.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	req.Header.Set("Client-Id", a.ClientID)
+	if a.token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.token))
+	}
+}
+
+// unexpired determines if the app access token is present and, if Twitch
+// said when it expires, hasn't expired. The caller must hold a.mu.
+func (a *AppTokenAuth) unexpired() bool {
+	if a.token == "" {
+		return false
+	}
+	return a.expiry.IsZero() || a.now().Before(a.expiry.Add(-expiryDelta))
+}
+
+// requestToken exchanges the client ID and secret for an app access token.
+// The caller must hold a.mu.
+func (a *AppTokenAuth) requestToken(ctx context.Context) (*tokenResponse, error) {
+	form := url.Values{}
+	form.Set("client_id", a.ClientID)
+	form.Set("client_secret", a.ClientSecret)
+	form.Set("grant_type", "client_credentials")
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, a.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := a.client.Do(req)
+	if err != nil {
+		if response != nil {
+			response.Body.Close()
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, fmt.Errorf("%w: %s", apperrors.ErrUpstreamUnavailable, err.Error())
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, maxTokenResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, decodeError(&apis.HTTPError{
+			URL:        a.tokenURL,
+			StatusCode: response.StatusCode,
+			Header:     response.Header,
+			Body:       body,
+		})
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("could not decode body: %w", err)
+	}
+	if !strings.EqualFold(token.TokenType, "bearer") || token.AccessToken == "" {
+		return nil, errors.New("token response did not contain a bearer token")
+	}
+	return &token, nil
+}
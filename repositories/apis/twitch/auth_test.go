@@ -0,0 +1,133 @@
+package twitch_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+	"github.com/jake-hansen/followrs/repositories/apis/twitch"
+	"github.com/stretchr/testify/assert"
+)
+
+// tokenServer is a Twitch server that issues app access tokens and records
+// the headers that users are requested with.
+type tokenServer struct {
+	mu        sync.Mutex
+	tokens    []string // Tokens issued, in order.
+	expiresIn int64
+	issued    int
+	requests  []http.Header
+	reject    map[string]bool // Authorization headers that /users responds to with 401.
+}
+
+// newTokenServer creates a tokenServer that issues the given tokens, which
+// expire after the given number of seconds, and an API that obtains them with
+// the given client secret.
+func newTokenServer(t *testing.T, clientSecret string, expiresIn int64, tokens ...string) (*tokenServer, *twitch.API) {
+	s := &tokenServer{tokens: tokens, expiresIn: expiresIn, reject: make(map[string]bool)}
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "client_credentials", r.FormValue("grant_type"))
+		assert.Equal(t, "client-id", r.FormValue("client_id"))
+		if r.FormValue("client_secret") != "client-secret" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"status":403,"message":"invalid client secret"}`)
+			return
+		}
+		writeJSON(t, w, map[string]interface{}{
+			"access_token": s.tokens[s.issued],
+			"expires_in":   s.expiresIn,
+			"token_type":   "bearer",
+		})
+		s.issued++
+	})
+	mux.HandleFunc("/helix/users", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.requests = append(s.requests, r.Header)
+		if s.reject[r.Header.Get("Authorization")] {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"Unauthorized","status":401,"message":"Invalid OAuth token"}`)
+			return
+		}
+		writeJSON(t, w, map[string]interface{}{"data": []twitch.User{{ID: "12826", Login: "twitch"}}})
+	})
+
+	api, err := twitch.NewTwitchAPI(server.URL+"/helix", server.URL+"/oauth2/token", "client-id", clientSecret)
+	assert.NoError(t, err)
+	return s, api
+}
+
+// TestAppTokenAuth tests that requests are authenticated with the app access
+// tokens AppTokenAuth obtains.
+func TestAppTokenAuth(t *testing.T) {
+	t.Run("obtains-token", func(t *testing.T) {
+		s, api := newTokenServer(t, "client-secret", 5000000, "token")
+
+		for i := 0; i < 2; i++ {
+			_, err := api.GetUser(context.Background(), "twitch")
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 1, s.issued)
+		for _, header := range s.requests {
+			assert.Equal(t, "Bearer token", header.Get("Authorization"))
+			assert.Equal(t, "client-id", header.Get("Client-Id"))
+		}
+	})
+
+	t.Run("refreshes-expired-token", func(t *testing.T) {
+		// Tokens that expire within a minute are considered expired already.
+		s, api := newTokenServer(t, "client-secret", 30, "expiring", "token")
+
+		for i := 0; i < 2; i++ {
+			_, err := api.GetUser(context.Background(), "twitch")
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 2, s.issued)
+		assert.Equal(t, "Bearer token", s.requests[1].Get("Authorization"))
+	})
+
+	t.Run("refreshes-rejected-token", func(t *testing.T) {
+		s, api := newTokenServer(t, "client-secret", 5000000, "revoked", "token")
+		s.reject["Bearer revoked"] = true
+
+		_, err := api.GetUser(context.Background(), "twitch")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, s.issued)
+		assert.Len(t, s.requests, 2)
+		assert.Equal(t, "Bearer token", s.requests[1].Get("Authorization"))
+	})
+
+	t.Run("invalid-secret", func(t *testing.T) {
+		s, api := newTokenServer(t, "wrong-secret", 5000000)
+
+		_, err := api.GetUser(context.Background(), "twitch")
+		assert.True(t, errors.Is(err, apperrors.ErrUnauthorized))
+		var responseError *apis.ResponseError
+		assert.True(t, errors.As(err, &responseError))
+		assert.Equal(t, "invalid client secret", responseError.Message)
+		assert.Empty(t, s.requests)
+	})
+
+	t.Run("no-credentials", func(t *testing.T) {
+		api, err := twitch.NewTwitchAPI("https://api.twitch.tv/helix", "https://id.twitch.tv/oauth2/token", "", "")
+		assert.NoError(t, err)
+
+		_, err = api.GetUser(context.Background(), "twitch")
+		assert.True(t, errors.Is(err, apperrors.ErrUnauthorized))
+	})
+}
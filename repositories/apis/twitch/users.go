@@ -0,0 +1,144 @@
+package twitch
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jake-hansen/followrs/apperrors"
+)
+
+// maxFollowersResults is the largest page size Twitch allows when listing the
+// followers of a channel.
+const maxFollowersResults = 100
+
+// loginPattern matches the logins Twitch allows, which are up to 25 letters,
+// digits and underscores that don't start with an underscore.
+var loginPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_]{0,24}$`)
+
+// User represents a Twitch user.
+type User struct {
+	ID              string     `json:"id"`
+	Login           string     `json:"login"`
+	DisplayName     string     `json:"display_name"`
+	Type            string     `json:"type"`             // "staff", "admin", "global_mod" or "".
+	BroadcasterType string     `json:"broadcaster_type"` // "partner", "affiliate" or "".
+	Description     string     `json:"description"`
+	ProfileImageURL string     `json:"profile_image_url"`
+	CreatedAt       *time.Time `json:"created_at"`
+}
+
+// Follower represents a user that follows a channel.
+type Follower struct {
+	UserID     string     `json:"user_id"`
+	UserLogin  string     `json:"user_login"`
+	UserName   string     `json:"user_name"`
+	FollowedAt *time.Time `json:"followed_at"`
+}
+
+// Followers is a page of the followers of a channel.
+type Followers struct {
+	// Total is the number of users that follow the channel.
+	Total int64 `json:"total"`
+
+	// Data lists the followers on the page. Twitch only lists them to the
+	// broadcaster and moderators of the channel, so it is empty when
+	// requested with an app access token.
+	Data []Follower `json:"data"`
+
+	Pagination struct {
+		Cursor string `json:"cursor"` // Cursor of the next page, or empty on the last page.
+	} `json:"pagination"`
+}
+
+// GetUser returns the user with the given login.
+func (a *API) GetUser(ctx context.Context, login string) (*User, error) {
+	login, err := normalizeLogin(login)
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		Data []User `json:"data"`
+	}
+	if err := a.get(ctx, "/users", url.Values{"login": {login}}, &body); err != nil {
+		return nil, err
+	}
+
+	// Twitch responds with no users, rather than 404 Not Found, when none
+	// match, including users that have been banned.
+	if len(body.Data) == 0 {
+		return nil, fmt.Errorf("user %s: %w", login, apperrors.ErrNotFound)
+	}
+	return &body.Data[0], nil
+}
+
+// GetFollowerCount returns the number of users that follow the channel of the
+// broadcaster with the given user ID.
+func (a *API) GetFollowerCount(ctx context.Context, broadcasterID string) (int64, error) {
+	page, err := a.getFollowers(ctx, broadcasterID, 1, "")
+	if err != nil {
+		return 0, err
+	}
+	return page.Total, nil
+}
+
+// GetFollowers returns every follower listed for the channel of the
+// broadcaster with the given user ID, along with the number of users that
+// follow it, which is more than are listed when Twitch withholds the list.
+// Pages are requested until Twitch stops returning a cursor, or returns the
+// cursor it was given.
+func (a *API) GetFollowers(ctx context.Context, broadcasterID string) ([]Follower, int64, error) {
+	var (
+		followers []Follower
+		total     int64
+		cursor    string
+	)
+	for {
+		page, err := a.getFollowers(ctx, broadcasterID, maxFollowersResults, cursor)
+		if err != nil {
+			return nil, 0, err
+		}
+		followers = append(followers, page.Data...)
+		total = page.Total
+
+		if page.Pagination.Cursor == "" || page.Pagination.Cursor == cursor {
+			return followers, total, nil
+		}
+		cursor = page.Pagination.Cursor
+	}
+}
+
+// getFollowers requests the page of followers after the given cursor.
+func (a *API) getFollowers(ctx context.Context, broadcasterID string, first int, cursor string) (*Followers, error) {
+	if _, err := strconv.ParseUint(broadcasterID, 10, 64); err != nil {
+		return nil, fmt.Errorf("%q is not the ID of a Twitch user: %w", broadcasterID, apperrors.ErrInvalidUsername)
+	}
+
+	params := url.Values{
+		"broadcaster_id": {broadcasterID},
+		"first":          {strconv.Itoa(first)},
+	}
+	if cursor != "" {
+		params.Set("after", cursor)
+	}
+
+	page := new(Followers)
+	if err := a.get(ctx, "/channels/followers", params, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// normalizeLogin validates the given login and returns it in lower case,
+// which is how Twitch stores logins.
+func normalizeLogin(login string) (string, error) {
+	if !loginPattern.MatchString(login) {
+		return "", fmt.Errorf("%q is not a Twitch login: %w", login, apperrors.ErrInvalidUsername)
+	}
+	return strings.ToLower(login), nil
+}
@@ -0,0 +1,160 @@
+package twitch_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+	"github.com/jake-hansen/followrs/repositories/apis/twitch"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeJSON(t *testing.T, w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	assert.NoError(t, json.NewEncoder(w).Encode(body))
+}
+
+// newTestAPI creates an API for a Twitch server that issues app access tokens
+// and responds with the handlers registered on the returned mux under /helix.
+func newTestAPI(t *testing.T) (*http.ServeMux, *twitch.API) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]interface{}{"access_token": "token", "expires_in": 5000000, "token_type": "bearer"})
+	})
+
+	api, err := twitch.NewTwitchAPI(server.URL+"/helix", server.URL+"/oauth2/token", "client-id", "client-secret")
+	assert.NoError(t, err)
+	return mux, api
+}
+
+// TestGetUser tests the GetUser function of API.
+func TestGetUser(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mux, api := newTestAPI(t)
+		mux.HandleFunc("/helix/users", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "twitchdev", r.URL.Query().Get("login"))
+			fmt.Fprint(w, `{"data":[{"id":"141981764","login":"twitchdev","display_name":"TwitchDev","type":"","broadcaster_type":"partner","description":"Supporting third-party developers","profile_image_url":"https://static-cdn.jtvnw.net/profile.png","created_at":"2016-12-14T20:32:28Z"}]}`)
+		})
+
+		user, err := api.GetUser(context.Background(), "TwitchDev")
+		assert.NoError(t, err)
+		assert.Equal(t, "141981764", user.ID)
+		assert.Equal(t, "TwitchDev", user.DisplayName)
+		assert.Equal(t, "partner", user.BroadcasterType)
+		assert.True(t, time.Date(2016, time.December, 14, 20, 32, 28, 0, time.UTC).Equal(*user.CreatedAt))
+	})
+
+	t.Run("invalid-login", func(t *testing.T) {
+		_, api := newTestAPI(t)
+
+		for _, login := range []string{"", "_twitch", "twitch dev", "twitch/dev", "this_login_is_far_too_long"} {
+			user, err := api.GetUser(context.Background(), login)
+			assert.Nil(t, user, login)
+			assert.True(t, errors.Is(err, apperrors.ErrInvalidUsername), login)
+		}
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mux, api := newTestAPI(t)
+		mux.HandleFunc("/helix/users", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"data":[]}`)
+		})
+
+		user, err := api.GetUser(context.Background(), "nobody")
+		assert.Nil(t, user)
+		assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+	})
+
+	t.Run("rate-limited", func(t *testing.T) {
+		reset := time.Now().Add(time.Minute).Truncate(time.Second)
+		mux, api := newTestAPI(t)
+		api.Client.RateLimitPolicy = apis.FailFast
+		mux.HandleFunc("/helix/users", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Ratelimit-Limit", "800")
+			w.Header().Set("Ratelimit-Remaining", "0")
+			w.Header().Set("Ratelimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			writeJSON(t, w, map[string]interface{}{"data": []twitch.User{{ID: "141981764"}}})
+		})
+
+		_, err := api.GetUser(context.Background(), "twitchdev")
+		assert.NoError(t, err)
+
+		limit, ok := api.RateLimit(context.Background())
+		assert.True(t, ok)
+		assert.Equal(t, int64(0), limit.Remaining)
+		assert.True(t, reset.Equal(limit.Reset))
+
+		_, err = api.GetUser(context.Background(), "twitchdev")
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+	})
+}
+
+// TestGetFollowers tests the GetFollowers and GetFollowerCount functions of
+// API.
+func TestGetFollowers(t *testing.T) {
+	t.Run("count", func(t *testing.T) {
+		mux, api := newTestAPI(t)
+		mux.HandleFunc("/helix/channels/followers", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "141981764", r.URL.Query().Get("broadcaster_id"))
+			assert.Equal(t, "1", r.URL.Query().Get("first"))
+			fmt.Fprint(w, `{"total":8,"data":[],"pagination":{}}`)
+		})
+
+		count, err := api.GetFollowerCount(context.Background(), "141981764")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(8), count)
+	})
+
+	t.Run("paginates", func(t *testing.T) {
+		mux, api := newTestAPI(t)
+		mux.HandleFunc("/helix/channels/followers", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "100", r.URL.Query().Get("first"))
+			switch r.URL.Query().Get("after") {
+			case "":
+				fmt.Fprint(w, `{"total":3,"data":[{"user_id":"1","user_login":"one"},{"user_id":"2","user_login":"two"}],"pagination":{"cursor":"page2"}}`)
+			case "page2":
+				fmt.Fprint(w, `{"total":3,"data":[{"user_id":"3","user_login":"three"}],"pagination":{}}`)
+			default:
+				t.Errorf("unexpected cursor %s", r.URL.Query().Get("after"))
+			}
+		})
+
+		followers, total, err := api.GetFollowers(context.Background(), "141981764")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, []twitch.Follower{{UserID: "1", UserLogin: "one"}, {UserID: "2", UserLogin: "two"}, {UserID: "3", UserLogin: "three"}}, followers)
+	})
+
+	t.Run("repeated-cursor", func(t *testing.T) {
+		mux, api := newTestAPI(t)
+		requests := 0
+		mux.HandleFunc("/helix/channels/followers", func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			fmt.Fprint(w, `{"total":1,"data":[],"pagination":{"cursor":"same"}}`)
+		})
+
+		followers, total, err := api.GetFollowers(context.Background(), "141981764")
+		assert.NoError(t, err)
+		assert.Empty(t, followers)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, 2, requests)
+	})
+
+	t.Run("invalid-id", func(t *testing.T) {
+		_, api := newTestAPI(t)
+
+		_, err := api.GetFollowerCount(context.Background(), "twitchdev")
+		assert.True(t, errors.Is(err, apperrors.ErrInvalidUsername))
+	})
+}
@@ -1,34 +1,17 @@
 package twitter
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
-	"strconv"
+	"net/url"
 	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/jake-hansen/followrs/repositories/apis"
 )
 
-// twitterAuth contains the API keys needed to authenticate to the
-// Twitter API.
-type twitterAuth struct {
-	APIKey         string
-	APISecretKey   string
-	APIBearerToken string
-}
-
-// Twitter API is authenticated with pre generated tokens that don't expire.
-// So, the client will always be considered authenticated.
-func (a *twitterAuth) IsAuthenticated() bool {
-	return true
-}
-
-// Attach attaches the API Bearer Token to a request.
-func (a *twitterAuth) Attach(req *retryablehttp.Request) {
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.APIBearerToken))
-}
-
 // API provides the services needed to interact with the Twitter API.
 type API struct {
 	Client      *apis.API
@@ -39,10 +22,18 @@ type API struct {
 type DataWrapper struct {
 	Data   interface{} `json:"data"`
 	Errors *[]Error    `json:"errors"`
+	Meta   *Meta       `json:"meta"`
+}
+
+// Meta contains information about a paginated response from the Twitter API.
+type Meta struct {
+	ResultCount int    `json:"result_count"`
+	NextToken   string `json:"next_token"`
 }
 
 // Error represents a problem that the Twitter API can return upon a
-// request that fails.
+// request that fails. Requests that fail outright are described by Status,
+// while older endpoints describe their errors with Code and Message.
 type Error struct {
 	Detail       string `json:"detail"`
 	Title        string `json:"title"`
@@ -50,82 +41,155 @@ type Error struct {
 	Parameter    string `json:"parameter"`
 	Value        string `json:"value"`
 	Type         string `json:"type"`
+	Status       int    `json:"status"`
+	Code         int    `json:"code"`
+	Message      string `json:"message"`
 }
 
-// Endpoint represents a Twitter API endpoint. An endpoint contains information
-// about the rate limits for itself.
-type Endpoint struct {
-	URL            string
-	RemainingCalls int64
-	RateLimitReset time.Time
+// Error returns the most specific explanation the Error contains, so that
+// problems Twitter reports alongside a successful response can be wrapped.
+func (e Error) Error() string {
+	return e.description()
 }
 
-func (e *Endpoint) parseRateLimitInfo(response *http.Response) error {
-	headerNotFoundError := func(headerName string) error {
-		return fmt.Errorf("header %s not found in response", headerName)
+// description returns the most specific explanation the Error contains.
+func (e Error) description() string {
+	switch {
+	case e.Detail != "":
+		return e.Detail
+	case e.Message != "":
+		return e.Message
 	}
+	return e.Title
+}
 
-	headerParseError := func(headerName string, err error) error {
-		return fmt.Errorf("could not parse rate limit header %s: %w", headerName, err)
-	}
+// ResponseError is returned when the Twitter API responds to a request with
+// a status other than 200 OK. Errors contains the problems described in the
+// response body, if it could be decoded.
+type ResponseError struct {
+	HTTPError *apis.HTTPError
+	Errors    []Error
+}
 
-	rateLimitRemainingHeader := "x-rate-limit-remaining"
-	rateLimitResetTimeHeader := "x-rate-limit-reset"
+// newResponseError decodes the Twitter errors from the body of the failed
+// response. The body is either an object with a list of errors or, for
+// requests that fail outright, a single error.
+func newResponseError(httpError *apis.HTTPError) *ResponseError {
+	responseError := &ResponseError{HTTPError: httpError}
 
-	rateLimitRemaining := response.Header.Get(rateLimitRemainingHeader)
-	if rateLimitRemaining == "" {
-		return headerNotFoundError(rateLimitRemainingHeader)
+	var wrapper struct {
+		Errors []Error `json:"errors"`
 	}
-	rateLimitResetTime := response.Header.Get(rateLimitResetTimeHeader)
-	if rateLimitResetTime == "" {
-		return headerNotFoundError(rateLimitResetTimeHeader)
+	if err := json.Unmarshal(httpError.Body, &wrapper); err != nil {
+		return responseError
+	}
+	if len(wrapper.Errors) > 0 {
+		responseError.Errors = wrapper.Errors
+		return responseError
 	}
 
-	remaining, err := strconv.ParseUint(rateLimitRemaining, 10, 64)
-	if err != nil {
-		return headerParseError(rateLimitRemainingHeader, err)
+	var single Error
+	if err := json.Unmarshal(httpError.Body, &single); err == nil && single.description() != "" {
+		responseError.Errors = []Error{single}
 	}
-	resetTime, err := strconv.ParseUint(rateLimitResetTime, 10, 64)
-	if err != nil {
-		return headerParseError(rateLimitResetTimeHeader, err)
+	return responseError
+}
+
+// Error describes the failed request and the first problem Twitter reported.
+func (e *ResponseError) Error() string {
+	if len(e.Errors) > 0 {
+		if description := e.Errors[0].description(); description != "" {
+			return fmt.Sprintf("%s: %s", e.HTTPError.Error(), description)
+		}
 	}
+	return e.HTTPError.Error()
+}
 
-	e.RemainingCalls = int64(remaining)
-	e.RateLimitReset = time.Unix(int64(resetTime), 0)
-	return nil
+// Unwrap returns the underlying HTTPError.
+func (e *ResponseError) Unwrap() error {
+	return e.HTTPError
+}
+
+// Endpoint represents a Twitter API endpoint. The rate limits of endpoints
+// are kept in the shared registry of the API that requests them, so that
+// every request to the same endpoint, as the same identity, counts against
+// the same limit.
+type Endpoint struct {
+	URL      string
+	Template string // Identifies the rate limit of the endpoint. Defaults to URL.
 }
 
+// rateLimitEndpoint returns the name of the rate limit of the endpoint.
+func (e *Endpoint) rateLimitEndpoint() string {
+	if e.Template != "" {
+		return e.Template
+	}
+	return e.URL
+}
+
+// rateLimitHeaders are the headers in which Twitter reports the rate limit of
+// the endpoint requested, which every successful response includes.
+var rateLimitHeaders = apis.RateLimitHeaders{Remaining: "x-rate-limit-remaining", Reset: "x-rate-limit-reset"}
+
 // PerformRequest is a helper function that requests a Twitter API URL on behalf of an endpoint.
-// This function checks the rate limit for the endpoint before requesting the given URL. Upon
-// a successful request, the endpoint is updated with the newly returned API rate limit information.
-func (e *Endpoint) PerformRequest(request *retryablehttp.Request, api *apis.API, body interface{}) error {
-	if e.RemainingCalls == 0 && time.Now().Before(e.RateLimitReset) {
-		return fmt.Errorf("could not perform request. rate limit reached for %s", request.URL)
+// This function acquires the rate limit of the endpoint before requesting the given URL, which
+// fails or waits according to the API's rate limit policy when the limit is exhausted. Upon a
+// successful request, the API's registry is updated with the newly returned rate limit information.
+// When the Twitter API responds with an error, the registry is still updated with any rate limit
+// information in the response, and a *ResponseError is returned.
+func (e *Endpoint) PerformRequest(ctx context.Context, request *retryablehttp.Request, api *apis.API, body interface{}) error {
+	endpoint := e.rateLimitEndpoint()
+	if err := api.AcquireRateLimit(ctx, endpoint); err != nil {
+		return fmt.Errorf("could not perform request to %s: %w", request.URL, err)
 	}
 
-	response, err := api.Do(request, body)
+	response, err := api.Do(ctx, request, body)
 	if err != nil {
+		var httpError *apis.HTTPError
+		if errors.As(err, &httpError) {
+			// Not every failed response carries rate limit information, so
+			// the registry is left unchanged when it can't be parsed.
+			if limit, ok := rateLimitHeaders.Parse(httpError.Header); ok {
+				api.UpdateRateLimit(ctx, endpoint, limit)
+			}
+			return newResponseError(httpError)
+		}
 		return err
 	}
 
-	err = e.parseRateLimitInfo(response)
-	if err != nil {
-		return err
+	limit, ok := rateLimitHeaders.Parse(response.Header)
+	if !ok {
+		return fmt.Errorf("response to %s did not report a valid rate limit in %s and %s",
+			request.URL, rateLimitHeaders.Remaining, rateLimitHeaders.Reset)
 	}
+	api.UpdateRateLimit(ctx, endpoint, limit)
 
 	return nil
 }
 
-// NewTwitterAPI creates an API configured to be used with Twitter.
+// NewTwitterAPI creates an API configured to be used with Twitter. Requests
+// are authenticated with an app-only bearer token, which is obtained from the
+// API key and secret unless a pre-generated token is given.
 func NewTwitterAPI(baseURL string, apiKey string, apiSecretKey string, apiBearerToken string) (*API, error) {
-	var beforeFuncs []apis.RequestFunc
+	auth := newTwitterAuth(apiKey, apiSecretKey, apiBearerToken)
 
-	auth := &twitterAuth{
-		APIKey:         apiKey,
-		APISecretKey:   apiSecretKey,
-		APIBearerToken: apiBearerToken,
+	twitterAPI, err := NewTwitterAPIWithAuth(baseURL, auth)
+	if err != nil {
+		return nil, err
 	}
 
+	auth.client = twitterAPI.Client.Client
+	auth.tokenURL = twitterAPI.Client.BaseURL.ResolveReference(&url.URL{Path: tokenPath}).String()
+
+	return twitterAPI, nil
+}
+
+// NewTwitterAPIWithAuth creates an API configured to be used with Twitter that
+// authenticates requests with the given Auth, such as an apis.OAuth1Auth for
+// endpoints that require user context.
+func NewTwitterAPIWithAuth(baseURL string, auth apis.Auth) (*API, error) {
+	var beforeFuncs []apis.RequestFunc
+
 	beforeFuncs = append(beforeFuncs, auth.Attach)
 
 	combinedFuncs := func(req *retryablehttp.Request) {
@@ -135,20 +199,94 @@ func NewTwitterAPI(baseURL string, apiKey string, apiSecretKey string, apiBearer
 	}
 
 	api, err := apis.NewAPI(baseURL, auth, combinedFuncs, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not create Twitter API: %w", err)
+	}
+	// Twitter doesn't send Retry-After, but reports when each rate limit
+	// resets, so that rate limited requests are retried once it does.
+	api.RetryPolicy.ResetHeader = "x-rate-limit-reset"
 
 	twitterAPI := &API{
 		Client:      api,
 		UserService: NewUserService(api),
 	}
 
+	return twitterAPI, nil
+}
+
+// GetUser looks up the user with the given username, with the given optional
+// fields, or all of them if fields is nil.
+func (a *API) GetUser(ctx context.Context, username string, fields []string) (*User, error) {
+	ctx, err := withFields(ctx, fields)
+	if err != nil {
+		return nil, err
+	}
+	return a.UserService.Show(ctx, username)
+}
+
+// GetUserByID is like GetUser, but looks up the user with the given ID.
+func (a *API) GetUserByID(ctx context.Context, id string, fields []string) (*User, error) {
+	ctx, err := withFields(ctx, fields)
 	if err != nil {
-		return nil, fmt.Errorf("could not create Twitter API: %w", err)
+		return nil, err
 	}
+	return a.UserService.ShowByID(ctx, id)
+}
 
-	return twitterAPI, nil
+// GetUsers looks up the users with the given usernames, with the given
+// optional fields, or all of them if fields is nil.
+func (a *API) GetUsers(ctx context.Context, usernames []string, fields []string) ([]User, []LookupError, error) {
+	ctx, err := withFields(ctx, fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a.UserService.ShowMany(ctx, usernames)
 }
 
-func (a *API) GetUser(username string) (*User, error) {
-	user, err := a.UserService.Show(username)
+// GetUsersByID is like GetUsers, but looks up the users with the given IDs.
+func (a *API) GetUsersByID(ctx context.Context, ids []string, fields []string) ([]User, []LookupError, error) {
+	ctx, err := withFields(ctx, fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a.UserService.ShowManyByID(ctx, ids)
+}
+
+func (a *API) GetAuthenticatedUser(ctx context.Context) (*User, error) {
+	user, err := a.UserService.Me(ctx)
 	return user, err
 }
+
+// GetFollowers lists the followers of the user with the given ID, with the
+// given optional fields, or all of them if fields is nil.
+func (a *API) GetFollowers(ctx context.Context, id string, fields []string) ([]User, error) {
+	ctx, err := withFields(ctx, fields)
+	if err != nil {
+		return nil, err
+	}
+	return a.UserService.Followers(ctx, id)
+}
+
+func (a *API) GetFollowersRateLimit() (int64, time.Time) {
+	limit, _ := a.Client.RateLimit(context.Background(), a.UserService.followersEndpoint.rateLimitEndpoint())
+	return limit.Remaining, limit.Reset
+}
+
+// GetFollowing lists the users that the user with the given ID follows, with
+// the given optional fields, or all of them if fields is nil.
+func (a *API) GetFollowing(ctx context.Context, id string, fields []string) ([]User, error) {
+	ctx, err := withFields(ctx, fields)
+	if err != nil {
+		return nil, err
+	}
+	return a.UserService.Following(ctx, id)
+}
+
+// withFields returns a copy of ctx with which the given optional fields are
+// requested, as by WithUserFields, or ctx itself if fields is nil.
+func withFields(ctx context.Context, fields []string) (context.Context, error) {
+	if fields == nil {
+		return ctx, nil
+	}
+	return WithUserFields(ctx, fields)
+}
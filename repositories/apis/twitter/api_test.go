@@ -1,7 +1,9 @@
 package twitter_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -9,6 +11,8 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
 	"github.com/jake-hansen/followrs/repositories/apis/twitter"
 	"github.com/stretchr/testify/assert"
 )
@@ -39,19 +43,16 @@ func TestPerformRequest(t *testing.T) {
 		defer server.Close()
 
 		StandardHandler(t, mux, "/test", nil)
-		endpoint := &twitter.Endpoint{
-			URL:            "/test",
-			RemainingCalls: 0,
-			RateLimitReset: time.Time{},
-		}
+		endpoint := &twitter.Endpoint{URL: "/test"}
 
-		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "")
+		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
 		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
-		err := endpoint.PerformRequest(req, api.Client, new(emptyBody))
+		err := endpoint.PerformRequest(context.Background(), req, api.Client, new(emptyBody))
 
 		assert.NoError(t, err)
-		assert.Equal(t, int64(100), endpoint.RemainingCalls)
-		assert.Equal(t, time.Unix(100, 0), endpoint.RateLimitReset)
+		limit, ok := api.Client.RateLimit(context.Background(), "/test")
+		assert.True(t, ok)
+		assert.Equal(t, apis.RateLimit{Remaining: 100, Reset: time.Unix(100, 0)}, limit)
 	})
 
 	t.Run("no-rate-limit-header-present", func(t *testing.T) {
@@ -59,11 +60,7 @@ func TestPerformRequest(t *testing.T) {
 
 		defer server.Close()
 
-		endpoint := &twitter.Endpoint{
-			URL:            "/test",
-			RemainingCalls: 0,
-			RateLimitReset: time.Time{},
-		}
+		endpoint := &twitter.Endpoint{URL: "/test"}
 
 		mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
@@ -72,12 +69,12 @@ func TestPerformRequest(t *testing.T) {
 			w.Write(bytes)
 		})
 
-		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "")
+		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
 		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
-		err := endpoint.PerformRequest(req, api.Client, new(emptyBody))
+		err := endpoint.PerformRequest(context.Background(), req, api.Client, new(emptyBody))
 
 		assert.Error(t, err)
-		assert.Equal(t, "header x-rate-limit-remaining not found in response", err.Error())
+		assert.Contains(t, err.Error(), "did not report a valid rate limit in x-rate-limit-remaining and x-rate-limit-reset")
 	})
 
 	t.Run("no-rate-limit-reset-header-present", func(t *testing.T) {
@@ -85,11 +82,7 @@ func TestPerformRequest(t *testing.T) {
 
 		defer server.Close()
 
-		endpoint := &twitter.Endpoint{
-			URL:            "/test",
-			RemainingCalls: 0,
-			RateLimitReset: time.Time{},
-		}
+		endpoint := &twitter.Endpoint{URL: "/test"}
 
 		mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
@@ -98,12 +91,12 @@ func TestPerformRequest(t *testing.T) {
 			w.Write(bytes)
 		})
 
-		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "")
+		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
 		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
-		err := endpoint.PerformRequest(req, api.Client, new(emptyBody))
+		err := endpoint.PerformRequest(context.Background(), req, api.Client, new(emptyBody))
 
 		assert.Error(t, err)
-		assert.Equal(t, "header x-rate-limit-reset not found in response", err.Error())
+		assert.Contains(t, err.Error(), "did not report a valid rate limit in x-rate-limit-remaining and x-rate-limit-reset")
 	})
 
 	t.Run("error-parsing-rate-limit-header", func(t *testing.T) {
@@ -111,11 +104,7 @@ func TestPerformRequest(t *testing.T) {
 
 		defer server.Close()
 
-		endpoint := &twitter.Endpoint{
-			URL:            "/test",
-			RemainingCalls: 0,
-			RateLimitReset: time.Time{},
-		}
+		endpoint := &twitter.Endpoint{URL: "/test"}
 
 		mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
@@ -125,12 +114,12 @@ func TestPerformRequest(t *testing.T) {
 			w.Write(bytes)
 		})
 
-		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "")
+		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
 		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
-		err := endpoint.PerformRequest(req, api.Client, new(emptyBody))
+		err := endpoint.PerformRequest(context.Background(), req, api.Client, new(emptyBody))
 
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "could not parse rate limit header x-rate-limit-remaining")
+		assert.Contains(t, err.Error(), "did not report a valid rate limit in x-rate-limit-remaining and x-rate-limit-reset")
 	})
 
 	t.Run("error-parsing-rate-limit-reset-header", func(t *testing.T) {
@@ -138,11 +127,7 @@ func TestPerformRequest(t *testing.T) {
 
 		defer server.Close()
 
-		endpoint := &twitter.Endpoint{
-			URL:            "/test",
-			RemainingCalls: 0,
-			RateLimitReset: time.Time{},
-		}
+		endpoint := &twitter.Endpoint{URL: "/test"}
 
 		mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
@@ -152,12 +137,12 @@ func TestPerformRequest(t *testing.T) {
 			w.Write(bytes)
 		})
 
-		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "")
+		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
 		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
-		err := endpoint.PerformRequest(req, api.Client, new(emptyBody))
+		err := endpoint.PerformRequest(context.Background(), req, api.Client, new(emptyBody))
 
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "could not parse rate limit header x-rate-limit-reset")
+		assert.Contains(t, err.Error(), "did not report a valid rate limit in x-rate-limit-remaining and x-rate-limit-reset")
 	})
 
 	t.Run("rate-limit-reached", func(t *testing.T) {
@@ -165,11 +150,7 @@ func TestPerformRequest(t *testing.T) {
 
 		defer server.Close()
 
-		endpoint := &twitter.Endpoint{
-			URL:            "/test",
-			RemainingCalls: 0,
-			RateLimitReset: time.Time{},
-		}
+		endpoint := &twitter.Endpoint{URL: "/test"}
 
 		mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
 			durationAddition, _ := time.ParseDuration("5s")
@@ -182,12 +163,168 @@ func TestPerformRequest(t *testing.T) {
 			w.Write(bytes)
 		})
 
-		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "")
+		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
 		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
-		err := endpoint.PerformRequest(req, api.Client, new(emptyBody))
+		err := endpoint.PerformRequest(context.Background(), req, api.Client, new(emptyBody))
 		assert.NoError(t, err)
-		err = endpoint.PerformRequest(req, api.Client, new(emptyBody))
+		err = endpoint.PerformRequest(context.Background(), req, api.Client, new(emptyBody))
 		assert.Error(t, err)
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+
+		var rateLimitError *apis.RateLimitError
+		assert.True(t, errors.As(err, &rateLimitError))
+		assert.True(t, rateLimitError.RetryAfter() > 0)
+	})
+
+	t.Run("rate-limit-shared-by-template", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		resetTime := time.Now().Add(time.Minute).Unix()
+		requests := 0
+		mux.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("x-rate-limit-remaining", "0")
+			w.Header().Set("x-rate-limit-reset", strconv.Itoa(int(resetTime)))
+			w.Write([]byte("{}"))
+		})
+
+		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+		first := &twitter.Endpoint{URL: "/users/1", Template: "GET /users/:id"}
+		second := &twitter.Endpoint{URL: "/users/2", Template: "GET /users/:id"}
+
+		req, _ := retryablehttp.NewRequest("GET", "/users/1", nil)
+		err := first.PerformRequest(context.Background(), req, api.Client, new(emptyBody))
+		assert.NoError(t, err)
+
+		req, _ = retryablehttp.NewRequest("GET", "/users/2", nil)
+		err = second.PerformRequest(context.Background(), req, api.Client, new(emptyBody))
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("rate-limit-waited-out", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		endpoint := &twitter.Endpoint{URL: "/test"}
+		StandardHandler(t, mux, "/test", nil)
+
+		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+		api.Client.UpdateRateLimit(context.Background(), "/test", apis.RateLimit{
+			Remaining: 0,
+			Reset:     time.Now().Add(50 * time.Millisecond),
+		})
+
+		ctx := apis.WithRateLimitPolicy(context.Background(), apis.WaitForReset)
+		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+		err := endpoint.PerformRequest(ctx, req, api.Client, new(emptyBody))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("error-response", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		endpoint := &twitter.Endpoint{URL: "/test"}
+		resetTime := time.Now().Add(time.Minute).Unix()
+
+		mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.Header().Set("x-rate-limit-remaining", "0")
+			w.Header().Set("x-rate-limit-reset", strconv.Itoa(int(resetTime)))
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"title":"Too Many Requests","detail":"Too Many Requests","type":"about:blank","status":429}`))
+		})
+
+		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+		api.Client.Client.RetryMax = 0
+		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+		err := endpoint.PerformRequest(context.Background(), req, api.Client, new(emptyBody))
+
+		var responseError *twitter.ResponseError
+		assert.True(t, errors.As(err, &responseError))
+		assert.Equal(t, http.StatusTooManyRequests, responseError.HTTPError.StatusCode)
+		assert.Equal(t, []twitter.Error{{
+			Title:  "Too Many Requests",
+			Detail: "Too Many Requests",
+			Type:   "about:blank",
+			Status: http.StatusTooManyRequests,
+		}}, responseError.Errors)
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+		limit, ok := api.Client.RateLimit(context.Background(), "/test")
+		assert.True(t, ok)
+		assert.Equal(t, apis.RateLimit{Remaining: 0, Reset: time.Unix(resetTime, 0)}, limit)
+	})
+
+	t.Run("error-response-with-error-list", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		endpoint := &twitter.Endpoint{URL: "/test"}
+
+		mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"errors":[{"code":89,"message":"Invalid or expired token."}]}`))
+		})
+
+		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+		err := endpoint.PerformRequest(context.Background(), req, api.Client, new(emptyBody))
+
+		var responseError *twitter.ResponseError
+		assert.True(t, errors.As(err, &responseError))
+		assert.Equal(t, []twitter.Error{{Code: 89, Message: "Invalid or expired token."}}, responseError.Errors)
+		assert.True(t, errors.Is(err, apperrors.ErrUnauthorized))
+		assert.Contains(t, err.Error(), "Invalid or expired token.")
 	})
 
+	t.Run("request-timeout", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		endpoint := &twitter.Endpoint{URL: "/test"}
+
+		mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		})
+
+		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+		api.Client.Timeout = 10 * time.Millisecond
+		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+		err := endpoint.PerformRequest(context.Background(), req, api.Client, new(emptyBody))
+
+		assert.Error(t, err)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("request-cancelled", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		endpoint := &twitter.Endpoint{URL: "/test"}
+		StandardHandler(t, mux, "/test", nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+		err := endpoint.PerformRequest(ctx, req, api.Client, new(emptyBody))
+
+		assert.Error(t, err)
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
 }
@@ -0,0 +1,175 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+)
+
+// tokenPath is the path, relative to the host of the Twitter API, of the
+// endpoint that exchanges an API key and secret for a bearer token.
+const tokenPath = "/oauth2/token"
+
+// maxTokenResponseSize is the largest token response that is read.
+const maxTokenResponseSize = 1 << 16
+
+// twitterAuth contains the API keys needed to authenticate to the
+// Twitter API. Requests are authenticated with an app-only bearer token,
+// which is obtained from the API key and secret using the OAuth 2.0 client
+// credentials flow. A pre-generated bearer token can be used instead, in which
+// case the API key and secret are only needed if that token is invalidated.
+type twitterAuth struct {
+	APIKey         string
+	APISecretKey   string
+	APIBearerToken string
+
+	client   *retryablehttp.Client
+	tokenURL string
+
+	mu    sync.Mutex
+	token string
+}
+
+// tokenResponse is the response to a bearer token request.
+type tokenResponse struct {
+	TokenType   string `json:"token_type"`
+	AccessToken string `json:"access_token"`
+}
+
+// newTwitterAuth creates a twitterAuth that starts out with the given bearer
+// token, which may be empty.
+func newTwitterAuth(apiKey string, apiSecretKey string, apiBearerToken string) *twitterAuth {
+	return &twitterAuth{
+		APIKey:         apiKey,
+		APISecretKey:   apiSecretKey,
+		APIBearerToken: apiBearerToken,
+		token:          apiBearerToken,
+	}
+}
+
+// IsAuthenticated determines if a bearer token is available.
+func (a *twitterAuth) IsAuthenticated() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.token != ""
+}
+
+// Authenticate obtains a bearer token from the API key and secret, unless a
+// bearer token is already available.
+func (a *twitterAuth) Authenticate(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" {
+		return nil
+	}
+	if !a.canObtainToken() {
+		return fmt.Errorf("no bearer token or API key and secret configured: %w", apperrors.ErrUnauthorized)
+	}
+
+	token, err := a.requestToken(ctx)
+	if err != nil {
+		return fmt.Errorf("could not obtain bearer token: %w", err)
+	}
+	a.token = token
+	return nil
+}
+
+// Invalidate discards the bearer token so that a new one is obtained by the
+// next call to Authenticate. The bearer token is kept if a new one can't be
+// obtained because no API key and secret are configured.
+func (a *twitterAuth) Invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.canObtainToken() {
+		a.token = ""
+	}
+}
+
+// Attach attaches the bearer token to a request.
+func (a *twitterAuth) Attach(req *retryablehttp.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.token))
+}
+
+// canObtainToken determines if the API key and secret are configured.
+func (a *twitterAuth) canObtainToken() bool {
+	return a.APIKey != "" && a.APISecretKey != "" && a.client != nil
+}
+
+// requestToken exchanges the API key and secret for a bearer token.
+func (a *twitterAuth) requestToken(ctx context.Context) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+
+	var token tokenResponse
+	err := postTokenRequest(ctx, a.client, a.tokenURL, form, url.QueryEscape(a.APIKey), url.QueryEscape(a.APISecretKey), &token)
+	if err != nil {
+		return "", err
+	}
+	if !strings.EqualFold(token.TokenType, "bearer") || token.AccessToken == "" {
+		return "", errors.New("token response did not contain a bearer token")
+	}
+
+	return token.AccessToken, nil
+}
+
+// postTokenRequest posts the form to the token endpoint at tokenURL and
+// decodes the response into v. The request is authenticated with HTTP basic
+// authentication if a username is given.
+func postTokenRequest(ctx context.Context, client *retryablehttp.Client, tokenURL string, form url.Values, username string, password string, v interface{}) error {
+	req, err := retryablehttp.NewRequest(http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=UTF-8")
+
+	response, err := client.Do(req)
+	if err != nil {
+		if response != nil {
+			response.Body.Close()
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("%w: %s", apperrors.ErrUpstreamUnavailable, err.Error())
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, maxTokenResponseSize))
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return &apis.HTTPError{
+			URL:        tokenURL,
+			StatusCode: response.StatusCode,
+			Header:     response.Header,
+			Body:       body,
+		}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("could not decode body: %w", err)
+	}
+	return nil
+}
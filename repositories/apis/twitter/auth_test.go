@@ -0,0 +1,173 @@
+package twitter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+	"github.com/jake-hansen/followrs/repositories/apis/twitter"
+	"github.com/stretchr/testify/assert"
+)
+
+// TokenHandler handles bearer token requests, issuing the given tokens in
+// order. It returns a pointer to the number of tokens issued.
+func TokenHandler(t *testing.T, mux *http.ServeMux, tokens ...string) *int {
+	issued := 0
+	mux.HandleFunc("/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		key, secret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "key", key)
+		assert.Equal(t, "secret", secret)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "client_credentials", r.FormValue("grant_type"))
+
+		w.Header().Set("Content-Type", "application/json")
+		bytes, _ := json.Marshal(map[string]string{
+			"token_type":   "bearer",
+			"access_token": tokens[issued],
+		})
+		issued++
+		w.Write(bytes)
+	})
+	return &issued
+}
+
+// AuthorizedHandler is like StandardHandler, but responds with 401 Unauthorized
+// unless the request carries the given bearer token.
+func AuthorizedHandler(t *testing.T, mux *http.ServeMux, endpoint string, token string) *int {
+	requests := 0
+	mux.HandleFunc(endpoint, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer %s", token) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-rate-limit-remaining", "100")
+		w.Header().Set("x-rate-limit-reset", "100")
+		w.Write([]byte("{}"))
+	})
+	return &requests
+}
+
+// TestTwitterAuth tests authenticating to the Twitter API.
+func TestTwitterAuth(t *testing.T) {
+	t.Run("token-obtained-from-key-and-secret", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		issued := TokenHandler(t, mux, "token")
+		AuthorizedHandler(t, mux, "/test", "token")
+
+		api, _ := twitter.NewTwitterAPI(server.URL, "key", "secret", "")
+		endpoint := &twitter.Endpoint{URL: "/test"}
+		for i := 0; i < 2; i++ {
+			req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+			err := endpoint.PerformRequest(context.Background(), req, api.Client, new(emptyBody))
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, 1, *issued)
+	})
+
+	t.Run("token-obtained-again-after-invalidation", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		issued := TokenHandler(t, mux, "new")
+		requests := AuthorizedHandler(t, mux, "/test", "new")
+
+		api, _ := twitter.NewTwitterAPI(server.URL, "key", "secret", "expired")
+		endpoint := &twitter.Endpoint{URL: "/test"}
+		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+		err := endpoint.PerformRequest(context.Background(), req, api.Client, new(emptyBody))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, *issued)
+		assert.Equal(t, 2, *requests)
+	})
+
+	t.Run("pre-generated-token-rejected", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		requests := AuthorizedHandler(t, mux, "/test", "valid")
+
+		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "expired")
+		endpoint := &twitter.Endpoint{URL: "/test"}
+		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+		err := endpoint.PerformRequest(context.Background(), req, api.Client, new(emptyBody))
+
+		assert.True(t, errors.Is(err, apperrors.ErrUnauthorized))
+		assert.Equal(t, 1, *requests)
+	})
+
+	t.Run("token-request-rejected", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		mux.HandleFunc("/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		})
+		requests := AuthorizedHandler(t, mux, "/test", "token")
+
+		api, _ := twitter.NewTwitterAPI(server.URL, "key", "secret", "")
+		endpoint := &twitter.Endpoint{URL: "/test"}
+		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+		err := endpoint.PerformRequest(context.Background(), req, api.Client, new(emptyBody))
+
+		assert.True(t, errors.Is(err, apperrors.ErrUnauthorized))
+		assert.Equal(t, 0, *requests)
+	})
+
+	t.Run("no-credentials", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		requests := AuthorizedHandler(t, mux, "/test", "token")
+
+		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "")
+		endpoint := &twitter.Endpoint{URL: "/test"}
+		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+		err := endpoint.PerformRequest(context.Background(), req, api.Client, new(emptyBody))
+
+		assert.True(t, errors.Is(err, apperrors.ErrUnauthorized))
+		assert.Equal(t, 0, *requests)
+	})
+}
+
+// TestNewTwitterAPIWithAuth tests creating an API with a user context Auth.
+func TestNewTwitterAPIWithAuth(t *testing.T) {
+	mux, server := NewTestServer()
+
+	defer server.Close()
+
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "OAuth "))
+		assert.Contains(t, r.Header.Get("Authorization"), `oauth_token="token"`)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-rate-limit-remaining", "100")
+		w.Header().Set("x-rate-limit-reset", "100")
+		w.Write([]byte("{}"))
+	})
+
+	api, err := twitter.NewTwitterAPIWithAuth(server.URL, apis.NewOAuth1Auth("key", "secret", "token", "token-secret"))
+	assert.NoError(t, err)
+
+	endpoint := &twitter.Endpoint{URL: "/test"}
+	req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+	err = endpoint.PerformRequest(context.Background(), req, api.Client, new(emptyBody))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,277 @@
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+)
+
+// authorizeURL is the page that users are sent to in order to authorize an
+// application to access their Twitter account.
+const authorizeURL = "https://twitter.com/i/oauth2/authorize"
+
+// userTokenPath is the path, relative to the base URL of the Twitter API, of
+// the endpoint that issues and refreshes user access tokens.
+const userTokenPath = "/oauth2/token"
+
+// expiryDelta is how long before its expiry an access token is refreshed, so
+// that it doesn't expire while a request is in flight.
+const expiryDelta = 30 * time.Second
+
+// Token is an OAuth 2.0 token that grants access to the Twitter API on behalf
+// of a user.
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	Expiry       time.Time // Zero if the access token doesn't expire.
+	Scopes       []string
+}
+
+// userTokenResponse is the response to a user access token request.
+type userTokenResponse struct {
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	Scope        string `json:"scope"`
+}
+
+// OAuth2Client performs Twitter's OAuth 2.0 authorization code flow with PKCE,
+// which lets users connect their Twitter account, and authenticates requests
+// on behalf of connected users.
+type OAuth2Client struct {
+	ClientID     string
+	ClientSecret string // Empty for public clients.
+	RedirectURL  string
+	Scopes       []string
+	AuthorizeURL string
+	TokenURL     string
+
+	client *retryablehttp.Client
+	now    func() time.Time
+
+	mu    sync.Mutex
+	auths map[string]*userAuth
+}
+
+// NewOAuth2Client creates an OAuth2Client that requests tokens from the
+// Twitter API that api is configured for. Token requests are authenticated
+// with the client ID and secret alone, so they aren't signed by api's Auth
+// even if it signs every attempt, as OAuth 1.0a user context does.
+func NewOAuth2Client(api *API, clientID string, clientSecret string, redirectURL string, scopes []string) *OAuth2Client {
+	return &OAuth2Client{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       scopes,
+		AuthorizeURL: authorizeURL,
+		TokenURL:     fmt.Sprintf("%s%s", api.Client.BaseURL, userTokenPath),
+		client:       api.Client.UnauthenticatedClient(),
+		now:          time.Now,
+		auths:        make(map[string]*userAuth),
+	}
+}
+
+// AuthorizationURL returns the URL of the page where the user authorizes
+// followrs to access their account. The given state is returned to the
+// redirect URL, and codeChallenge is the S256 challenge derived from the code
+// verifier that is later passed to Exchange.
+func (o *OAuth2Client) AuthorizationURL(state string, codeChallenge string) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", o.ClientID)
+	query.Set("redirect_uri", o.RedirectURL)
+	query.Set("scope", strings.Join(o.Scopes, " "))
+	query.Set("state", state)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	return fmt.Sprintf("%s?%s", o.AuthorizeURL, query.Encode())
+}
+
+// Exchange exchanges an authorization code, and the code verifier its
+// authorization was requested with, for a Token.
+func (o *OAuth2Client) Exchange(ctx context.Context, code string, codeVerifier string) (*Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", o.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+
+	token, err := o.requestToken(ctx, form)
+	if err != nil {
+		return nil, fmt.Errorf("could not exchange authorization code: %w", err)
+	}
+	return token, nil
+}
+
+// Refresh obtains a new Token using a refresh token.
+func (o *OAuth2Client) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+
+	token, err := o.requestToken(ctx, form)
+	if err != nil {
+		return nil, fmt.Errorf("could not refresh access token: %w", err)
+	}
+	return token, nil
+}
+
+// WithToken returns a copy of ctx whose requests to the Twitter API are
+// authenticated with the given Token. The Token is not refreshed.
+func (o *OAuth2Client) WithToken(ctx context.Context, token Token) context.Context {
+	return apis.WithAuth(ctx, &userAuth{client: o, token: token})
+}
+
+// WithUser returns a copy of ctx whose requests to the Twitter API are made on
+// behalf of the user with the given ID, using the given Token. The Token is
+// refreshed when it expires or is rejected, and onRefresh, which may be nil,
+// is called with every new Token. Requests made on behalf of the same user
+// share the Token, so the most recently obtained Token should be given.
+func (o *OAuth2Client) WithUser(ctx context.Context, userID string, token Token, onRefresh func(Token)) context.Context {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	auth, ok := o.auths[userID]
+	if !ok || auth.replacedBy(token) {
+		auth = &userAuth{client: o, userID: userID, token: token}
+		o.auths[userID] = auth
+	}
+	auth.setOnRefresh(onRefresh)
+
+	return apis.WithAuth(ctx, auth)
+}
+
+// requestToken posts the form to the token endpoint. Confidential clients
+// authenticate with their client secret, while public clients only identify
+// themselves.
+func (o *OAuth2Client) requestToken(ctx context.Context, form url.Values) (*Token, error) {
+	form.Set("client_id", o.ClientID)
+
+	username, password := "", ""
+	if o.ClientSecret != "" {
+		username, password = url.QueryEscape(o.ClientID), url.QueryEscape(o.ClientSecret)
+	}
+
+	var response userTokenResponse
+	if err := postTokenRequest(ctx, o.client, o.TokenURL, form, username, password, &response); err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(response.TokenType, "bearer") || response.AccessToken == "" {
+		return nil, fmt.Errorf("token response did not contain a bearer token: %w", apperrors.ErrUnauthorized)
+	}
+
+	token := &Token{
+		AccessToken:  response.AccessToken,
+		RefreshToken: response.RefreshToken,
+		Scopes:       strings.Fields(response.Scope),
+	}
+	if response.ExpiresIn > 0 {
+		token.Expiry = o.now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// userAuth authenticates requests on behalf of a user with an OAuth 2.0
+// access token, refreshing it when it expires or is rejected.
+type userAuth struct {
+	client *OAuth2Client
+	userID string // Empty if the user is not yet known.
+
+	mu        sync.Mutex
+	token     Token
+	onRefresh func(Token)
+}
+
+// IsAuthenticated determines if the access token is present and unexpired.
+func (a *userAuth) IsAuthenticated() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.valid()
+}
+
+// Authenticate refreshes the access token, unless it is still valid.
+func (a *userAuth) Authenticate(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.valid() {
+		return nil
+	}
+	if a.token.RefreshToken == "" {
+		return fmt.Errorf("access token expired and cannot be refreshed: %w", apperrors.ErrUnauthorized)
+	}
+
+	token, err := a.client.Refresh(ctx, a.token.RefreshToken)
+	if err != nil {
+		return err
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = a.token.RefreshToken
+	}
+	a.token = *token
+
+	if a.onRefresh != nil {
+		a.onRefresh(a.token)
+	}
+	return nil
+}
+
+// Invalidate discards the access token so that it is refreshed by the next
+// call to Authenticate. The access token is kept if it can't be refreshed.
+func (a *userAuth) Invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token.RefreshToken != "" {
+		a.token.AccessToken = ""
+	}
+}
+
+// Identity identifies the user that requests are made on behalf of.
+func (a *userAuth) Identity() string {
+	return "user:" + a.userID
+}
+
+// Attach attaches the access token to a request.
+func (a *userAuth) Attach(req *retryablehttp.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.token.AccessToken))
+}
+
+// valid determines if the access token is present and unexpired. The caller
+// must hold a.mu.
+func (a *userAuth) valid() bool {
+	if a.token.AccessToken == "" {
+		return false
+	}
+	return a.token.Expiry.IsZero() || a.client.now().Before(a.token.Expiry.Add(-expiryDelta))
+}
+
+// replacedBy determines if the given Token was obtained after the userAuth's
+// Token, such as when the user connects their account again.
+func (a *userAuth) replacedBy(token Token) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return token.AccessToken != a.token.AccessToken && token.Expiry.After(a.token.Expiry)
+}
+
+// setOnRefresh sets the function that is called with every new Token.
+func (a *userAuth) setOnRefresh(onRefresh func(Token)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.onRefresh = onRefresh
+}
@@ -0,0 +1,195 @@
+package twitter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+	"github.com/jake-hansen/followrs/repositories/apis/twitter"
+	"github.com/stretchr/testify/assert"
+)
+
+// UserTokenHandler handles user access token requests, responding to each
+// with the result of respond.
+func UserTokenHandler(t *testing.T, mux *http.ServeMux, respond func(form url.Values) map[string]interface{}) {
+	mux.HandleFunc("/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "client", clientID)
+		assert.Equal(t, "secret", clientSecret)
+		r.ParseForm()
+
+		w.Header().Set("Content-Type", "application/json")
+		bytes, _ := json.Marshal(respond(r.PostForm))
+		w.Write(bytes)
+	})
+}
+
+func newOAuth2Client(serverURL string) *twitter.OAuth2Client {
+	api, _ := twitter.NewTwitterAPI(serverURL, "", "", "app")
+	return twitter.NewOAuth2Client(api, "client", "secret", "http://localhost/callback", []string{"users.read", "offline.access"})
+}
+
+// TestOAuth2Client_AuthorizationURL tests the URL users are sent to in order to
+// authorize a connection.
+func TestOAuth2Client_AuthorizationURL(t *testing.T) {
+	client := newOAuth2Client("http://localhost")
+
+	authorizationURL, err := url.Parse(client.AuthorizationURL("state", "challenge"))
+	assert.NoError(t, err)
+
+	query := authorizationURL.Query()
+	assert.Equal(t, "twitter.com", authorizationURL.Host)
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "client", query.Get("client_id"))
+	assert.Equal(t, "http://localhost/callback", query.Get("redirect_uri"))
+	assert.Equal(t, "users.read offline.access", query.Get("scope"))
+	assert.Equal(t, "state", query.Get("state"))
+	assert.Equal(t, "challenge", query.Get("code_challenge"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+}
+
+// TestOAuth2Client_Exchange tests exchanging an authorization code for a token.
+func TestOAuth2Client_Exchange(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		UserTokenHandler(t, mux, func(form url.Values) map[string]interface{} {
+			assert.Equal(t, "authorization_code", form.Get("grant_type"))
+			assert.Equal(t, "code", form.Get("code"))
+			assert.Equal(t, "verifier", form.Get("code_verifier"))
+			assert.Equal(t, "http://localhost/callback", form.Get("redirect_uri"))
+			return map[string]interface{}{
+				"token_type":    "bearer",
+				"expires_in":    7200,
+				"access_token":  "access",
+				"refresh_token": "refresh",
+				"scope":         "users.read offline.access",
+			}
+		})
+
+		token, err := newOAuth2Client(server.URL).Exchange(context.Background(), "code", "verifier")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "access", token.AccessToken)
+		assert.Equal(t, "refresh", token.RefreshToken)
+		assert.Equal(t, []string{"users.read", "offline.access"}, token.Scopes)
+		assert.WithinDuration(t, time.Now().Add(2*time.Hour), token.Expiry, time.Minute)
+	})
+
+	t.Run("code-rejected", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		mux.HandleFunc("/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+
+		token, err := newOAuth2Client(server.URL).Exchange(context.Background(), "code", "verifier")
+
+		assert.Nil(t, token)
+		assert.True(t, errors.Is(err, apperrors.ErrUnauthorized))
+	})
+
+	t.Run("api-with-user-auth", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		UserTokenHandler(t, mux, func(form url.Values) map[string]interface{} {
+			return map[string]interface{}{
+				"token_type":   "bearer",
+				"expires_in":   7200,
+				"access_token": "access",
+			}
+		})
+
+		api, err := twitter.NewTwitterAPIWithAuth(server.URL, apis.NewOAuth1Auth("key", "secret", "token", "token-secret"))
+		assert.NoError(t, err)
+		client := twitter.NewOAuth2Client(api, "client", "secret", "http://localhost/callback", []string{"users.read"})
+
+		token, err := client.Exchange(context.Background(), "code", "verifier")
+		assert.NoError(t, err)
+		assert.Equal(t, "access", token.AccessToken)
+	})
+}
+
+// TestOAuth2Client_WithUser tests making requests on behalf of a user.
+func TestOAuth2Client_WithUser(t *testing.T) {
+	t.Run("expired-token-refreshed", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		UserTokenHandler(t, mux, func(form url.Values) map[string]interface{} {
+			assert.Equal(t, "refresh_token", form.Get("grant_type"))
+			assert.Equal(t, "refresh", form.Get("refresh_token"))
+			return map[string]interface{}{
+				"token_type":    "bearer",
+				"expires_in":    7200,
+				"access_token":  "refreshed",
+				"refresh_token": "next",
+			}
+		})
+		requests := AuthorizedHandler(t, mux, "/test", "refreshed")
+
+		var refreshed []twitter.Token
+		client := newOAuth2Client(server.URL)
+		token := twitter.Token{AccessToken: "expired", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Minute)}
+		ctx := client.WithUser(context.Background(), "1", token, func(token twitter.Token) {
+			refreshed = append(refreshed, token)
+		})
+
+		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "app")
+		endpoint := &twitter.Endpoint{URL: "/test"}
+		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+		err := endpoint.PerformRequest(ctx, req, api.Client, new(emptyBody))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, *requests)
+		assert.Len(t, refreshed, 1)
+		assert.Equal(t, "refreshed", refreshed[0].AccessToken)
+		assert.Equal(t, "next", refreshed[0].RefreshToken)
+	})
+
+	t.Run("rejected-token-refreshed", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		UserTokenHandler(t, mux, func(form url.Values) map[string]interface{} {
+			return map[string]interface{}{
+				"token_type":   "bearer",
+				"access_token": "refreshed",
+			}
+		})
+		requests := AuthorizedHandler(t, mux, "/test", "refreshed")
+
+		var refreshed []twitter.Token
+		client := newOAuth2Client(server.URL)
+		token := twitter.Token{AccessToken: "revoked", RefreshToken: "refresh"}
+		ctx := client.WithUser(context.Background(), "1", token, func(token twitter.Token) {
+			refreshed = append(refreshed, token)
+		})
+
+		api, _ := twitter.NewTwitterAPI(server.URL, "", "", "app")
+		endpoint := &twitter.Endpoint{URL: "/test"}
+		req, _ := retryablehttp.NewRequest("GET", "/test", nil)
+		err := endpoint.PerformRequest(ctx, req, api.Client, new(emptyBody))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, *requests)
+		assert.Len(t, refreshed, 1)
+		assert.Equal(t, "refresh", refreshed[0].RefreshToken)
+	})
+}
@@ -1,71 +1,339 @@
 package twitter
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/jake-hansen/followrs/apperrors"
 	"github.com/jake-hansen/followrs/repositories/apis"
 
 	"github.com/hashicorp/go-retryablehttp"
 )
 
-// User represents a Twitter user.
+// User represents a Twitter user. Fields other than ID, Name and Username are
+// only present if they were requested, as by WithUserFields.
 type User struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Username string `json:"username"`
+	ID              string         `json:"id"`
+	Name            string         `json:"name"`
+	Username        string         `json:"username"`
+	CreatedAt       time.Time      `json:"created_at"`
+	Description     string         `json:"description"`
+	Location        string         `json:"location"`
+	ProfileImageURL string         `json:"profile_image_url"`
+	Protected       bool           `json:"protected"`
+	PublicMetrics   *PublicMetrics `json:"public_metrics"`
+	URL             string         `json:"url"`
+	Verified        bool           `json:"verified"`
+}
+
+// PublicMetrics contains the public activity counts of a Twitter user.
+type PublicMetrics struct {
+	FollowersCount int64 `json:"followers_count"`
+	FollowingCount int64 `json:"following_count"`
+	TweetCount     int64 `json:"tweet_count"`
+	ListedCount    int64 `json:"listed_count"`
+}
+
+// UserFields are the optional fields of a User that can be requested. All of
+// them are requested unless others are selected with WithUserFields.
+var UserFields = []string{
+	"created_at",
+	"description",
+	"location",
+	"profile_image_url",
+	"protected",
+	"public_metrics",
+	"url",
+	"verified",
+}
+
+// userFieldsContextKey is the key of the user fields carried by a context.
+type userFieldsContextKey struct{}
+
+// WithUserFields returns a copy of ctx carrying the given fields, which are
+// the optional fields requested for the Users looked up with it. It fails if
+// any of the fields is not one of UserFields.
+func WithUserFields(ctx context.Context, fields []string) (context.Context, error) {
+	for _, field := range fields {
+		if !IsUserField(field) {
+			return nil, fmt.Errorf("unknown user field %q", field)
+		}
+	}
+	return context.WithValue(ctx, userFieldsContextKey{}, fields), nil
+}
+
+// IsUserField determines if the given field is one of UserFields.
+func IsUserField(field string) bool {
+	for _, userField := range UserFields {
+		if field == userField {
+			return true
+		}
+	}
+	return false
+}
+
+// setUserFields sets the user.fields query parameter to the fields requested
+// for the Users looked up with the given context.
+func setUserFields(ctx context.Context, query url.Values) {
+	fields, ok := ctx.Value(userFieldsContextKey{}).([]string)
+	if !ok {
+		fields = UserFields
+	}
+	if len(fields) > 0 {
+		query.Set("user.fields", strings.Join(fields, ","))
+	}
+}
+
+// usernamePattern matches the usernames that Twitter allows.
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`)
+
+// maxFollowsResults is the largest page size the Twitter API allows when
+// listing the follows of a user.
+const maxFollowsResults = 1000
+
+// maxLookupUsers is the most users the Twitter API allows to be looked up by
+// a single request.
+const maxLookupUsers = 100
+
+// LookupError describes why a single user could not be returned by a lookup
+// of several users.
+type LookupError struct {
+	Value string // Username or ID that was looked up.
+	Err   error  // Wraps the matching error from apperrors, if any.
+}
+
+// Error describes the user that could not be looked up.
+func (e *LookupError) Error() string {
+	return fmt.Sprintf("could not look up user %s: %s", e.Value, e.Err.Error())
+}
+
+// Unwrap returns the reason the user could not be looked up.
+func (e *LookupError) Unwrap() error {
+	return e.Err
 }
 
 // UserService provides methods for accessing Twitter users via the API.
 type UserService struct {
-	baseURL            string
-	twitterAPI         *apis.API
-	userLookupEndpoint *Endpoint
+	baseURL               string
+	twitterAPI            *apis.API
+	userLookupEndpoint    *Endpoint
+	userIDLookupEndpoint  *Endpoint
+	usersLookupEndpoint   *Endpoint
+	usersIDLookupEndpoint *Endpoint
+	meEndpoint            *Endpoint
+	followersEndpoint     *Endpoint
+	followingEndpoint     *Endpoint
 }
 
 // NewUserService creates a UserService with the default configuration.
 func NewUserService(api *apis.API) *UserService {
 	return &UserService{
-		baseURL:            "/users",
-		twitterAPI:         api,
-		userLookupEndpoint: newUserLookupEndpoint(),
+		baseURL:               "/users",
+		twitterAPI:            api,
+		userLookupEndpoint:    newUserLookupEndpoint(),
+		userIDLookupEndpoint:  newUserIDLookupEndpoint(),
+		usersLookupEndpoint:   newUsersLookupEndpoint(),
+		usersIDLookupEndpoint: newUsersIDLookupEndpoint(),
+		meEndpoint:            newMeEndpoint(),
+		followersEndpoint:     newFollowersEndpoint(),
+		followingEndpoint:     newFollowingEndpoint(),
 	}
 }
 
 func newUserLookupEndpoint() *Endpoint {
 	return &Endpoint{
-		URL: "/by/username/",
+		URL:      "/by/username/",
+		Template: "GET /users/by/username/:username",
 	}
 }
 
+func newUserIDLookupEndpoint() *Endpoint {
+	return &Endpoint{
+		URL:      "/",
+		Template: "GET /users/:id",
+	}
+}
+
+func newUsersLookupEndpoint() *Endpoint {
+	return &Endpoint{
+		URL:      "/by",
+		Template: "GET /users/by",
+	}
+}
+
+func newUsersIDLookupEndpoint() *Endpoint {
+	return &Endpoint{
+		URL:      "",
+		Template: "GET /users",
+	}
+}
+
+func newMeEndpoint() *Endpoint {
+	return &Endpoint{
+		URL:      "/me",
+		Template: "GET /users/me",
+	}
+}
+
+func newFollowersEndpoint() *Endpoint {
+	return &Endpoint{
+		URL:      "/followers",
+		Template: "GET /users/:id/followers",
+	}
+}
+
+func newFollowingEndpoint() *Endpoint {
+	return &Endpoint{
+		URL:      "/following",
+		Template: "GET /users/:id/following",
+	}
+}
+
+// parseError converts the first error in the response, if any, into an error
+// wrapping the matching error from apperrors.
 func (u *UserService) parseError(wrapper *DataWrapper) error {
 	if wrapper.Errors != nil {
 		apiErrors := *wrapper.Errors
 		if len(apiErrors) > 0 {
-			firstError := apiErrors[0]
-			if firstError.Title == "Not Found Error" {
-				return errors.New("user not found")
-			}
-			return errors.New("unknown error from Twitter API")
+			return userError(apiErrors[0])
 		}
 	}
 
 	return nil
 }
 
+// userError converts an error that the Twitter API reported about a user into
+// an error wrapping the matching error from apperrors.
+func userError(apiError Error) error {
+	switch {
+	case strings.Contains(apiError.Detail, "suspended"):
+		return fmt.Errorf("user %w", apperrors.ErrSuspended)
+	case apiError.Title == "Not Found Error":
+		return fmt.Errorf("user %w", apperrors.ErrNotFound)
+	case apiError.Title == "Authorization Error":
+		return fmt.Errorf("user %w", apperrors.ErrProtected)
+	}
+	return fmt.Errorf("user could not be retrieved: %w", apiError)
+}
+
 // Show returns the requested User.
-func (u *UserService) Show(username string) (*User, error) {
+func (u *UserService) Show(ctx context.Context, username string) (*User, error) {
+	if !usernamePattern.MatchString(username) {
+		return nil, fmt.Errorf("%w: %s", apperrors.ErrInvalidUsername, username)
+	}
+	return u.show(ctx, username, u.userLookupEndpoint)
+}
+
+// ShowByID returns the User with the given ID.
+func (u *UserService) ShowByID(ctx context.Context, id string) (*User, error) {
+	return u.show(ctx, id, u.userIDLookupEndpoint)
+}
+
+// ShowMany returns the Users with the given usernames, looking up at most 100
+// per request. A username that can't be looked up, such as because the user
+// doesn't exist, doesn't fail the lookup but is instead described by one of
+// the returned LookupErrors.
+func (u *UserService) ShowMany(ctx context.Context, usernames []string) ([]User, []LookupError, error) {
+	var valid []string
+	var lookupErrors []LookupError
+	for _, username := range usernames {
+		if !usernamePattern.MatchString(username) {
+			lookupErrors = append(lookupErrors, LookupError{
+				Value: username,
+				Err:   fmt.Errorf("%w: %s", apperrors.ErrInvalidUsername, username),
+			})
+			continue
+		}
+		valid = append(valid, username)
+	}
+
+	users, failed, err := u.showMany(ctx, "usernames", valid, u.usersLookupEndpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	return users, append(lookupErrors, failed...), nil
+}
+
+// ShowManyByID is like ShowMany, but looks up Users by their IDs.
+func (u *UserService) ShowManyByID(ctx context.Context, ids []string) ([]User, []LookupError, error) {
+	return u.showMany(ctx, "ids", ids, u.usersIDLookupEndpoint)
+}
+
+// showMany requests the Users with the given values of the given query
+// parameter from the given lookup endpoint, in chunks of maxLookupUsers.
+func (u *UserService) showMany(ctx context.Context, parameter string, values []string, endpoint *Endpoint) ([]User, []LookupError, error) {
+	var users []User
+	var lookupErrors []LookupError
+
+	for start := 0; start < len(values); start += maxLookupUsers {
+		end := start + maxLookupUsers
+		if end > len(values) {
+			end = len(values)
+		}
+
+		query := url.Values{}
+		query.Set(parameter, strings.Join(values[start:end], ","))
+		setUserFields(ctx, query)
+
+		page := new([]User)
+		wrapper := &DataWrapper{
+			Data:   page,
+			Errors: new([]Error),
+		}
+		req, err := retryablehttp.NewRequest(http.MethodGet, fmt.Sprintf("%s%s?%s", u.baseURL, endpoint.URL, query.Encode()), nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = endpoint.PerformRequest(ctx, req, u.twitterAPI, wrapper)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		users = append(users, *page...)
+		if wrapper.Errors != nil {
+			for _, apiError := range *wrapper.Errors {
+				lookupErrors = append(lookupErrors, LookupError{
+					Value: apiError.Value,
+					Err:   userError(apiError),
+				})
+			}
+		}
+	}
+
+	return users, lookupErrors, nil
+}
+
+// Me returns the User that the request is authenticated on behalf of. It
+// requires user context authentication.
+func (u *UserService) Me(ctx context.Context) (*User, error) {
+	return u.show(ctx, "", u.meEndpoint)
+}
+
+// show requests a single User from the given lookup endpoint.
+func (u *UserService) show(ctx context.Context, key string, endpoint *Endpoint) (*User, error) {
 	wrapper := &DataWrapper{
 		Data:   new(User),
 		Errors: new([]Error),
 	}
-	req, err := retryablehttp.NewRequest(http.MethodGet, fmt.Sprintf("%s%s%s", u.baseURL, u.userLookupEndpoint.URL, username), nil)
+	path := fmt.Sprintf("%s%s%s", u.baseURL, endpoint.URL, url.PathEscape(key))
+	query := url.Values{}
+	setUserFields(ctx, query)
+	if len(query) > 0 {
+		path = fmt.Sprintf("%s?%s", path, query.Encode())
+	}
+	req, err := retryablehttp.NewRequest(http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	err = u.userLookupEndpoint.PerformRequest(req, u.twitterAPI, wrapper)
+	err = endpoint.PerformRequest(ctx, req, u.twitterAPI, wrapper)
 	if err != nil {
 		return nil, err
 	}
@@ -77,3 +345,58 @@ func (u *UserService) Show(username string) (*User, error) {
 
 	return wrapper.Data.(*User), err
 }
+
+// Followers returns every User that follows the user with the given ID.
+func (u *UserService) Followers(ctx context.Context, id string) ([]User, error) {
+	return u.listUsers(ctx, id, u.followersEndpoint)
+}
+
+// Following returns every User that the user with the given ID follows.
+func (u *UserService) Following(ctx context.Context, id string) ([]User, error) {
+	return u.listUsers(ctx, id, u.followingEndpoint)
+}
+
+// listUsers requests every page of Users from the given endpoint for the user
+// with the given ID. Pages are requested until the Twitter API stops returning
+// a pagination token.
+func (u *UserService) listUsers(ctx context.Context, id string, endpoint *Endpoint) ([]User, error) {
+	var users []User
+	paginationToken := ""
+
+	for {
+		query := url.Values{}
+		query.Set("max_results", strconv.Itoa(maxFollowsResults))
+		setUserFields(ctx, query)
+		if paginationToken != "" {
+			query.Set("pagination_token", paginationToken)
+		}
+
+		page := new([]User)
+		wrapper := &DataWrapper{
+			Data:   page,
+			Errors: new([]Error),
+			Meta:   new(Meta),
+		}
+		req, err := retryablehttp.NewRequest(http.MethodGet, fmt.Sprintf("%s/%s%s?%s", u.baseURL, url.PathEscape(id), endpoint.URL, query.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		err = endpoint.PerformRequest(ctx, req, u.twitterAPI, wrapper)
+		if err != nil {
+			return nil, err
+		}
+
+		err = u.parseError(wrapper)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, *page...)
+
+		if wrapper.Meta == nil || wrapper.Meta.NextToken == "" {
+			return users, nil
+		}
+		paginationToken = wrapper.Meta.NextToken
+	}
+}
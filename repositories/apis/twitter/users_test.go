@@ -1,8 +1,16 @@
 package twitter_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/jake-hansen/followrs/apperrors"
 	"github.com/jake-hansen/followrs/repositories/apis/twitter"
 	"github.com/stretchr/testify/assert"
 )
@@ -24,9 +32,9 @@ func TestUserService_Show(t *testing.T) {
 
 		StandardHandler(t, mux, "/users/by/username/test", wrapper)
 
-		client, _ := twitter.NewTwitterAPI(server.URL, "", "", "")
+		client, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
 
-		user, err := client.UserService.Show("test")
+		user, err := client.UserService.Show(context.Background(), "test")
 		assert.NoError(t, err)
 		assert.Equal(t, testUser, user)
 	})
@@ -49,11 +57,366 @@ func TestUserService_Show(t *testing.T) {
 
 		StandardHandler(t, mux, "/users/by/username/notfound", wrapper)
 
-		client, _ := twitter.NewTwitterAPI(server.URL, "", "", "")
+		client, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
 
-		user, err := client.UserService.Show("notfound")
+		user, err := client.UserService.Show(context.Background(), "notfound")
 		assert.Nil(t, user)
 		assert.Error(t, err)
 		assert.Equal(t, "user not found", err.Error())
+		assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+	})
+
+	t.Run("user-suspended", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		wrapper := &twitter.DataWrapper{
+			Errors: &[]twitter.Error{{
+				Title:  "Forbidden",
+				Detail: "User has been suspended: [suspended].",
+			}},
+		}
+
+		StandardHandler(t, mux, "/users/by/username/suspended", wrapper)
+
+		client, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+
+		user, err := client.UserService.Show(context.Background(), "suspended")
+		assert.Nil(t, user)
+		assert.True(t, errors.Is(err, apperrors.ErrSuspended))
+	})
+
+	t.Run("user-protected", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		wrapper := &twitter.DataWrapper{
+			Errors: &[]twitter.Error{{
+				Title:  "Authorization Error",
+				Detail: "Sorry, you are not authorized to see the user with username: [protected].",
+			}},
+		}
+
+		StandardHandler(t, mux, "/users/by/username/protected", wrapper)
+
+		client, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+
+		user, err := client.UserService.Show(context.Background(), "protected")
+		assert.Nil(t, user)
+		assert.True(t, errors.Is(err, apperrors.ErrProtected))
+		assert.False(t, errors.Is(err, apperrors.ErrUnauthorized))
+	})
+
+	t.Run("unknown-error", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		apiError := twitter.Error{
+			Title:  "Invalid Request",
+			Detail: "One or more parameters to your request was invalid.",
+		}
+		wrapper := &twitter.DataWrapper{
+			Errors: &[]twitter.Error{apiError},
+		}
+
+		StandardHandler(t, mux, "/users/by/username/test", wrapper)
+
+		client, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+
+		user, err := client.UserService.Show(context.Background(), "test")
+		assert.Nil(t, user)
+		var reported twitter.Error
+		assert.True(t, errors.As(err, &reported))
+		assert.Equal(t, apiError, reported)
+		assert.Contains(t, err.Error(), "One or more parameters")
+	})
+
+	t.Run("invalid-username", func(t *testing.T) {
+		client, _ := twitter.NewTwitterAPI("http://localhost", "", "", "")
+
+		user, err := client.UserService.Show(context.Background(), "not a valid username")
+		assert.Nil(t, user)
+		assert.True(t, errors.Is(err, apperrors.ErrInvalidUsername))
+	})
+
+	t.Run("upstream-unauthorized", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		mux.HandleFunc("/users/by/username/test", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+
+		client, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+
+		user, err := client.UserService.Show(context.Background(), "test")
+		assert.Nil(t, user)
+		assert.True(t, errors.Is(err, apperrors.ErrUnauthorized))
+	})
+}
+
+// TestUserService_ShowMany tests the ShowMany and ShowManyByID functions in
+// UserService.
+func TestUserService_ShowMany(t *testing.T) {
+	t.Run("chunked-into-requests-of-100", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		var requested []int
+		mux.HandleFunc("/users/by", func(w http.ResponseWriter, r *http.Request) {
+			usernames := strings.Split(r.URL.Query().Get("usernames"), ",")
+			requested = append(requested, len(usernames))
+
+			users := make([]twitter.User, 0, len(usernames))
+			for _, username := range usernames {
+				users = append(users, twitter.User{ID: username, Username: username})
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("x-rate-limit-remaining", "100")
+			w.Header().Set("x-rate-limit-reset", "100")
+			bytes, _ := json.Marshal(&twitter.DataWrapper{Data: users})
+			w.Write(bytes)
+		})
+
+		usernames := make([]string, 150)
+		for i := range usernames {
+			usernames[i] = fmt.Sprintf("user%d", i)
+		}
+
+		client, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+
+		users, lookupErrors, err := client.UserService.ShowMany(context.Background(), usernames)
+		assert.NoError(t, err)
+		assert.Empty(t, lookupErrors)
+		assert.Len(t, users, 150)
+		assert.Equal(t, "user149", users[149].Username)
+		assert.Equal(t, []int{100, 50}, requested)
+	})
+
+	t.Run("partial-failure", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		wrapper := &twitter.DataWrapper{
+			Data: []twitter.User{{ID: "1", Username: "found"}},
+			Errors: &[]twitter.Error{
+				{Value: "missing", Title: "Not Found Error", Parameter: "usernames"},
+				{Value: "banned", Title: "Forbidden", Detail: "User has been suspended: [banned]."},
+			},
+		}
+		StandardHandler(t, mux, "/users/by", wrapper)
+
+		client, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+
+		users, lookupErrors, err := client.UserService.ShowMany(context.Background(), []string{"found", "missing", "banned", "not valid!"})
+		assert.NoError(t, err)
+		assert.Equal(t, []twitter.User{{ID: "1", Username: "found"}}, users)
+		assert.Len(t, lookupErrors, 3)
+		assert.Equal(t, "not valid!", lookupErrors[0].Value)
+		assert.True(t, errors.Is(&lookupErrors[0], apperrors.ErrInvalidUsername))
+		assert.Equal(t, "missing", lookupErrors[1].Value)
+		assert.True(t, errors.Is(&lookupErrors[1], apperrors.ErrNotFound))
+		assert.Equal(t, "banned", lookupErrors[2].Value)
+		assert.True(t, errors.Is(&lookupErrors[2], apperrors.ErrSuspended))
+	})
+
+	t.Run("by-id", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "1,2", r.URL.Query().Get("ids"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("x-rate-limit-remaining", "100")
+			w.Header().Set("x-rate-limit-reset", "100")
+			bytes, _ := json.Marshal(&twitter.DataWrapper{
+				Data:   []twitter.User{{ID: "1", Username: "one"}},
+				Errors: &[]twitter.Error{{Value: "2", Title: "Not Found Error", Parameter: "ids"}},
+			})
+			w.Write(bytes)
+		})
+
+		client, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+
+		users, lookupErrors, err := client.UserService.ShowManyByID(context.Background(), []string{"1", "2"})
+		assert.NoError(t, err)
+		assert.Equal(t, []twitter.User{{ID: "1", Username: "one"}}, users)
+		assert.Len(t, lookupErrors, 1)
+		assert.Equal(t, "2", lookupErrors[0].Value)
+		assert.True(t, errors.Is(&lookupErrors[0], apperrors.ErrNotFound))
+	})
+}
+
+// TestUserService_UserFields tests requesting the optional fields of Users.
+func TestUserService_UserFields(t *testing.T) {
+	t.Run("all-fields-by-default", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		mux.HandleFunc("/users/by/username/test", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, strings.Join(twitter.UserFields, ","), r.URL.Query().Get("user.fields"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("x-rate-limit-remaining", "100")
+			w.Header().Set("x-rate-limit-reset", "100")
+			w.Write([]byte(`{"data":{"id":"1","name":"Test","username":"test","created_at":"2013-12-14T04:35:55.000Z",` +
+				`"description":"bio","verified":true,"public_metrics":{"followers_count":10,"following_count":20,"tweet_count":30,"listed_count":4}}}`))
+		})
+
+		client, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+
+		user, err := client.UserService.Show(context.Background(), "test")
+		assert.NoError(t, err)
+		assert.Equal(t, &twitter.User{
+			ID:          "1",
+			Name:        "Test",
+			Username:    "test",
+			CreatedAt:   time.Date(2013, 12, 14, 4, 35, 55, 0, time.UTC),
+			Description: "bio",
+			Verified:    true,
+			PublicMetrics: &twitter.PublicMetrics{
+				FollowersCount: 10,
+				FollowingCount: 20,
+				TweetCount:     30,
+				ListedCount:    4,
+			},
+		}, user)
+	})
+
+	t.Run("selected-fields", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		fields := ""
+		mux.HandleFunc("/users/1/followers", func(w http.ResponseWriter, r *http.Request) {
+			fields = r.URL.Query().Get("user.fields")
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("x-rate-limit-remaining", "100")
+			w.Header().Set("x-rate-limit-reset", "100")
+			w.Write([]byte(`{"data":[]}`))
+		})
+
+		client, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+
+		ctx, err := twitter.WithUserFields(context.Background(), []string{"public_metrics", "verified"})
+		assert.NoError(t, err)
+		_, err = client.UserService.Followers(ctx, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, "public_metrics,verified", fields)
+	})
+
+	t.Run("unknown-field", func(t *testing.T) {
+		ctx, err := twitter.WithUserFields(context.Background(), []string{"pinned_tweet"})
+		assert.Nil(t, ctx)
+		assert.Error(t, err)
+	})
+}
+
+// TestUserService_Followers tests the Followers function in UserService.
+func TestUserService_Followers(t *testing.T) {
+	t.Run("success-multiple-pages", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		firstPage := []twitter.User{{ID: "2", Name: "first", Username: "first"}}
+		secondPage := []twitter.User{{ID: "3", Name: "second", Username: "second"}}
+
+		mux.HandleFunc("/users/1/followers", func(w http.ResponseWriter, r *http.Request) {
+			wrapper := &twitter.DataWrapper{
+				Data: firstPage,
+				Meta: &twitter.Meta{ResultCount: 1, NextToken: "next"},
+			}
+			if r.URL.Query().Get("pagination_token") == "next" {
+				wrapper = &twitter.DataWrapper{
+					Data: secondPage,
+					Meta: &twitter.Meta{ResultCount: 1},
+				}
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("x-rate-limit-remaining", "100")
+			w.Header().Set("x-rate-limit-reset", "100")
+			bytes, _ := json.Marshal(wrapper)
+			w.Write(bytes)
+		})
+
+		client, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+
+		followers, err := client.UserService.Followers(context.Background(), "1")
+		assert.NoError(t, err)
+		assert.Equal(t, append(firstPage, secondPage...), followers)
+	})
+
+	t.Run("no-followers", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		wrapper := &twitter.DataWrapper{
+			Meta: &twitter.Meta{ResultCount: 0},
+		}
+
+		StandardHandler(t, mux, "/users/1/followers", wrapper)
+
+		client, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+
+		followers, err := client.UserService.Followers(context.Background(), "1")
+		assert.NoError(t, err)
+		assert.Empty(t, followers)
+	})
+
+	t.Run("user-not-found", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		wrapper := &twitter.DataWrapper{
+			Errors: &[]twitter.Error{{Title: "Not Found Error"}},
+		}
+
+		StandardHandler(t, mux, "/users/1/followers", wrapper)
+
+		client, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+
+		followers, err := client.UserService.Followers(context.Background(), "1")
+		assert.Nil(t, followers)
+		assert.Error(t, err)
+		assert.Equal(t, "user not found", err.Error())
+	})
+}
+
+// TestUserService_Following tests the Following function in UserService.
+func TestUserService_Following(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mux, server := NewTestServer()
+
+		defer server.Close()
+
+		following := []twitter.User{{ID: "2", Name: "followed", Username: "followed"}}
+		wrapper := &twitter.DataWrapper{
+			Data: following,
+			Meta: &twitter.Meta{ResultCount: 1},
+		}
+
+		StandardHandler(t, mux, "/users/1/following", wrapper)
+
+		client, _ := twitter.NewTwitterAPI(server.URL, "", "", "bearer")
+
+		users, err := client.UserService.Following(context.Background(), "1")
+		assert.NoError(t, err)
+		assert.Equal(t, following, users)
 	})
 }
@@ -0,0 +1,105 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+)
+
+// API provides the services needed to interact with the YouTube Data API.
+type API struct {
+	Client *apis.API
+
+	// Quota keeps track of the quota units requests have spent today. Requests
+	// that would exceed it fail without being sent.
+	Quota *Quota
+}
+
+// decodeError decodes the Google API error YouTube describes in the body of a
+// failed response. Its Name is the reason given for the error, such as
+// "quotaExceeded". YouTube rejects requests that exceed a quota or a rate
+// limit, and requests with a rejected API key, with statuses that don't tell
+// them apart from other errors, so their Kind is decided by their reason.
+func decodeError(httpError *apis.HTTPError) *apis.ResponseError {
+	responseError := &apis.ResponseError{HTTPError: httpError}
+
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+			Errors  []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(httpError.Body, &body); err != nil {
+		return responseError
+	}
+	responseError.Message = body.Error.Message
+	if len(body.Error.Errors) > 0 {
+		responseError.Name = body.Error.Errors[0].Reason
+	}
+
+	switch responseError.Name {
+	case "quotaExceeded", "dailyLimitExceeded", "rateLimitExceeded":
+		responseError.Kind = apperrors.ErrRateLimited
+	case "keyInvalid", "keyExpired":
+		responseError.Kind = apperrors.ErrUnauthorized
+	}
+	return responseError
+}
+
+// quotaExceeded determines if the error reports that the daily quota of the
+// API key was spent. Other rate limits, such as "rateLimitExceeded", which
+// limits how quickly requests are made, don't use up the quota.
+func quotaExceeded(err error) bool {
+	var responseError *apis.ResponseError
+	if !errors.As(err, &responseError) {
+		return false
+	}
+	return responseError.Name == "quotaExceeded" || responseError.Name == "dailyLimitExceeded"
+}
+
+// NewYouTubeAPI creates an API that requests the YouTube Data API at the given
+// URL, such as "https://www.googleapis.com/youtube/v3", with the given API
+// key, which may spend the given number of quota units each day.
+func NewYouTubeAPI(baseURL string, apiKey string, dailyQuota int64) (*API, error) {
+	auth := NewAPIKeyAuth(apiKey)
+
+	api, err := apis.NewAPI(baseURL, auth, auth.Attach, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not create YouTube API: %w", err)
+	}
+	api.DecodeError = decodeError
+
+	return &API{Client: api, Quota: NewQuota(dailyQuota)}, nil
+}
+
+// RateLimit returns the quota units that remain to be spent today, and when
+// the quota resets. It is always known, since it is kept track of locally.
+func (a *API) RateLimit(ctx context.Context) (apis.RateLimit, bool) {
+	return a.Quota.Remaining(), true
+}
+
+// get requests the given path with the given parameters after spending the
+// given number of quota units, which fails if the quota is exhausted. YouTube
+// charges for requests whether or not they succeed. YouTube doesn't report
+// the quota in response headers, so it is never known to the API's rate limit
+// registry, and only the Quota limits requests. If YouTube reports that
+// the quota was exceeded, such as by requests made elsewhere with the same
+// API key, the Quota is exhausted until it resets.
+func (a *API) get(ctx context.Context, path string, params url.Values, cost int64, body interface{}) error {
+	if err := a.Quota.Spend(cost); err != nil {
+		return fmt.Errorf("could not perform request to %s: %w", path, err)
+	}
+
+	_, err := a.Client.Get(ctx, quotaEndpoint, fmt.Sprintf("%s?%s", path, params.Encode()), body)
+	if quotaExceeded(err) {
+		a.Quota.Exhaust()
+	}
+	return err
+}
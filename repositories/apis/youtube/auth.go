@@ -0,0 +1,46 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jake-hansen/followrs/apperrors"
+)
+
+// APIKeyAuth authenticates requests to the YouTube Data API with an API key,
+// which identifies the Google Cloud project whose quota requests spend. The
+// key is sent in a header rather than the query string, so that it isn't
+// included in the URLs of failed requests.
+type APIKeyAuth struct {
+	APIKey string
+}
+
+// NewAPIKeyAuth creates an APIKeyAuth with the given API key.
+func NewAPIKeyAuth(apiKey string) *APIKeyAuth {
+	return &APIKeyAuth{
+		APIKey: apiKey,
+	}
+}
+
+// IsAuthenticated determines if an API key is configured.
+func (a *APIKeyAuth) IsAuthenticated() bool {
+	return a.APIKey != ""
+}
+
+// Authenticate fails if no API key is configured, since the YouTube Data API
+// rejects requests without one.
+func (a *APIKeyAuth) Authenticate(ctx context.Context) error {
+	if a.APIKey == "" {
+		return fmt.Errorf("no YouTube API key configured: %w", apperrors.ErrUnauthorized)
+	}
+	return nil
+}
+
+// Invalidate does nothing, since the API key can't be obtained again.
+func (a *APIKeyAuth) Invalidate() {}
+
+// Attach attaches the API key to a request.
+func (a *APIKeyAuth) Attach(req *retryablehttp.Request) {
+	req.Header.Set("X-Goog-Api-Key", a.APIKey)
+}
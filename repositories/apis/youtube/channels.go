@@ -0,0 +1,95 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jake-hansen/followrs/apperrors"
+)
+
+// channelsListCost is the number of quota units a request to channels.list
+// spends.
+const channelsListCost = 1
+
+// channelIDPattern matches the IDs of channels, which are "UC" followed by 22
+// URL-safe base64 characters.
+var channelIDPattern = regexp.MustCompile(`^UC[A-Za-z0-9_-]{22}$`)
+
+// handlePattern matches the handles YouTube allows, without their "@", which
+// are between 3 and 30 letters, digits, underscores, hyphens and periods.
+var handlePattern = regexp.MustCompile(`^[\pL\pN_.-]{3,30}$`)
+
+// Channel represents a YouTube channel.
+type Channel struct {
+	ID         string            `json:"id"`
+	Snippet    ChannelSnippet    `json:"snippet"`
+	Statistics ChannelStatistics `json:"statistics"`
+}
+
+// ChannelSnippet describes a channel.
+type ChannelSnippet struct {
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	CustomURL   string               `json:"customUrl"` // Handle of the channel, such as "@youtube".
+	PublishedAt *time.Time           `json:"publishedAt"`
+	Country     string               `json:"country"`
+	Thumbnails  map[string]Thumbnail `json:"thumbnails"` // Thumbnails by size, such as "default" or "high".
+}
+
+// Thumbnail is an image of a channel.
+type Thumbnail struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// ChannelStatistics counts the subscribers, views and videos of a channel.
+// SubscriberCount is zero when HiddenSubscriberCount is true, since the owner
+// of the channel has hidden it. YouTube reports counts as strings.
+type ChannelStatistics struct {
+	ViewCount             int64 `json:"viewCount,string"`
+	SubscriberCount       int64 `json:"subscriberCount,string"`
+	HiddenSubscriberCount bool  `json:"hiddenSubscriberCount"`
+	VideoCount            int64 `json:"videoCount,string"`
+}
+
+// Handle returns the handle of the channel without its "@", or its ID if it
+// has no handle.
+func (c *Channel) Handle() string {
+	if c.Snippet.CustomURL == "" {
+		return c.ID
+	}
+	return strings.TrimPrefix(c.Snippet.CustomURL, "@")
+}
+
+// GetChannel returns the channel with the given handle, with or without its
+// "@", or with the given channel ID.
+func (a *API) GetChannel(ctx context.Context, handleOrID string) (*Channel, error) {
+	params := url.Values{"part": {"snippet,statistics"}}
+	switch handle := strings.TrimPrefix(handleOrID, "@"); {
+	case channelIDPattern.MatchString(handleOrID):
+		params.Set("id", handleOrID)
+	case handlePattern.MatchString(handle):
+		params.Set("forHandle", "@"+handle)
+	default:
+		return nil, fmt.Errorf("%q is not a handle or ID of a YouTube channel: %w", handleOrID, apperrors.ErrInvalidUsername)
+	}
+
+	var body struct {
+		Items []Channel `json:"items"`
+	}
+	if err := a.get(ctx, "/channels", params, channelsListCost, &body); err != nil {
+		return nil, err
+	}
+
+	// YouTube responds with no channels, rather than 404 Not Found, when none
+	// match.
+	if len(body.Items) == 0 {
+		return nil, fmt.Errorf("channel %s: %w", handleOrID, apperrors.ErrNotFound)
+	}
+	return &body.Items[0], nil
+}
@@ -0,0 +1,190 @@
+package youtube_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+	"github.com/jake-hansen/followrs/repositories/apis/youtube"
+	"github.com/stretchr/testify/assert"
+)
+
+const channelID = "UC_x5XG1OV2P6uZZ5FSM9Ttw"
+
+// newTestAPI creates an API with the given daily quota for a YouTube server
+// that responds with the handlers registered on the returned mux.
+func newTestAPI(t *testing.T, dailyQuota int64) (*http.ServeMux, *youtube.API) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	api, err := youtube.NewYouTubeAPI(server.URL, "api-key", dailyQuota)
+	assert.NoError(t, err)
+	return mux, api
+}
+
+func writeChannel(w http.ResponseWriter) {
+	fmt.Fprintf(w, `{"items":[{"id":%q,"snippet":{"title":"Google for Developers","customUrl":"@googledevelopers","publishedAt":"2007-08-23T00:34:43Z","thumbnails":{"default":{"url":"https://yt3.ggpht.com/default.jpg"}}},"statistics":{"viewCount":"100","subscriberCount":"20","hiddenSubscriberCount":false,"videoCount":"3"}}]}`, channelID)
+}
+
+func writeError(w http.ResponseWriter, status int, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"error":{"code":%d,"message":"Request failed","errors":[{"message":"Request failed","domain":"youtube","reason":%q}]}}`, status, reason)
+}
+
+// TestGetChannel tests the GetChannel function of API.
+func TestGetChannel(t *testing.T) {
+	t.Run("handle", func(t *testing.T) {
+		mux, api := newTestAPI(t, youtube.DefaultDailyQuota)
+		mux.HandleFunc("/channels", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "api-key", r.Header.Get("X-Goog-Api-Key"))
+			assert.Equal(t, "snippet,statistics", r.URL.Query().Get("part"))
+			assert.Equal(t, "@googledevelopers", r.URL.Query().Get("forHandle"))
+			assert.Empty(t, r.URL.Query().Get("id"))
+			writeChannel(w)
+		})
+
+		for _, handle := range []string{"googledevelopers", "@googledevelopers"} {
+			channel, err := api.GetChannel(context.Background(), handle)
+			assert.NoError(t, err)
+			assert.Equal(t, channelID, channel.ID)
+			assert.Equal(t, "googledevelopers", channel.Handle())
+			assert.Equal(t, "Google for Developers", channel.Snippet.Title)
+			assert.True(t, time.Date(2007, time.August, 23, 0, 34, 43, 0, time.UTC).Equal(*channel.Snippet.PublishedAt))
+			assert.Equal(t, "https://yt3.ggpht.com/default.jpg", channel.Snippet.Thumbnails["default"].URL)
+			assert.Equal(t, youtube.ChannelStatistics{ViewCount: 100, SubscriberCount: 20, VideoCount: 3}, channel.Statistics)
+		}
+
+		limit, ok := api.RateLimit(context.Background())
+		assert.True(t, ok)
+		assert.Equal(t, int64(youtube.DefaultDailyQuota-2), limit.Remaining)
+	})
+
+	t.Run("id", func(t *testing.T) {
+		mux, api := newTestAPI(t, youtube.DefaultDailyQuota)
+		mux.HandleFunc("/channels", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, channelID, r.URL.Query().Get("id"))
+			assert.Empty(t, r.URL.Query().Get("forHandle"))
+			writeChannel(w)
+		})
+
+		_, err := api.GetChannel(context.Background(), channelID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("hidden-subscriber-count", func(t *testing.T) {
+		mux, api := newTestAPI(t, youtube.DefaultDailyQuota)
+		mux.HandleFunc("/channels", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"items":[{"id":%q,"statistics":{"viewCount":"100","hiddenSubscriberCount":true,"videoCount":"3"}}]}`, channelID)
+		})
+
+		channel, err := api.GetChannel(context.Background(), channelID)
+		assert.NoError(t, err)
+		assert.True(t, channel.Statistics.HiddenSubscriberCount)
+		assert.Equal(t, int64(0), channel.Statistics.SubscriberCount)
+		assert.Equal(t, channelID, channel.Handle())
+	})
+
+	t.Run("invalid-handle", func(t *testing.T) {
+		_, api := newTestAPI(t, youtube.DefaultDailyQuota)
+
+		for _, handle := range []string{"", "@", "ab", "has space", "slash/path", "this-handle-is-far-too-long-for-youtube"} {
+			channel, err := api.GetChannel(context.Background(), handle)
+			assert.Nil(t, channel, handle)
+			assert.True(t, errors.Is(err, apperrors.ErrInvalidUsername), handle)
+		}
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mux, api := newTestAPI(t, youtube.DefaultDailyQuota)
+		mux.HandleFunc("/channels", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"kind":"youtube#channelListResponse","pageInfo":{"totalResults":0}}`)
+		})
+
+		channel, err := api.GetChannel(context.Background(), "nobody")
+		assert.Nil(t, channel)
+		assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+	})
+
+	t.Run("invalid-key", func(t *testing.T) {
+		mux, api := newTestAPI(t, youtube.DefaultDailyQuota)
+		mux.HandleFunc("/channels", func(w http.ResponseWriter, r *http.Request) {
+			writeError(w, http.StatusBadRequest, "keyInvalid")
+		})
+
+		_, err := api.GetChannel(context.Background(), "googledevelopers")
+		assert.True(t, errors.Is(err, apperrors.ErrUnauthorized))
+		var responseError *apis.ResponseError
+		assert.True(t, errors.As(err, &responseError))
+		assert.Equal(t, "keyInvalid", responseError.Name)
+	})
+
+	t.Run("no-key", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+		}))
+		t.Cleanup(server.Close)
+		api, err := youtube.NewYouTubeAPI(server.URL, "", youtube.DefaultDailyQuota)
+		assert.NoError(t, err)
+
+		_, err = api.GetChannel(context.Background(), "googledevelopers")
+		assert.True(t, errors.Is(err, apperrors.ErrUnauthorized))
+		assert.Equal(t, 0, requests)
+	})
+
+	t.Run("quota-exhausted", func(t *testing.T) {
+		mux, api := newTestAPI(t, 1)
+		requests := 0
+		mux.HandleFunc("/channels", func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			writeChannel(w)
+		})
+
+		_, err := api.GetChannel(context.Background(), "googledevelopers")
+		assert.NoError(t, err)
+
+		_, err = api.GetChannel(context.Background(), "googledevelopers")
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("rate-limit-exceeded", func(t *testing.T) {
+		mux, api := newTestAPI(t, youtube.DefaultDailyQuota)
+		mux.HandleFunc("/channels", func(w http.ResponseWriter, r *http.Request) {
+			writeError(w, http.StatusForbidden, "rateLimitExceeded")
+		})
+
+		_, err := api.GetChannel(context.Background(), "googledevelopers")
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+
+		limit, _ := api.RateLimit(context.Background())
+		assert.Equal(t, int64(youtube.DefaultDailyQuota-1), limit.Remaining)
+	})
+
+	t.Run("quota-exceeded", func(t *testing.T) {
+		mux, api := newTestAPI(t, youtube.DefaultDailyQuota)
+		requests := 0
+		mux.HandleFunc("/channels", func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			writeError(w, http.StatusForbidden, "quotaExceeded")
+		})
+
+		_, err := api.GetChannel(context.Background(), "googledevelopers")
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+
+		limit, _ := api.RateLimit(context.Background())
+		assert.Equal(t, int64(0), limit.Remaining)
+
+		_, err = api.GetChannel(context.Background(), "googledevelopers")
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+		assert.Equal(t, 1, requests)
+	})
+}
@@ -0,0 +1,106 @@
+package youtube
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jake-hansen/followrs/repositories/apis"
+)
+
+// DefaultDailyQuota is the number of quota units Google Cloud projects may
+// spend on the YouTube Data API each day, unless Google has granted more.
+const DefaultDailyQuota = 10000
+
+// quotaEndpoint is the name of the quota in the errors returned when it is
+// exhausted.
+const quotaEndpoint = "daily quota"
+
+// quotaLocation is the time zone in which the quota resets at midnight.
+var quotaLocation = loadQuotaLocation()
+
+// loadQuotaLocation returns Pacific Time, or Pacific Standard Time if the
+// time zone database isn't available.
+func loadQuotaLocation() *time.Location {
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		return time.FixedZone("PST", -8*60*60)
+	}
+	return location
+}
+
+// Quota keeps track of the quota units that requests to the YouTube Data API
+// have spent. Rather than limiting the number of requests in a short window,
+// YouTube limits the units that requests spend each day, which differ by the
+// method requested. The quota resets at midnight Pacific Time.
+type Quota struct {
+	// Limit is the number of units that may be spent each day.
+	Limit int64
+
+	mu    sync.Mutex
+	spent int64
+	reset time.Time // Time at which the units spent so far are forgotten.
+	now   func() time.Time
+}
+
+// NewQuota creates a Quota that allows the given number of units to be spent
+// each day.
+func NewQuota(limit int64) *Quota {
+	return &Quota{
+		Limit: limit,
+		now:   time.Now,
+	}
+}
+
+// Spend spends the given number of units, unless that would exceed the
+// quota, in which case an *apis.RateLimitError is returned.
+func (q *Quota) Spend(units int64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.resetIfDue()
+	if q.spent+units > q.Limit {
+		return &apis.RateLimitError{
+			Key:   apis.RateLimitKey{Endpoint: quotaEndpoint},
+			Reset: q.reset,
+		}
+	}
+	q.spent += units
+	return nil
+}
+
+// Exhaust records that every unit of the quota has been spent, such as when
+// YouTube reports that it has been exceeded by requests made elsewhere.
+func (q *Quota) Exhaust() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.resetIfDue()
+	q.spent = q.Limit
+}
+
+// Remaining returns the number of units that remain to be spent today, and
+// the time at which the quota resets.
+func (q *Quota) Remaining() apis.RateLimit {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.resetIfDue()
+	remaining := q.Limit - q.spent
+	if remaining < 0 {
+		remaining = 0
+	}
+	return apis.RateLimit{Remaining: remaining, Reset: q.reset}
+}
+
+// resetIfDue forgets the units spent before the last reset, and determines
+// when the quota next resets. The caller must hold q.mu.
+func (q *Quota) resetIfDue() {
+	now := q.now()
+	if now.Before(q.reset) {
+		return
+	}
+
+	q.spent = 0
+	local := now.In(quotaLocation)
+	q.reset = time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, quotaLocation)
+}
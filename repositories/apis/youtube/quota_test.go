@@ -0,0 +1,40 @@
+package youtube
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/repositories/apis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestQuota tests that Quota allows units to be spent until the daily quota
+// is exhausted, and resets it at midnight Pacific Time.
+func TestQuota(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 23, 30, 0, 0, quotaLocation)
+	midnight := time.Date(2024, time.March, 2, 0, 0, 0, 0, quotaLocation)
+	quota := NewQuota(3)
+	quota.now = func() time.Time { return now }
+
+	assert.NoError(t, quota.Spend(2))
+	assert.Equal(t, apis.RateLimit{Remaining: 1, Reset: midnight}, quota.Remaining())
+
+	err := quota.Spend(2)
+	assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+	var rateLimitError *apis.RateLimitError
+	assert.True(t, errors.As(err, &rateLimitError))
+	assert.True(t, midnight.Equal(rateLimitError.Reset))
+
+	assert.NoError(t, quota.Spend(1))
+	assert.Equal(t, int64(0), quota.Remaining().Remaining)
+
+	now = midnight
+	assert.Equal(t, int64(3), quota.Remaining().Remaining)
+	assert.NoError(t, quota.Spend(1))
+
+	quota.Exhaust()
+	assert.Error(t, quota.Spend(1))
+	assert.True(t, midnight.AddDate(0, 0, 1).Equal(quota.Remaining().Reset))
+}
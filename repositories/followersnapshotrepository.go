@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+)
+
+// InMemoryFollowerSnapshotRepository is a FollowerSnapshotRepository that keeps
+// every snapshot in memory. Snapshots are lost when the program exits.
+type InMemoryFollowerSnapshotRepository struct {
+	mu        sync.RWMutex
+	snapshots map[string][]domain.FollowerSnapshot
+}
+
+// NewInMemoryFollowerSnapshotRepository creates an empty InMemoryFollowerSnapshotRepository.
+func NewInMemoryFollowerSnapshotRepository() domain.FollowerSnapshotRepository {
+	return &InMemoryFollowerSnapshotRepository{
+		snapshots: make(map[string][]domain.FollowerSnapshot),
+	}
+}
+
+// Save stores a copy of the given snapshot with its follower IDs sorted.
+func (r *InMemoryFollowerSnapshotRepository) Save(ctx context.Context, snapshot *domain.FollowerSnapshot) error {
+	stored := copySnapshot(*snapshot)
+	stored.TakenAt = stored.TakenAt.UTC()
+	sort.Strings(stored.FollowerIDs)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := snapshotKey(snapshot.Platform, snapshot.AccountID)
+	snapshots := append(r.snapshots[key], stored)
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].TakenAt.Before(snapshots[j].TakenAt)
+	})
+	r.snapshots[key] = snapshots
+
+	return nil
+}
+
+// At returns the most recent snapshot of the account taken at or before t.
+func (r *InMemoryFollowerSnapshotRepository) At(ctx context.Context, platform string, accountID string, t time.Time) (*domain.FollowerSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshots := r.snapshots[snapshotKey(platform, accountID)]
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if !snapshots[i].TakenAt.After(t) {
+			snapshot := copySnapshot(snapshots[i])
+			return &snapshot, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// List returns every snapshot of the account taken between from and to.
+func (r *InMemoryFollowerSnapshotRepository) List(ctx context.Context, platform string, accountID string, from time.Time, to time.Time) ([]domain.FollowerSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var snapshots []domain.FollowerSnapshot
+	for _, snapshot := range r.snapshots[snapshotKey(platform, accountID)] {
+		if !snapshot.TakenAt.Before(from) && !snapshot.TakenAt.After(to) {
+			snapshots = append(snapshots, copySnapshot(snapshot))
+		}
+	}
+
+	return snapshots, nil
+}
+
+func snapshotKey(platform string, accountID string) string {
+	return platform + "/" + accountID
+}
+
+func copySnapshot(snapshot domain.FollowerSnapshot) domain.FollowerSnapshot {
+	followerIDs := make([]string, len(snapshot.FollowerIDs))
+	copy(followerIDs, snapshot.FollowerIDs)
+	snapshot.FollowerIDs = followerIDs
+	return snapshot
+}
@@ -0,0 +1,77 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories"
+)
+
+// snapshotRepositories returns a new, empty instance of every FollowerSnapshotRepository implementation.
+func snapshotRepositories(t *testing.T) map[string]domain.FollowerSnapshotRepository {
+	db, err := repositories.OpenSQLiteDatabase(":memory:")
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	sqliteRepo, err := repositories.NewSQLiteFollowerSnapshotRepository(db)
+	assert.NoError(t, err)
+
+	return map[string]domain.FollowerSnapshotRepository{
+		"in-memory": repositories.NewInMemoryFollowerSnapshotRepository(),
+		"sqlite":    sqliteRepo,
+	}
+}
+
+func TestFollowerSnapshotRepository_At(t *testing.T) {
+	now := time.Now().UTC()
+	older := &domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: now.Add(-time.Hour), FollowerIDs: []string{"2", "3"}}
+	newer := &domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: now, FollowerIDs: []string{"3", "4"}}
+	other := &domain.FollowerSnapshot{Platform: "twitter", AccountID: "5", TakenAt: now, FollowerIDs: []string{"6"}}
+
+	for name, repo := range snapshotRepositories(t) {
+		t.Run(name, func(t *testing.T) {
+			assert.NoError(t, repo.Save(context.Background(), newer))
+			assert.NoError(t, repo.Save(context.Background(), older))
+			assert.NoError(t, repo.Save(context.Background(), other))
+
+			snapshot, err := repo.At(context.Background(), "twitter", "1", now.Add(-time.Minute))
+			assert.NoError(t, err)
+			assert.Equal(t, older, snapshot)
+
+			snapshot, err = repo.At(context.Background(), "twitter", "1", now)
+			assert.NoError(t, err)
+			assert.Equal(t, newer, snapshot)
+
+			snapshot, err = repo.At(context.Background(), "twitter", "1", now.Add(-2*time.Hour))
+			assert.NoError(t, err)
+			assert.Nil(t, snapshot)
+		})
+	}
+}
+
+func TestFollowerSnapshotRepository_List(t *testing.T) {
+	now := time.Now().UTC()
+	first := domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: now.Add(-2 * time.Hour), FollowerIDs: []string{"2"}}
+	second := domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: now.Add(-time.Hour), FollowerIDs: []string{}}
+	third := domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: now, FollowerIDs: []string{"2", "3"}}
+
+	for name, repo := range snapshotRepositories(t) {
+		t.Run(name, func(t *testing.T) {
+			assert.NoError(t, repo.Save(context.Background(), &third))
+			assert.NoError(t, repo.Save(context.Background(), &first))
+			assert.NoError(t, repo.Save(context.Background(), &second))
+
+			snapshots, err := repo.List(context.Background(), "twitter", "1", now.Add(-90*time.Minute), now)
+			assert.NoError(t, err)
+			assert.Equal(t, []domain.FollowerSnapshot{second, third}, snapshots)
+
+			snapshots, err = repo.List(context.Background(), "mastodon", "1", now.Add(-3*time.Hour), now)
+			assert.NoError(t, err)
+			assert.Empty(t, snapshots)
+		})
+	}
+}
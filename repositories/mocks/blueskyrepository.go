@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/jake-hansen/followrs/repositories/apis/bluesky"
+	"github.com/stretchr/testify/mock"
+)
+
+// BlueskyRepository is a mock BlueskyRepository.
+type BlueskyRepository struct {
+	mock.Mock
+}
+
+// GetProfile provides a mock function.
+func (m *BlueskyRepository) GetProfile(ctx context.Context, actor string) (*bluesky.Profile, error) {
+	args := m.Called(ctx, actor)
+	profile, _ := args.Get(0).(*bluesky.Profile)
+	return profile, args.Error(1)
+}
+
+// GetFollowers provides a mock function.
+func (m *BlueskyRepository) GetFollowers(ctx context.Context, actor string) ([]bluesky.Profile, error) {
+	args := m.Called(ctx, actor)
+	profiles, _ := args.Get(0).([]bluesky.Profile)
+	return profiles, args.Error(1)
+}
+
+// GetFollows provides a mock function.
+func (m *BlueskyRepository) GetFollows(ctx context.Context, actor string) ([]bluesky.Profile, error) {
+	args := m.Called(ctx, actor)
+	profiles, _ := args.Get(0).([]bluesky.Profile)
+	return profiles, args.Error(1)
+}
@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/jake-hansen/followrs/repositories/apis/github"
+	"github.com/stretchr/testify/mock"
+)
+
+// GitHubRepository is a mock GitHubRepository.
+type GitHubRepository struct {
+	mock.Mock
+}
+
+// GetUser provides a mock function.
+func (m *GitHubRepository) GetUser(ctx context.Context, login string) (*github.User, error) {
+	args := m.Called(ctx, login)
+	user, _ := args.Get(0).(*github.User)
+	return user, args.Error(1)
+}
+
+// GetUserByID provides a mock function.
+func (m *GitHubRepository) GetUserByID(ctx context.Context, id int64) (*github.User, error) {
+	args := m.Called(ctx, id)
+	user, _ := args.Get(0).(*github.User)
+	return user, args.Error(1)
+}
+
+// GetFollowers provides a mock function.
+func (m *GitHubRepository) GetFollowers(ctx context.Context, login string) ([]github.User, error) {
+	args := m.Called(ctx, login)
+	users, _ := args.Get(0).([]github.User)
+	return users, args.Error(1)
+}
+
+// GetFollowing provides a mock function.
+func (m *GitHubRepository) GetFollowing(ctx context.Context, login string) ([]github.User, error) {
+	args := m.Called(ctx, login)
+	users, _ := args.Get(0).([]github.User)
+	return users, args.Error(1)
+}
+
+// GetStargazers provides a mock function.
+func (m *GitHubRepository) GetStargazers(ctx context.Context, owner string, repo string) ([]github.User, error) {
+	args := m.Called(ctx, owner, repo)
+	users, _ := args.Get(0).([]github.User)
+	return users, args.Error(1)
+}
@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/jake-hansen/followrs/repositories/apis/mastodon"
+	"github.com/stretchr/testify/mock"
+)
+
+// MastodonRepository is a mock MastodonRepository.
+type MastodonRepository struct {
+	mock.Mock
+}
+
+// LookupAccount provides a mock function.
+func (m *MastodonRepository) LookupAccount(ctx context.Context, acct string) (*mastodon.Account, error) {
+	args := m.Called(ctx, acct)
+	account, _ := args.Get(0).(*mastodon.Account)
+	return account, args.Error(1)
+}
+
+// Followers provides a mock function.
+func (m *MastodonRepository) Followers(ctx context.Context, account *mastodon.Account) ([]mastodon.Account, error) {
+	args := m.Called(ctx, account)
+	accounts, _ := args.Get(0).([]mastodon.Account)
+	return accounts, args.Error(1)
+}
+
+// Following provides a mock function.
+func (m *MastodonRepository) Following(ctx context.Context, account *mastodon.Account) ([]mastodon.Account, error) {
+	args := m.Called(ctx, account)
+	accounts, _ := args.Get(0).([]mastodon.Account)
+	return accounts, args.Error(1)
+}
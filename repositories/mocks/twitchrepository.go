@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/jake-hansen/followrs/repositories/apis/twitch"
+	"github.com/stretchr/testify/mock"
+)
+
+// TwitchRepository is a mock TwitchRepository.
+type TwitchRepository struct {
+	mock.Mock
+}
+
+// GetUser provides a mock function.
+func (m *TwitchRepository) GetUser(ctx context.Context, login string) (*twitch.User, error) {
+	args := m.Called(ctx, login)
+	user, _ := args.Get(0).(*twitch.User)
+	return user, args.Error(1)
+}
+
+// GetFollowerCount provides a mock function.
+func (m *TwitchRepository) GetFollowerCount(ctx context.Context, broadcasterID string) (int64, error) {
+	args := m.Called(ctx, broadcasterID)
+	count, _ := args.Get(0).(int64)
+	return count, args.Error(1)
+}
+
+// GetFollowers provides a mock function.
+func (m *TwitchRepository) GetFollowers(ctx context.Context, broadcasterID string) ([]twitch.Follower, int64, error) {
+	args := m.Called(ctx, broadcasterID)
+	followers, _ := args.Get(0).([]twitch.Follower)
+	total, _ := args.Get(1).(int64)
+	return followers, total, args.Error(2)
+}
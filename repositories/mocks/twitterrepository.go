@@ -0,0 +1,71 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/jake-hansen/followrs/repositories/apis/twitter"
+	"github.com/stretchr/testify/mock"
+)
+
+// TwitterRepository is a mock TwitterRepository.
+type TwitterRepository struct {
+	mock.Mock
+}
+
+// GetUser provides a mock function.
+func (m *TwitterRepository) GetUser(ctx context.Context, username string, fields []string) (*twitter.User, error) {
+	args := m.Called(ctx, username, fields)
+	user, _ := args.Get(0).(*twitter.User)
+	return user, args.Error(1)
+}
+
+// GetUserByID provides a mock function.
+func (m *TwitterRepository) GetUserByID(ctx context.Context, id string, fields []string) (*twitter.User, error) {
+	args := m.Called(ctx, id, fields)
+	user, _ := args.Get(0).(*twitter.User)
+	return user, args.Error(1)
+}
+
+// GetUsers provides a mock function.
+func (m *TwitterRepository) GetUsers(ctx context.Context, usernames []string, fields []string) ([]twitter.User, []twitter.LookupError, error) {
+	args := m.Called(ctx, usernames, fields)
+	users, _ := args.Get(0).([]twitter.User)
+	lookupErrors, _ := args.Get(1).([]twitter.LookupError)
+	return users, lookupErrors, args.Error(2)
+}
+
+// GetUsersByID provides a mock function.
+func (m *TwitterRepository) GetUsersByID(ctx context.Context, ids []string, fields []string) ([]twitter.User, []twitter.LookupError, error) {
+	args := m.Called(ctx, ids, fields)
+	users, _ := args.Get(0).([]twitter.User)
+	lookupErrors, _ := args.Get(1).([]twitter.LookupError)
+	return users, lookupErrors, args.Error(2)
+}
+
+// GetAuthenticatedUser provides a mock function.
+func (m *TwitterRepository) GetAuthenticatedUser(ctx context.Context) (*twitter.User, error) {
+	args := m.Called(ctx)
+	user, _ := args.Get(0).(*twitter.User)
+	return user, args.Error(1)
+}
+
+// GetFollowers provides a mock function.
+func (m *TwitterRepository) GetFollowers(ctx context.Context, id string, fields []string) ([]twitter.User, error) {
+	args := m.Called(ctx, id, fields)
+	users, _ := args.Get(0).([]twitter.User)
+	return users, args.Error(1)
+}
+
+// GetFollowersRateLimit provides a mock function.
+func (m *TwitterRepository) GetFollowersRateLimit() (int64, time.Time) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Get(1).(time.Time)
+}
+
+// GetFollowing provides a mock function.
+func (m *TwitterRepository) GetFollowing(ctx context.Context, id string, fields []string) ([]twitter.User, error) {
+	args := m.Called(ctx, id, fields)
+	users, _ := args.Get(0).([]twitter.User)
+	return users, args.Error(1)
+}
@@ -0,0 +1,20 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/jake-hansen/followrs/repositories/apis/youtube"
+	"github.com/stretchr/testify/mock"
+)
+
+// YouTubeRepository is a mock YouTubeRepository.
+type YouTubeRepository struct {
+	mock.Mock
+}
+
+// GetChannel provides a mock function.
+func (m *YouTubeRepository) GetChannel(ctx context.Context, handleOrID string) (*youtube.Channel, error) {
+	args := m.Called(ctx, handleOrID)
+	channel, _ := args.Get(0).(*youtube.Channel)
+	return channel, args.Error(1)
+}
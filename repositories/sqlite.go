@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	// Registers the sqlite3 driver with database/sql.
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// OpenSQLiteDatabase opens the SQLite database stored at the given path,
+// creating it if it does not exist. The path ":memory:" may be used to open
+// a database that is never written to disk.
+func OpenSQLiteDatabase(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000", path))
+	if err != nil {
+		return nil, fmt.Errorf("could not open database %s: %w", path, err)
+	}
+
+	// SQLite only allows a single writer at a time, and each connection to an
+	// in-memory database sees its own empty database.
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not connect to database %s: %w", path, err)
+	}
+
+	return db, nil
+}
+
+// migrate executes each of the given statements against the database.
+func migrate(db *sql.DB, statements ...string) error {
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			return fmt.Errorf("could not migrate database: %w", err)
+		}
+	}
+	return nil
+}
+
+// addColumn adds the column with the given definition to the table, unless
+// the table already has it, such as when the table was created after the
+// column was added to its CREATE TABLE statement.
+func addColumn(db *sql.DB, table string, column string, definition string) error {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("could not migrate database: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			columnType string
+			notNull    bool
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultVal, &primaryKey); err != nil {
+			return fmt.Errorf("could not migrate database: %w", err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("could not migrate database: %w", err)
+	}
+	rows.Close()
+
+	return migrate(db, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+)
+
+// SQLiteAccountMetricsRepository is an AccountMetricsRepository backed by a
+// SQLite database.
+type SQLiteAccountMetricsRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteAccountMetricsRepository creates a SQLiteAccountMetricsRepository
+// using the given database, creating the tables it needs if they do not exist.
+func NewSQLiteAccountMetricsRepository(db *sql.DB) (domain.AccountMetricsRepository, error) {
+	err := migrate(db,
+		`CREATE TABLE IF NOT EXISTS account_metrics (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			platform TEXT NOT NULL,
+			account_id TEXT NOT NULL,
+			recorded_at INTEGER NOT NULL,
+			followers_count INTEGER NOT NULL,
+			following_count INTEGER NOT NULL,
+			tweet_count INTEGER NOT NULL,
+			listed_count INTEGER NOT NULL,
+			view_count INTEGER NOT NULL DEFAULT 0,
+			followers_hidden INTEGER NOT NULL DEFAULT 0
+		)`,
+		`CREATE INDEX IF NOT EXISTS account_metrics_account
+			ON account_metrics (platform, account_id, recorded_at)`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	// Tables created before views were recorded lack the columns for them.
+	if err := addColumn(db, "account_metrics", "view_count", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return nil, err
+	}
+	if err := addColumn(db, "account_metrics", "followers_hidden", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return nil, err
+	}
+
+	return &SQLiteAccountMetricsRepository{db: db}, nil
+}
+
+// Save stores the given metrics.
+func (r *SQLiteAccountMetricsRepository) Save(ctx context.Context, metrics *domain.AccountMetrics) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO account_metrics
+		(platform, account_id, recorded_at, followers_count, following_count, tweet_count, listed_count, view_count, followers_hidden)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		metrics.Platform, metrics.AccountID, metrics.RecordedAt.UnixNano(),
+		metrics.Followers, metrics.Following, metrics.Tweets, metrics.Listed, metrics.Views, metrics.FollowersHidden)
+	if err != nil {
+		return fmt.Errorf("could not save account metrics: %w", err)
+	}
+	return nil
+}
+
+// List returns every metrics of the account recorded between from and to.
+func (r *SQLiteAccountMetricsRepository) List(ctx context.Context, platform string, accountID string, from time.Time, to time.Time) ([]domain.AccountMetrics, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT recorded_at, followers_count, following_count, tweet_count, listed_count, view_count, followers_hidden
+		FROM account_metrics
+		WHERE platform = ? AND account_id = ? AND recorded_at >= ? AND recorded_at <= ?
+		ORDER BY recorded_at, id`,
+		platform, accountID, from.UnixNano(), to.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("could not list account metrics: %w", err)
+	}
+	defer rows.Close()
+
+	var metrics []domain.AccountMetrics
+	for rows.Next() {
+		recorded := domain.AccountMetrics{
+			Platform:  platform,
+			AccountID: accountID,
+		}
+		var recordedAt int64
+		if err := rows.Scan(&recordedAt, &recorded.Followers, &recorded.Following, &recorded.Tweets, &recorded.Listed, &recorded.Views, &recorded.FollowersHidden); err != nil {
+			return nil, fmt.Errorf("could not list account metrics: %w", err)
+		}
+		recorded.RecordedAt = time.Unix(0, recordedAt).UTC()
+		metrics = append(metrics, recorded)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not list account metrics: %w", err)
+	}
+
+	return metrics, nil
+}
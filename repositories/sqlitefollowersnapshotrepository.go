@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+)
+
+// SQLiteFollowerSnapshotRepository is a FollowerSnapshotRepository backed by
+// a SQLite database.
+type SQLiteFollowerSnapshotRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteFollowerSnapshotRepository creates a SQLiteFollowerSnapshotRepository
+// using the given database, creating the tables it needs if they do not exist.
+func NewSQLiteFollowerSnapshotRepository(db *sql.DB) (domain.FollowerSnapshotRepository, error) {
+	err := migrate(db,
+		`CREATE TABLE IF NOT EXISTS follower_snapshots (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			platform TEXT NOT NULL,
+			account_id TEXT NOT NULL,
+			taken_at INTEGER NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS follower_snapshots_account
+			ON follower_snapshots (platform, account_id, taken_at)`,
+		`CREATE TABLE IF NOT EXISTS follower_snapshot_followers (
+			snapshot_id INTEGER NOT NULL REFERENCES follower_snapshots (id) ON DELETE CASCADE,
+			follower_id TEXT NOT NULL,
+			PRIMARY KEY (snapshot_id, follower_id)
+		)`,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQLiteFollowerSnapshotRepository{db: db}, nil
+}
+
+// Save stores the given snapshot.
+func (r *SQLiteFollowerSnapshotRepository) Save(ctx context.Context, snapshot *domain.FollowerSnapshot) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not save follower snapshot: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `INSERT INTO follower_snapshots (platform, account_id, taken_at) VALUES (?, ?, ?)`,
+		snapshot.Platform, snapshot.AccountID, snapshot.TakenAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("could not save follower snapshot: %w", err)
+	}
+	snapshotID, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("could not save follower snapshot: %w", err)
+	}
+
+	insert, err := tx.PrepareContext(ctx, `INSERT OR IGNORE INTO follower_snapshot_followers (snapshot_id, follower_id) VALUES (?, ?)`)
+	if err != nil {
+		return fmt.Errorf("could not save follower snapshot: %w", err)
+	}
+	defer insert.Close()
+
+	for _, followerID := range snapshot.FollowerIDs {
+		if _, err := insert.ExecContext(ctx, snapshotID, followerID); err != nil {
+			return fmt.Errorf("could not save follower snapshot: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not save follower snapshot: %w", err)
+	}
+	return nil
+}
+
+// At returns the most recent snapshot of the account taken at or before t.
+func (r *SQLiteFollowerSnapshotRepository) At(ctx context.Context, platform string, accountID string, t time.Time) (*domain.FollowerSnapshot, error) {
+	var snapshotID int64
+	var takenAt int64
+	err := r.db.QueryRowContext(ctx, `SELECT id, taken_at FROM follower_snapshots
+		WHERE platform = ? AND account_id = ? AND taken_at <= ?
+		ORDER BY taken_at DESC, id DESC LIMIT 1`,
+		platform, accountID, t.UnixNano()).Scan(&snapshotID, &takenAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve follower snapshot: %w", err)
+	}
+
+	followerIDs, err := r.followerIDs(ctx, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.FollowerSnapshot{
+		Platform:    platform,
+		AccountID:   accountID,
+		TakenAt:     time.Unix(0, takenAt).UTC(),
+		FollowerIDs: followerIDs,
+	}, nil
+}
+
+// List returns every snapshot of the account taken between from and to.
+func (r *SQLiteFollowerSnapshotRepository) List(ctx context.Context, platform string, accountID string, from time.Time, to time.Time) ([]domain.FollowerSnapshot, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, taken_at FROM follower_snapshots
+		WHERE platform = ? AND account_id = ? AND taken_at >= ? AND taken_at <= ?
+		ORDER BY taken_at, id`,
+		platform, accountID, from.UnixNano(), to.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("could not list follower snapshots: %w", err)
+	}
+
+	var snapshotIDs []int64
+	var snapshots []domain.FollowerSnapshot
+	for rows.Next() {
+		var snapshotID int64
+		var takenAt int64
+		if err := rows.Scan(&snapshotID, &takenAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("could not list follower snapshots: %w", err)
+		}
+		snapshotIDs = append(snapshotIDs, snapshotID)
+		snapshots = append(snapshots, domain.FollowerSnapshot{
+			Platform:  platform,
+			AccountID: accountID,
+			TakenAt:   time.Unix(0, takenAt).UTC(),
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not list follower snapshots: %w", err)
+	}
+
+	for i, snapshotID := range snapshotIDs {
+		followerIDs, err := r.followerIDs(ctx, snapshotID)
+		if err != nil {
+			return nil, err
+		}
+		snapshots[i].FollowerIDs = followerIDs
+	}
+
+	return snapshots, nil
+}
+
+func (r *SQLiteFollowerSnapshotRepository) followerIDs(ctx context.Context, snapshotID int64) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT follower_id FROM follower_snapshot_followers WHERE snapshot_id = ? ORDER BY follower_id`, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve snapshot followers: %w", err)
+	}
+	defer rows.Close()
+
+	followerIDs := []string{}
+	for rows.Next() {
+		var followerID string
+		if err := rows.Scan(&followerID); err != nil {
+			return nil, fmt.Errorf("could not retrieve snapshot followers: %w", err)
+		}
+		followerIDs = append(followerIDs, followerID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not retrieve snapshot followers: %w", err)
+	}
+
+	return followerIDs, nil
+}
@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/mattn/go-sqlite3"
+)
+
+// SQLiteTrackedAccountRepository is a TrackedAccountRepository backed by a
+// SQLite database.
+type SQLiteTrackedAccountRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteTrackedAccountRepository creates a SQLiteTrackedAccountRepository
+// using the given database, creating the tables it needs if they do not exist.
+func NewSQLiteTrackedAccountRepository(db *sql.DB) (domain.TrackedAccountRepository, error) {
+	err := migrate(db,
+		`CREATE TABLE IF NOT EXISTS tracked_accounts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			platform TEXT NOT NULL,
+			platform_user_id TEXT NOT NULL,
+			username TEXT NOT NULL,
+			added_at INTEGER NOT NULL,
+			poll_enabled INTEGER NOT NULL,
+			poll_interval INTEGER NOT NULL,
+			UNIQUE (platform, platform_user_id)
+		)`,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQLiteTrackedAccountRepository{db: db}, nil
+}
+
+// Add stores the given account and assigns it an ID.
+func (r *SQLiteTrackedAccountRepository) Add(ctx context.Context, account *domain.TrackedAccount) error {
+	result, err := r.db.ExecContext(ctx, `INSERT INTO tracked_accounts
+		(platform, platform_user_id, username, added_at, poll_enabled, poll_interval)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		account.Platform, account.PlatformUserID, account.Username, account.AddedAt.UnixNano(),
+		account.Poll.Enabled, int64(account.Poll.Interval))
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return domain.ErrTrackedAccountExists
+		}
+		return fmt.Errorf("could not add tracked account: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("could not add tracked account: %w", err)
+	}
+	account.ID = id
+	account.AddedAt = account.AddedAt.UTC()
+
+	return nil
+}
+
+// Get returns the account with the given ID.
+func (r *SQLiteTrackedAccountRepository) Get(ctx context.Context, id int64) (*domain.TrackedAccount, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT id, platform, platform_user_id, username, added_at, poll_enabled, poll_interval
+		FROM tracked_accounts WHERE id = ?`, id)
+
+	account, err := scanTrackedAccount(row)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrTrackedAccountNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve tracked account: %w", err)
+	}
+
+	return account, nil
+}
+
+// List returns every stored account ordered by ID.
+func (r *SQLiteTrackedAccountRepository) List(ctx context.Context) ([]domain.TrackedAccount, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, platform, platform_user_id, username, added_at, poll_enabled, poll_interval
+		FROM tracked_accounts ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("could not list tracked accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := []domain.TrackedAccount{}
+	for rows.Next() {
+		account, err := scanTrackedAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not list tracked accounts: %w", err)
+		}
+		accounts = append(accounts, *account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not list tracked accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// Delete removes the account with the given ID.
+func (r *SQLiteTrackedAccountRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tracked_accounts WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("could not delete tracked account: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not delete tracked account: %w", err)
+	}
+	if deleted == 0 {
+		return domain.ErrTrackedAccountNotFound
+	}
+
+	return nil
+}
+
+// scanner is implemented by both *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTrackedAccount(row scanner) (*domain.TrackedAccount, error) {
+	var account domain.TrackedAccount
+	var addedAt int64
+	var pollInterval int64
+
+	err := row.Scan(&account.ID, &account.Platform, &account.PlatformUserID, &account.Username,
+		&addedAt, &account.Poll.Enabled, &pollInterval)
+	if err != nil {
+		return nil, err
+	}
+	account.AddedAt = time.Unix(0, addedAt).UTC()
+	account.Poll.Interval = domain.Duration(pollInterval)
+
+	return &account, nil
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+)
+
+// SQLiteTwitterConnectionRepository is a TwitterConnectionRepository backed by
+// a SQLite database.
+type SQLiteTwitterConnectionRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteTwitterConnectionRepository creates a SQLiteTwitterConnectionRepository
+// using the given database, creating the tables it needs if they do not exist.
+func NewSQLiteTwitterConnectionRepository(db *sql.DB) (domain.TwitterConnectionRepository, error) {
+	err := migrate(db,
+		`CREATE TABLE IF NOT EXISTS twitter_connections (
+			user_id TEXT PRIMARY KEY,
+			username TEXT NOT NULL,
+			access_token TEXT NOT NULL,
+			refresh_token TEXT NOT NULL,
+			expiry INTEGER NOT NULL,
+			scopes TEXT NOT NULL,
+			connected_at INTEGER NOT NULL
+		)`,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQLiteTwitterConnectionRepository{db: db}, nil
+}
+
+// Save stores the connection, replacing any connection of the same account.
+func (r *SQLiteTwitterConnectionRepository) Save(ctx context.Context, connection *domain.TwitterConnection) error {
+	var expiry int64
+	if !connection.Expiry.IsZero() {
+		expiry = connection.Expiry.UnixNano()
+	}
+
+	_, err := r.db.ExecContext(ctx, `INSERT OR REPLACE INTO twitter_connections
+		(user_id, username, access_token, refresh_token, expiry, scopes, connected_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		connection.UserID, connection.Username, connection.AccessToken, connection.RefreshToken,
+		expiry, strings.Join(connection.Scopes, " "), connection.ConnectedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("could not save Twitter connection: %w", err)
+	}
+
+	connection.Expiry = connection.Expiry.UTC()
+	connection.ConnectedAt = connection.ConnectedAt.UTC()
+
+	return nil
+}
+
+// Get returns the connection of the account with the given user ID.
+func (r *SQLiteTwitterConnectionRepository) Get(ctx context.Context, userID string) (*domain.TwitterConnection, error) {
+	var connection domain.TwitterConnection
+	var expiry, connectedAt int64
+	var scopes string
+
+	err := r.db.QueryRowContext(ctx, `SELECT user_id, username, access_token, refresh_token, expiry, scopes, connected_at
+		FROM twitter_connections WHERE user_id = ?`, userID).
+		Scan(&connection.UserID, &connection.Username, &connection.AccessToken, &connection.RefreshToken,
+			&expiry, &scopes, &connectedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrTwitterConnectionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve Twitter connection: %w", err)
+	}
+
+	if expiry != 0 {
+		connection.Expiry = time.Unix(0, expiry).UTC()
+	}
+	connection.Scopes = strings.Fields(scopes)
+	connection.ConnectedAt = time.Unix(0, connectedAt).UTC()
+
+	return &connection, nil
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/jake-hansen/followrs/domain"
+)
+
+// InMemoryTrackedAccountRepository is a TrackedAccountRepository that keeps
+// every account in memory. Accounts are lost when the program exits.
+type InMemoryTrackedAccountRepository struct {
+	mu       sync.RWMutex
+	lastID   int64
+	accounts map[int64]domain.TrackedAccount
+}
+
+// NewInMemoryTrackedAccountRepository creates an empty InMemoryTrackedAccountRepository.
+func NewInMemoryTrackedAccountRepository() domain.TrackedAccountRepository {
+	return &InMemoryTrackedAccountRepository{
+		accounts: make(map[int64]domain.TrackedAccount),
+	}
+}
+
+// Add stores the given account and assigns it an ID.
+func (r *InMemoryTrackedAccountRepository) Add(ctx context.Context, account *domain.TrackedAccount) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.accounts {
+		if existing.Platform == account.Platform && existing.PlatformUserID == account.PlatformUserID {
+			return domain.ErrTrackedAccountExists
+		}
+	}
+
+	r.lastID++
+	account.ID = r.lastID
+	account.AddedAt = account.AddedAt.UTC()
+	r.accounts[account.ID] = *account
+
+	return nil
+}
+
+// Get returns the account with the given ID.
+func (r *InMemoryTrackedAccountRepository) Get(ctx context.Context, id int64) (*domain.TrackedAccount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	account, ok := r.accounts[id]
+	if !ok {
+		return nil, domain.ErrTrackedAccountNotFound
+	}
+	return &account, nil
+}
+
+// List returns every stored account ordered by ID.
+func (r *InMemoryTrackedAccountRepository) List(ctx context.Context) ([]domain.TrackedAccount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	accounts := make([]domain.TrackedAccount, 0, len(r.accounts))
+	for _, account := range r.accounts {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].ID < accounts[j].ID
+	})
+
+	return accounts, nil
+}
+
+// Delete removes the account with the given ID.
+func (r *InMemoryTrackedAccountRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.accounts[id]; !ok {
+		return domain.ErrTrackedAccountNotFound
+	}
+	delete(r.accounts, id)
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories"
+)
+
+// trackedAccountRepositories returns a new, empty instance of every TrackedAccountRepository implementation.
+func trackedAccountRepositories(t *testing.T) map[string]domain.TrackedAccountRepository {
+	db, err := repositories.OpenSQLiteDatabase(":memory:")
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	sqliteRepo, err := repositories.NewSQLiteTrackedAccountRepository(db)
+	assert.NoError(t, err)
+
+	return map[string]domain.TrackedAccountRepository{
+		"in-memory": repositories.NewInMemoryTrackedAccountRepository(),
+		"sqlite":    sqliteRepo,
+	}
+}
+
+func TestTrackedAccountRepository(t *testing.T) {
+	for name, repo := range trackedAccountRepositories(t) {
+		t.Run(name, func(t *testing.T) {
+			first := &domain.TrackedAccount{
+				Platform:       "twitter",
+				PlatformUserID: "1",
+				Username:       "first",
+				AddedAt:        time.Now(),
+				Poll:           domain.PollSettings{Enabled: true, Interval: domain.Duration(time.Hour)},
+			}
+			second := &domain.TrackedAccount{
+				Platform:       "twitter",
+				PlatformUserID: "2",
+				Username:       "second",
+				AddedAt:        time.Now(),
+			}
+
+			assert.NoError(t, repo.Add(context.Background(), first))
+			assert.NoError(t, repo.Add(context.Background(), second))
+			assert.NotEqual(t, first.ID, second.ID)
+
+			err := repo.Add(context.Background(), &domain.TrackedAccount{Platform: "twitter", PlatformUserID: "1", Username: "renamed"})
+			assert.Equal(t, domain.ErrTrackedAccountExists, err)
+
+			account, err := repo.Get(context.Background(), first.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, first, account)
+
+			accounts, err := repo.List(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, []domain.TrackedAccount{*first, *second}, accounts)
+
+			assert.NoError(t, repo.Delete(context.Background(), first.ID))
+			assert.Equal(t, domain.ErrTrackedAccountNotFound, repo.Delete(context.Background(), first.ID))
+
+			_, err = repo.Get(context.Background(), first.ID)
+			assert.Equal(t, domain.ErrTrackedAccountNotFound, err)
+		})
+	}
+}
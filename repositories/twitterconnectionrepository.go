@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jake-hansen/followrs/domain"
+)
+
+// InMemoryTwitterConnectionRepository is a TwitterConnectionRepository that
+// keeps every connection in memory. Connections are lost when the program
+// exits.
+type InMemoryTwitterConnectionRepository struct {
+	mu          sync.RWMutex
+	connections map[string]domain.TwitterConnection
+}
+
+// NewInMemoryTwitterConnectionRepository creates an empty InMemoryTwitterConnectionRepository.
+func NewInMemoryTwitterConnectionRepository() domain.TwitterConnectionRepository {
+	return &InMemoryTwitterConnectionRepository{
+		connections: make(map[string]domain.TwitterConnection),
+	}
+}
+
+// Save stores the connection, replacing any connection of the same account.
+func (r *InMemoryTwitterConnectionRepository) Save(ctx context.Context, connection *domain.TwitterConnection) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	connection.Expiry = connection.Expiry.UTC()
+	connection.ConnectedAt = connection.ConnectedAt.UTC()
+	r.connections[connection.UserID] = copyConnection(*connection)
+
+	return nil
+}
+
+// Get returns the connection of the account with the given user ID.
+func (r *InMemoryTwitterConnectionRepository) Get(ctx context.Context, userID string) (*domain.TwitterConnection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	connection, ok := r.connections[userID]
+	if !ok {
+		return nil, domain.ErrTwitterConnectionNotFound
+	}
+	connection = copyConnection(connection)
+	return &connection, nil
+}
+
+// copyConnection returns a copy of the connection that shares no memory with it.
+func copyConnection(connection domain.TwitterConnection) domain.TwitterConnection {
+	connection.Scopes = append([]string(nil), connection.Scopes...)
+	return connection
+}
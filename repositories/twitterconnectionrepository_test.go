@@ -0,0 +1,60 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories"
+)
+
+// twitterConnectionRepositories returns a new, empty instance of every TwitterConnectionRepository implementation.
+func twitterConnectionRepositories(t *testing.T) map[string]domain.TwitterConnectionRepository {
+	db, err := repositories.OpenSQLiteDatabase(":memory:")
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	sqliteRepo, err := repositories.NewSQLiteTwitterConnectionRepository(db)
+	assert.NoError(t, err)
+
+	return map[string]domain.TwitterConnectionRepository{
+		"in-memory": repositories.NewInMemoryTwitterConnectionRepository(),
+		"sqlite":    sqliteRepo,
+	}
+}
+
+func TestTwitterConnectionRepository(t *testing.T) {
+	for name, repo := range twitterConnectionRepositories(t) {
+		t.Run(name, func(t *testing.T) {
+			connection := &domain.TwitterConnection{
+				UserID:       "1",
+				Username:     "test",
+				AccessToken:  "access",
+				RefreshToken: "refresh",
+				Expiry:       time.Now().Add(time.Hour),
+				Scopes:       []string{"users.read", "offline.access"},
+				ConnectedAt:  time.Now(),
+			}
+
+			_, err := repo.Get(context.Background(), "1")
+			assert.Equal(t, domain.ErrTwitterConnectionNotFound, err)
+
+			assert.NoError(t, repo.Save(context.Background(), connection))
+			stored, err := repo.Get(context.Background(), "1")
+			assert.NoError(t, err)
+			assert.Equal(t, connection, stored)
+
+			refreshed := *connection
+			refreshed.AccessToken = "refreshed"
+			refreshed.Expiry = time.Time{}
+			assert.NoError(t, repo.Save(context.Background(), &refreshed))
+			stored, err = repo.Get(context.Background(), "1")
+			assert.NoError(t, err)
+			assert.Equal(t, "refreshed", stored.AccessToken)
+			assert.True(t, stored.Expiry.IsZero())
+		})
+	}
+}
@@ -0,0 +1,315 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/domain"
+)
+
+// defaultSyncInterval is how often the Scheduler checks for changes to the
+// tracked accounts when none is configured.
+const defaultSyncInterval = time.Minute
+
+// twitterPlatform is the name of the platform of Twitter accounts, whose
+// followers are recorded along with their metrics.
+const twitterPlatform = "twitter"
+
+// Jobs are keyed by where their account was configured, so that tracked
+// accounts can be synchronized without affecting configured accounts.
+const (
+	configKeyPrefix  = "config/"
+	trackedKeyPrefix = "tracked/"
+)
+
+// Account configures how often the followers of an account are polled.
+// Accounts with an ID are looked up by it, so that they are still polled after
+// being renamed; Username is then only used to describe them, and to look up
+// accounts of platforms whose Provider can't look accounts up by ID. Only the
+// metrics of accounts of platforms other than Twitter are polled.
+type Account struct {
+	Platform string        `mapstructure:"platform"` // Platform of the account. Accounts without one are on Twitter.
+	ID       string        `mapstructure:"id"`
+	Username string        `mapstructure:"username"`
+	Interval time.Duration `mapstructure:"interval"` // Time between polls. The Scheduler's default is used when zero.
+}
+
+// isTwitter determines if the account is on Twitter.
+func (a Account) isTwitter() bool {
+	return a.Platform == "" || a.Platform == twitterPlatform
+}
+
+// key identifies the account among the configured accounts by its platform
+// and ID, or its username if it has no ID, so that accounts configured only by
+// ID, or with the same username on different platforms, don't collide.
+func (a Account) key() string {
+	platform := a.Platform
+	if platform == "" {
+		platform = twitterPlatform
+	}
+	if a.ID != "" {
+		return fmt.Sprintf("%s/id/%s", platform, a.ID)
+	}
+	return fmt.Sprintf("%s/username/%s", platform, a.Username)
+}
+
+// retryAfterError is implemented by errors that know when the failed request
+// can be retried, such as those returned when a rate limit is exhausted.
+type retryAfterError interface {
+	RetryAfter() time.Duration
+}
+
+// job tracks when an account is next due to be polled.
+type job struct {
+	account Account
+	next    time.Time
+}
+
+// Scheduler periodically records a snapshot of the followers of each of its
+// Twitter accounts, and the metrics of each of its other accounts. Polls of
+// Twitter accounts are deferred, rather than attempted, while the followers
+// lookup is rate limited.
+type Scheduler struct {
+	DiffService    domain.FollowerDiffService
+	TwitterService domain.TwitterService
+
+	// MetricsService records the metrics of accounts that aren't on Twitter.
+	// Those accounts aren't polled if it is nil.
+	MetricsService domain.AccountMetricsService
+
+	// AccountService provides the tracked accounts to poll in addition to the
+	// configured accounts. Tracked accounts are reloaded every SyncInterval.
+	AccountService domain.TrackedAccountService
+	SyncInterval   time.Duration
+
+	// PollContext, if set, derives the context that each poll is made with,
+	// such as to make polls wait out exhausted rate limits.
+	PollContext func(ctx context.Context) context.Context
+
+	defaultInterval time.Duration
+	jobs            map[string]*job
+	now             func() time.Time
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	stopped chan struct{}
+}
+
+// NewScheduler creates a Scheduler that polls each of the given accounts and
+// every enabled account tracked by accountService, which may be nil, as may
+// metricsService. Accounts without an interval are polled every
+// defaultInterval.
+func NewScheduler(diffService domain.FollowerDiffService, twitterService domain.TwitterService, accountService domain.TrackedAccountService, metricsService domain.AccountMetricsService, defaultInterval time.Duration, accounts []Account) *Scheduler {
+	s := &Scheduler{
+		DiffService:     diffService,
+		TwitterService:  twitterService,
+		MetricsService:  metricsService,
+		AccountService:  accountService,
+		SyncInterval:    defaultSyncInterval,
+		defaultInterval: defaultInterval,
+		jobs:            make(map[string]*job),
+		now:             time.Now,
+	}
+
+	for _, account := range accounts {
+		s.schedule(configKeyPrefix+account.key(), account)
+	}
+
+	return s
+}
+
+// Start begins polling in the background. Calling Start on a Scheduler that
+// is already running has no effect.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.stopped = make(chan struct{})
+
+	go s.run(ctx, s.stopped)
+}
+
+// Stop stops polling, cancelling any poll in progress, and waits for the
+// Scheduler to finish.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel, stopped := s.cancel, s.stopped
+	s.cancel, s.stopped = nil, nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-stopped
+}
+
+func (s *Scheduler) run(ctx context.Context, stopped chan<- struct{}) {
+	defer close(stopped)
+
+	nextSync := s.now()
+	for {
+		if s.AccountService != nil && !s.now().Before(nextSync) {
+			s.syncTrackedAccounts(ctx)
+			nextSync = s.now().Add(s.SyncInterval)
+		}
+
+		wake := s.nextDue()
+		if s.AccountService != nil && (wake.IsZero() || nextSync.Before(wake)) {
+			wake = nextSync
+		}
+
+		var timer *time.Timer
+		var wait <-chan time.Time
+		if !wake.IsZero() {
+			timer = time.NewTimer(wake.Sub(s.now()))
+			wait = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-wait:
+		}
+
+		for _, j := range s.jobs {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if !s.now().Before(j.next) {
+				s.poll(ctx, j)
+			}
+		}
+	}
+}
+
+// schedule adds a job for the account under the given key, or updates the
+// job already scheduled under that key. Accounts that can't be polled, since
+// they aren't on Twitter and there is no MetricsService, are skipped.
+func (s *Scheduler) schedule(key string, account Account) {
+	if !account.isTwitter() && s.MetricsService == nil {
+		return
+	}
+	if account.Interval <= 0 {
+		account.Interval = s.defaultInterval
+	}
+
+	if existing, ok := s.jobs[key]; ok {
+		existing.account = account
+		return
+	}
+	s.jobs[key] = &job{account: account, next: s.now()}
+}
+
+// syncTrackedAccounts schedules a job for every enabled tracked account and
+// removes the jobs of accounts that are no longer tracked or enabled.
+func (s *Scheduler) syncTrackedAccounts(ctx context.Context) {
+	accounts, err := s.AccountService.List(ctx)
+	if err != nil {
+		log.Printf("scheduler: could not load tracked accounts: %s", err.Error())
+		return
+	}
+
+	tracked := make(map[string]bool, len(accounts))
+	for _, account := range accounts {
+		if !account.Poll.Enabled {
+			continue
+		}
+		key := fmt.Sprintf("%s%d", trackedKeyPrefix, account.ID)
+		tracked[key] = true
+		s.schedule(key, Account{
+			Platform: account.Platform,
+			ID:       account.PlatformUserID,
+			Username: account.Username,
+			Interval: time.Duration(account.Poll.Interval),
+		})
+	}
+
+	for key := range s.jobs {
+		if strings.HasPrefix(key, trackedKeyPrefix) && !tracked[key] {
+			delete(s.jobs, key)
+		}
+	}
+}
+
+// nextDue returns the earliest time a job is due to be polled, or the zero
+// time if there are no jobs.
+func (s *Scheduler) nextDue() time.Time {
+	var next time.Time
+	for _, j := range s.jobs {
+		if next.IsZero() || j.next.Before(next) {
+			next = j.next
+		}
+	}
+	return next
+}
+
+// poll records a snapshot, or the metrics, of the job's account and schedules
+// its next poll. If the followers lookup, or any other request the poll
+// makes, is rate limited, the poll is deferred until the rate limit resets.
+func (s *Scheduler) poll(ctx context.Context, j *job) {
+	username := j.account.Username
+	twitter := j.account.isTwitter()
+
+	if twitter {
+		if rateLimit := s.TwitterService.GetFollowersRateLimit(); rateLimit.Exhausted(s.now()) {
+			log.Printf("scheduler: deferring poll of %s until %s, rate limit reached", username, rateLimit.Reset.Format(time.RFC3339))
+			j.next = rateLimit.Reset
+			return
+		}
+	}
+
+	pollCtx := ctx
+	if s.PollContext != nil {
+		pollCtx = s.PollContext(ctx)
+	}
+
+	var err error
+	switch {
+	case !twitter && j.account.ID != "":
+		_, err = s.MetricsService.RecordByID(pollCtx, j.account.Platform, j.account.ID, username)
+	case !twitter:
+		_, err = s.MetricsService.Record(pollCtx, j.account.Platform, username)
+	case j.account.ID != "":
+		_, err = s.DiffService.RecordSnapshotByID(pollCtx, j.account.ID)
+	default:
+		_, err = s.DiffService.RecordSnapshot(pollCtx, username)
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		var retryable retryAfterError
+		if errors.As(err, &retryable) && retryable.RetryAfter() > 0 {
+			next := s.now().Add(retryable.RetryAfter())
+			log.Printf("scheduler: deferring poll of %s until %s, rate limit reached", username, next.Format(time.RFC3339))
+			j.next = next
+			return
+		}
+		if twitter && errors.Is(err, apperrors.ErrRateLimited) {
+			if rateLimit := s.TwitterService.GetFollowersRateLimit(); rateLimit.Reset.After(s.now()) {
+				log.Printf("scheduler: deferring poll of %s until %s, rate limit reached", username, rateLimit.Reset.Format(time.RFC3339))
+				j.next = rateLimit.Reset
+				return
+			}
+		}
+		log.Printf("scheduler: could not poll %s: %s", username, err.Error())
+	}
+
+	j.next = s.now().Add(j.account.Interval)
+}
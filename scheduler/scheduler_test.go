@@ -0,0 +1,245 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories"
+	"github.com/jake-hansen/followrs/repositories/apis"
+	"github.com/jake-hansen/followrs/scheduler"
+	"github.com/jake-hansen/followrs/services"
+	"github.com/jake-hansen/followrs/services/mocks"
+)
+
+func TestScheduler(t *testing.T) {
+	t.Run("polls-each-interval", func(t *testing.T) {
+		polled := make(chan string, 10)
+		diffService := new(mocks.FollowerDiffService)
+		diffService.On("RecordSnapshot", mock.Anything, "test").Return(&domain.FollowerSnapshot{}, nil).Run(func(args mock.Arguments) {
+			polled <- args.String(1)
+		})
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetFollowersRateLimit").Return(domain.RateLimit{Remaining: 15})
+
+		s := scheduler.NewScheduler(diffService, twitterService, nil, nil, time.Millisecond, []scheduler.Account{{Username: "test"}})
+		s.Start()
+		defer s.Stop()
+
+		for i := 0; i < 3; i++ {
+			select {
+			case username := <-polled:
+				assert.Equal(t, "test", username)
+			case <-time.After(time.Second):
+				t.Fatal("account was not polled")
+			}
+		}
+	})
+
+	t.Run("configured-accounts-keyed-by-platform-and-id", func(t *testing.T) {
+		polled := make(chan string, 10)
+		diffService := new(mocks.FollowerDiffService)
+		diffService.On("RecordSnapshotByID", mock.Anything, mock.Anything).Return(&domain.FollowerSnapshot{}, nil).Run(func(args mock.Arguments) {
+			polled <- args.String(1)
+		})
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetFollowersRateLimit").Return(domain.RateLimit{Remaining: 15})
+		metricsService := new(mocks.AccountMetricsService)
+		metricsService.On("RecordByID", mock.Anything, "bluesky", mock.Anything, mock.Anything).Return(&domain.AccountMetrics{}, nil).Run(func(args mock.Arguments) {
+			polled <- args.String(2)
+		})
+
+		accounts := []scheduler.Account{{ID: "1"}, {ID: "2"}, {Platform: "bluesky", ID: "1"}}
+		s := scheduler.NewScheduler(diffService, twitterService, nil, metricsService, time.Hour, accounts)
+		s.Start()
+		defer s.Stop()
+
+		var ids []string
+		for i := 0; i < len(accounts); i++ {
+			select {
+			case id := <-polled:
+				ids = append(ids, id)
+			case <-time.After(time.Second):
+				t.Fatal("account was not polled")
+			}
+		}
+		assert.ElementsMatch(t, []string{"1", "2", "1"}, ids)
+		diffService.AssertNumberOfCalls(t, "RecordSnapshotByID", 2)
+		metricsService.AssertNumberOfCalls(t, "RecordByID", 1)
+	})
+
+	t.Run("rate-limited-poll-deferred", func(t *testing.T) {
+		diffService := new(mocks.FollowerDiffService)
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetFollowersRateLimit").Return(domain.RateLimit{Remaining: 0, Reset: time.Now().Add(time.Hour)})
+
+		s := scheduler.NewScheduler(diffService, twitterService, nil, nil, time.Millisecond, []scheduler.Account{{Username: "test"}})
+		s.Start()
+		time.Sleep(50 * time.Millisecond)
+		s.Stop()
+
+		twitterService.AssertCalled(t, "GetFollowersRateLimit")
+		diffService.AssertNotCalled(t, "RecordSnapshot", mock.Anything, "test")
+	})
+
+	t.Run("polls-tracked-accounts", func(t *testing.T) {
+		polled := make(chan string, 10)
+		diffService := new(mocks.FollowerDiffService)
+		diffService.On("RecordSnapshotByID", mock.Anything, mock.Anything).Return(&domain.FollowerSnapshot{}, nil).Run(func(args mock.Arguments) {
+			polled <- args.String(1)
+		})
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetFollowersRateLimit").Return(domain.RateLimit{Remaining: 15})
+
+		accountRepo := repositories.NewInMemoryTrackedAccountRepository()
+		accountRepo.Add(context.Background(), &domain.TrackedAccount{Platform: "twitter", PlatformUserID: "1", Username: "enabled", Poll: domain.PollSettings{Enabled: true, Interval: domain.Duration(time.Hour)}})
+		accountRepo.Add(context.Background(), &domain.TrackedAccount{Platform: "twitter", PlatformUserID: "2", Username: "disabled"})
+		accountService := services.NewTrackedAccountService(accountRepo, twitterService, services.NewProviderRegistry())
+
+		s := scheduler.NewScheduler(diffService, twitterService, accountService, nil, time.Hour, nil)
+		s.Start()
+		defer s.Stop()
+
+		select {
+		case id := <-polled:
+			assert.Equal(t, "1", id)
+		case <-time.After(time.Second):
+			t.Fatal("tracked account was not polled")
+		}
+		select {
+		case id := <-polled:
+			t.Fatalf("unexpected poll of %s", id)
+		case <-time.After(50 * time.Millisecond):
+		}
+		diffService.AssertNotCalled(t, "RecordSnapshot", mock.Anything, mock.Anything)
+	})
+
+	t.Run("polls-metrics-of-other-platforms-by-id", func(t *testing.T) {
+		polled := make(chan string, 10)
+		diffService := new(mocks.FollowerDiffService)
+		twitterService := new(mocks.TwitterService)
+		metricsService := new(mocks.AccountMetricsService)
+		metricsService.On("RecordByID", mock.Anything, "youtube", "UC1", "@test").Return(&domain.AccountMetrics{}, nil).Run(func(args mock.Arguments) {
+			polled <- args.String(2)
+		})
+
+		accountRepo := repositories.NewInMemoryTrackedAccountRepository()
+		accountRepo.Add(context.Background(), &domain.TrackedAccount{Platform: "youtube", PlatformUserID: "UC1", Username: "@test", Poll: domain.PollSettings{Enabled: true}})
+		accountService := services.NewTrackedAccountService(accountRepo, twitterService, services.NewProviderRegistry())
+
+		s := scheduler.NewScheduler(diffService, twitterService, accountService, metricsService, time.Hour, nil)
+		s.Start()
+		defer s.Stop()
+
+		select {
+		case id := <-polled:
+			assert.Equal(t, "UC1", id)
+		case <-time.After(time.Second):
+			t.Fatal("tracked account was not polled")
+		}
+		metricsService.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything)
+		diffService.AssertNotCalled(t, "RecordSnapshotByID", mock.Anything, mock.Anything)
+		twitterService.AssertNotCalled(t, "GetFollowersRateLimit")
+	})
+
+	t.Run("other-platforms-skipped-without-metrics-service", func(t *testing.T) {
+		diffService := new(mocks.FollowerDiffService)
+		twitterService := new(mocks.TwitterService)
+
+		s := scheduler.NewScheduler(diffService, twitterService, nil, nil, time.Millisecond, []scheduler.Account{{Platform: "youtube", Username: "@test"}})
+		s.Start()
+		time.Sleep(20 * time.Millisecond)
+		s.Stop()
+
+		twitterService.AssertNotCalled(t, "GetFollowersRateLimit")
+	})
+
+	t.Run("poll-deferred-after-rate-limit-reached", func(t *testing.T) {
+		reset := time.Now().Add(time.Hour)
+		rateLimits := []domain.RateLimit{{Remaining: 15}, {Remaining: 0, Reset: reset}}
+		diffService := new(mocks.FollowerDiffService)
+		diffService.On("RecordSnapshot", mock.Anything, "test").Return(nil, fmt.Errorf("could not poll: %w", apperrors.ErrRateLimited)).Once()
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetFollowersRateLimit").Return(rateLimits[0]).Once()
+		twitterService.On("GetFollowersRateLimit").Return(rateLimits[1])
+
+		s := scheduler.NewScheduler(diffService, twitterService, nil, nil, time.Millisecond, []scheduler.Account{{Username: "test"}})
+		s.Start()
+		time.Sleep(50 * time.Millisecond)
+		s.Stop()
+
+		diffService.AssertNumberOfCalls(t, "RecordSnapshot", 1)
+	})
+
+	t.Run("poll-deferred-until-rate-limit-error-reset", func(t *testing.T) {
+		rateLimitError := &apis.RateLimitError{
+			Key:   apis.RateLimitKey{Endpoint: "GET /users/by/username/:username"},
+			Reset: time.Now().Add(time.Hour),
+		}
+		diffService := new(mocks.FollowerDiffService)
+		diffService.On("RecordSnapshot", mock.Anything, "test").Return(nil, fmt.Errorf("could not poll: %w", rateLimitError))
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetFollowersRateLimit").Return(domain.RateLimit{Remaining: 15})
+
+		s := scheduler.NewScheduler(diffService, twitterService, nil, nil, time.Millisecond, []scheduler.Account{{Username: "test"}})
+		s.Start()
+		time.Sleep(50 * time.Millisecond)
+		s.Stop()
+
+		diffService.AssertNumberOfCalls(t, "RecordSnapshot", 1)
+	})
+
+	t.Run("poll-made-with-poll-context", func(t *testing.T) {
+		type contextKey struct{}
+		polled := make(chan context.Context, 10)
+		diffService := new(mocks.FollowerDiffService)
+		diffService.On("RecordSnapshot", mock.Anything, "test").Return(&domain.FollowerSnapshot{}, nil).Run(func(args mock.Arguments) {
+			polled <- args.Get(0).(context.Context)
+		})
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetFollowersRateLimit").Return(domain.RateLimit{Remaining: 15})
+
+		s := scheduler.NewScheduler(diffService, twitterService, nil, nil, time.Hour, []scheduler.Account{{Username: "test"}})
+		s.PollContext = func(ctx context.Context) context.Context {
+			return context.WithValue(ctx, contextKey{}, "value")
+		}
+		s.Start()
+		defer s.Stop()
+
+		select {
+		case ctx := <-polled:
+			assert.Equal(t, "value", ctx.Value(contextKey{}))
+		case <-time.After(time.Second):
+			t.Fatal("account was not polled")
+		}
+	})
+
+	t.Run("failed-poll-retried-next-interval", func(t *testing.T) {
+		polled := make(chan string, 10)
+		diffService := new(mocks.FollowerDiffService)
+		diffService.On("RecordSnapshot", mock.Anything, "test").Return(nil, errors.New("example error")).Run(func(args mock.Arguments) {
+			polled <- args.String(1)
+		})
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetFollowersRateLimit").Return(domain.RateLimit{Remaining: 15})
+
+		s := scheduler.NewScheduler(diffService, twitterService, nil, nil, time.Millisecond, []scheduler.Account{{Username: "test"}})
+		s.Start()
+		defer s.Stop()
+
+		for i := 0; i < 2; i++ {
+			select {
+			case <-polled:
+			case <-time.After(time.Second):
+				t.Fatal("account was not polled again after a failure")
+			}
+		}
+	})
+}
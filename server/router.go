@@ -1,31 +1,95 @@
 package server
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
+
 	"github.com/jake-hansen/followrs/config"
 	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories/apis"
+	"github.com/jake-hansen/followrs/repositories/apis/bluesky"
+	"github.com/jake-hansen/followrs/repositories/apis/github"
+	"github.com/jake-hansen/followrs/repositories/apis/mastodon"
+	"github.com/jake-hansen/followrs/repositories/apis/twitch"
 	"github.com/jake-hansen/followrs/repositories/apis/twitter"
+	"github.com/jake-hansen/followrs/repositories/apis/youtube"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jake-hansen/followrs/handlers"
 	"github.com/jake-hansen/followrs/middleware"
 	"github.com/jake-hansen/followrs/repositories"
+	"github.com/jake-hansen/followrs/scheduler"
 	"github.com/jake-hansen/followrs/services"
 )
 
+// twitterBaseURL is the URL of version 2 of the Twitter API.
+const twitterBaseURL = "https://api.twitter.com/2"
+
+// Dependencies contains the services shared by the router and the
+// background scheduler.
+type Dependencies struct {
+	TwitterService          *domain.TwitterService
+	FollowerDiffService     domain.FollowerDiffService
+	TrackedAccountService   domain.TrackedAccountService
+	AccountMetricsService   domain.AccountMetricsService
+	AccountAnalyticsService domain.AccountAnalyticsService
+	Providers               domain.ProviderRegistry
+
+	// TwitterConnectService is nil unless an OAuth 2.0 client is configured
+	// by secrets.twitter.oauth2.client_id.
+	TwitterConnectService domain.TwitterConnectService
+}
+
+// NewDependencies creates the services configured for this instance of the
+// program.
+func NewDependencies() *Dependencies {
+	db := openDatabase()
+	twitterAPI := createTwitterAPI()
+	twitterService := createTwitterService(twitterAPI)
+	accountRepo := createTrackedAccountRepository(db)
+	metricsRepo := createAccountMetricsRepository(db)
+	providers := services.NewProviderRegistry(
+		services.NewTwitterProvider(*twitterService),
+		services.NewMastodonProvider(createMastodonAPI()),
+		services.NewBlueskyProvider(createBlueskyAPI()),
+		services.NewGitHubProvider(createGitHubAPI()),
+		services.NewYouTubeProvider(createYouTubeAPI()),
+		services.NewTwitchProvider(createTwitchAPI()),
+	)
+	metricsService := services.NewAccountMetricsService(accountRepo, metricsRepo, providers)
+
+	return &Dependencies{
+		TwitterService:          twitterService,
+		FollowerDiffService:     services.NewFollowerDiffService(*twitterService, createFollowerSnapshotRepository(db), metricsRepo),
+		TrackedAccountService:   services.NewTrackedAccountService(accountRepo, *twitterService, providers),
+		AccountMetricsService:   metricsService,
+		AccountAnalyticsService: services.NewAccountAnalyticsService(metricsService),
+		TwitterConnectService:   createTwitterConnectService(db, twitterAPI, *twitterService),
+		Providers:               providers,
+	}
+}
+
 // NewRouter returns a router configured with handlers for configured
 // endpoints.
-func NewRouter(env string, startTime time.Time) *gin.Engine {
+func NewRouter(env string, startTime time.Time, deps *Dependencies) *gin.Engine {
 	setGinEnvironment(env)
 	router := gin.New()
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
-	router.Use(middleware.PublicErrorHandler())
+	router.Use(middleware.PublicErrorHandlerWithFormat(middleware.ErrorFormat(config.GetConfig().GetString("server.errors.format"))))
 
 	v1 := router.Group("v1")
 	handlers.NewHealthHandler(v1, services.NewSimpleHealthService(repositories.NewSimpleHealthRepository(startTime)))
 
-	handlers.NewUsersHandler(v1, createTwitterService())
+	if deps.TwitterConnectService != nil {
+		handlers.NewConnectHandler(v1, deps.TwitterConnectService)
+		v1.Use(middleware.TwitterUser(deps.TwitterConnectService))
+	}
+
+	handlers.NewUsersHandler(v1, deps.TwitterService, deps.FollowerDiffService, deps.Providers)
+	handlers.NewAccountsHandler(v1, deps.TrackedAccountService, deps.AccountMetricsService, deps.AccountAnalyticsService, config.GetConfig().GetDuration("scheduler.min_interval"))
 
 	return router
 }
@@ -36,16 +100,275 @@ func setGinEnvironment(env string) {
 	}
 }
 
-func createTwitterService() *domain.TwitterService {
+// createTwitterAPI creates a Twitter API client that authenticates as
+// configured by apis.twitter.auth: "app" for app-only authentication, or
+// "user" to sign requests with OAuth 1.0a on behalf of the user that the
+// configured access token belongs to.
+func createTwitterAPI() *twitter.API {
 	config := config.GetConfig()
 	apiKey := config.GetString("secrets.twitter.api.key")
 	apiSecretKey := config.GetString("secrets.twitter.api.secret")
-	apiBearerToken := config.GetString("secrets.twitter.api.bearer")
 
-	twitterRepo, _ := twitter.NewTwitterAPI("https://api.twitter.com/2", apiKey, apiSecretKey, apiBearerToken)
-	repoPtr := domain.TwitterRepository(twitterRepo)
+	var twitterRepo *twitter.API
+	var err error
+	switch mode := config.GetString("apis.twitter.auth"); mode {
+	case "app":
+		apiBearerToken := config.GetString("secrets.twitter.api.bearer")
+		twitterRepo, err = twitter.NewTwitterAPI(twitterBaseURL, apiKey, apiSecretKey, apiBearerToken)
+	case "user":
+		auth := apis.NewOAuth1Auth(apiKey, apiSecretKey,
+			config.GetString("secrets.twitter.api.access_token"),
+			config.GetString("secrets.twitter.api.access_token_secret"))
+		twitterRepo, err = twitter.NewTwitterAPIWithAuth(twitterBaseURL, auth)
+	default:
+		err = fmt.Errorf("unknown authentication mode %q", mode)
+	}
+	if err != nil {
+		panic(fmt.Errorf("could not create Twitter API: %w", err))
+	}
+
+	twitterRepo.Client.Timeout = config.GetDuration("apis.timeout")
+	twitterRepo.Client.RateLimitPolicy = rateLimitPolicy("apis.rate_limit.policy")
+	twitterRepo.Client.RetryPolicy.MaxWait = retryMaxWait()
+
+	return twitterRepo
+}
+
+// createMastodonAPI creates a Mastodon API client that looks up accounts
+// without a domain on apis.mastodon.instance, and authenticates requests to
+// each instance with the access token for its domain in
+// secrets.mastodon.access_tokens, if there is one.
+func createMastodonAPI() *mastodon.API {
+	config := config.GetConfig()
+	mastodonAPI := mastodon.NewMastodonAPI(config.GetString("apis.mastodon.instance"),
+		config.GetStringMapString("secrets.mastodon.access_tokens"))
+
+	timeout := config.GetDuration("apis.timeout")
+	policy := rateLimitPolicy("apis.rate_limit.policy")
+	maxWait := retryMaxWait()
+	mastodonAPI.Configure = func(instance *apis.API) {
+		instance.Timeout = timeout
+		instance.RateLimitPolicy = policy
+		instance.RetryPolicy.MaxWait = maxWait
+	}
+
+	return mastodonAPI
+}
+
+// createBlueskyAPI creates a Bluesky API client that makes requests as the
+// account configured by secrets.bluesky, through the server at
+// apis.bluesky.url. Without an account, requests are made anonymously
+// through the server at apis.bluesky.public_url.
+func createBlueskyAPI() *bluesky.API {
+	config := config.GetConfig()
+	identifier := config.GetString("secrets.bluesky.identifier")
+	password := config.GetString("secrets.bluesky.app_password")
+
+	baseURL := config.GetString("apis.bluesky.url")
+	if identifier == "" || password == "" {
+		baseURL = config.GetString("apis.bluesky.public_url")
+	}
+
+	blueskyAPI, err := bluesky.NewBlueskyAPI(baseURL, identifier, password)
+	if err != nil {
+		panic(fmt.Errorf("could not create Bluesky API: %w", err))
+	}
+
+	blueskyAPI.Client.Timeout = config.GetDuration("apis.timeout")
+	blueskyAPI.Client.RateLimitPolicy = rateLimitPolicy("apis.rate_limit.policy")
+	blueskyAPI.Client.RetryPolicy.MaxWait = retryMaxWait()
+
+	return blueskyAPI
+}
+
+// createGitHubAPI creates a GitHub API client for apis.github.url that
+// authenticates with the personal access token secrets.github.token, or makes
+// requests anonymously if there is none.
+func createGitHubAPI() *github.API {
+	config := config.GetConfig()
+	githubAPI, err := github.NewGitHubAPI(config.GetString("apis.github.url"), config.GetString("secrets.github.token"))
+	if err != nil {
+		panic(fmt.Errorf("could not create GitHub API: %w", err))
+	}
+
+	githubAPI.Client.Timeout = config.GetDuration("apis.timeout")
+	githubAPI.Client.RateLimitPolicy = rateLimitPolicy("apis.rate_limit.policy")
+	githubAPI.Client.RetryPolicy.MaxWait = retryMaxWait()
+
+	return githubAPI
+}
+
+// createYouTubeAPI creates a YouTube API client for apis.youtube.url that
+// authenticates with secrets.youtube.api_key and spends up to
+// apis.youtube.daily_quota quota units each day. YouTube limits requests by
+// their daily quota rather than by window, so apis.rate_limit.policy doesn't
+// apply; requests fail once the quota is exhausted.
+func createYouTubeAPI() *youtube.API {
+	config := config.GetConfig()
+	youtubeAPI, err := youtube.NewYouTubeAPI(config.GetString("apis.youtube.url"), config.GetString("secrets.youtube.api_key"), config.GetInt64("apis.youtube.daily_quota"))
+	if err != nil {
+		panic(fmt.Errorf("could not create YouTube API: %w", err))
+	}
+
+	youtubeAPI.Client.Timeout = config.GetDuration("apis.timeout")
+	youtubeAPI.Client.RetryPolicy.MaxWait = retryMaxWait()
+
+	return youtubeAPI
+}
+
+// createTwitchAPI creates a Twitch API client for apis.twitch.url that
+// obtains app access tokens from apis.twitch.token_url with
+// secrets.twitch.client_id and secrets.twitch.client_secret.
+func createTwitchAPI() *twitch.API {
+	config := config.GetConfig()
+	twitchAPI, err := twitch.NewTwitchAPI(config.GetString("apis.twitch.url"), config.GetString("apis.twitch.token_url"), config.GetString("secrets.twitch.client_id"), config.GetString("secrets.twitch.client_secret"))
+	if err != nil {
+		panic(fmt.Errorf("could not create Twitch API: %w", err))
+	}
+
+	twitchAPI.Client.Timeout = config.GetDuration("apis.timeout")
+	twitchAPI.Client.RateLimitPolicy = rateLimitPolicy("apis.rate_limit.policy")
+	twitchAPI.Client.RetryPolicy.MaxWait = retryMaxWait()
+
+	return twitchAPI
+}
+
+func createTwitterService(twitterRepo *twitter.API) *domain.TwitterService {
+	repoPtr := services.TwitterRepository(twitterRepo)
 
 	service := services.NewTwitterService(&repoPtr)
 
 	return &service
 }
+
+// createTwitterConnectService creates a TwitterConnectService that lets users
+// connect their Twitter accounts with the OAuth 2.0 client configured by
+// secrets.twitter.oauth2, or returns nil if no client is configured. The
+// sessions of users who connected are signed with secrets.session.key, which
+// must be configured along with the client.
+func createTwitterConnectService(db *sql.DB, twitterAPI *twitter.API, twitterService domain.TwitterService) domain.TwitterConnectService {
+	config := config.GetConfig()
+	clientID := config.GetString("secrets.twitter.oauth2.client_id")
+	if clientID == "" {
+		return nil
+	}
+
+	sessionKey := config.GetString("secrets.session.key")
+	if sessionKey == "" {
+		panic(fmt.Errorf("could not create Twitter connect service: secrets.session.key is required"))
+	}
+
+	authorizer := twitter.NewOAuth2Client(twitterAPI, clientID,
+		config.GetString("secrets.twitter.oauth2.client_secret"),
+		config.GetString("apis.twitter.oauth2.redirect_url"),
+		config.GetStringSlice("apis.twitter.oauth2.scopes"))
+
+	return services.NewTwitterConnectService(authorizer, createTwitterConnectionRepository(db), twitterService, []byte(sessionKey))
+}
+
+// rateLimitPolicy returns the rate limit policy configured by the given key,
+// panicking if it is unknown.
+func rateLimitPolicy(key string) apis.RateLimitPolicy {
+	policy, err := apis.ParseRateLimitPolicy(config.GetConfig().GetString(key))
+	if err != nil {
+		panic(fmt.Errorf("could not read %s: %w", key, err))
+	}
+	return policy
+}
+
+// retryMaxWait returns the longest that requests wait for a rate limit to
+// reset, as configured by apis.retry.max_wait, panicking if it isn't shorter
+// than apis.timeout, which would end every wait before it could be retried.
+func retryMaxWait() time.Duration {
+	config := config.GetConfig()
+	maxWait, timeout := config.GetDuration("apis.retry.max_wait"), config.GetDuration("apis.timeout")
+	if timeout > 0 && maxWait >= timeout {
+		panic(fmt.Errorf("apis.retry.max_wait (%s) must be shorter than apis.timeout (%s)", maxWait, timeout))
+	}
+	return maxWait
+}
+
+// createScheduler creates a scheduler that polls the accounts configured by
+// scheduler.accounts. Polls handle exhausted rate limits according to
+// scheduler.rate_limit_policy, so that they can wait out rate limit windows
+// rather than fail.
+func createScheduler(deps *Dependencies) *scheduler.Scheduler {
+	config := config.GetConfig()
+
+	var accounts []scheduler.Account
+	if err := config.UnmarshalKey("scheduler.accounts", &accounts); err != nil {
+		panic(fmt.Errorf("could not read scheduler accounts: %w", err))
+	}
+
+	s := scheduler.NewScheduler(deps.FollowerDiffService, *deps.TwitterService, deps.TrackedAccountService, deps.AccountMetricsService, config.GetDuration("scheduler.interval"), accounts)
+
+	policy := rateLimitPolicy("scheduler.rate_limit_policy")
+	s.PollContext = func(ctx context.Context) context.Context {
+		return apis.WithRateLimitPolicy(ctx, policy)
+	}
+
+	return s
+}
+
+// openDatabase opens the database configured by database.driver. If the
+// configured driver keeps everything in memory, nil is returned.
+func openDatabase() *sql.DB {
+	config := config.GetConfig()
+	if config.GetString("database.driver") != "sqlite" {
+		return nil
+	}
+
+	db, err := repositories.OpenSQLiteDatabase(config.GetString("database.path"))
+	if err != nil {
+		panic(err)
+	}
+	return db
+}
+
+func createFollowerSnapshotRepository(db *sql.DB) domain.FollowerSnapshotRepository {
+	if db == nil {
+		return repositories.NewInMemoryFollowerSnapshotRepository()
+	}
+
+	repo, err := repositories.NewSQLiteFollowerSnapshotRepository(db)
+	if err != nil {
+		panic(fmt.Errorf("could not create follower snapshot repository: %w", err))
+	}
+	return repo
+}
+
+func createAccountMetricsRepository(db *sql.DB) domain.AccountMetricsRepository {
+	if db == nil {
+		return repositories.NewInMemoryAccountMetricsRepository()
+	}
+
+	repo, err := repositories.NewSQLiteAccountMetricsRepository(db)
+	if err != nil {
+		panic(fmt.Errorf("could not create account metrics repository: %w", err))
+	}
+	return repo
+}
+
+func createTrackedAccountRepository(db *sql.DB) domain.TrackedAccountRepository {
+	if db == nil {
+		return repositories.NewInMemoryTrackedAccountRepository()
+	}
+
+	repo, err := repositories.NewSQLiteTrackedAccountRepository(db)
+	if err != nil {
+		panic(fmt.Errorf("could not create tracked account repository: %w", err))
+	}
+	return repo
+}
+
+func createTwitterConnectionRepository(db *sql.DB) domain.TwitterConnectionRepository {
+	if db == nil {
+		return repositories.NewInMemoryTwitterConnectionRepository()
+	}
+
+	repo, err := repositories.NewSQLiteTwitterConnectionRepository(db)
+	if err != nil {
+		panic(fmt.Errorf("could not create Twitter connection repository: %w", err))
+	}
+	return repo
+}
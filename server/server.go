@@ -1,13 +1,62 @@
 package server
 
 import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/jake-hansen/followrs/config"
 )
 
+// Init creates the services used by the server, starts the background
+// scheduler if it is enabled, and serves requests until the process is
+// interrupted or terminated. On shutdown, the contexts of requests in
+// progress are cancelled so that any upstream calls they are making stop.
 func Init(env string, startTime time.Time) {
-	r := NewRouter(env, startTime)
-	var address string = config.GetConfig().GetString("server.address")
-	r.Run(address)
+	deps := NewDependencies()
+
+	if config.GetConfig().GetBool("scheduler.enabled") {
+		s := createScheduler(deps)
+		s.Start()
+		defer s.Stop()
+	}
+
+	baseCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := &http.Server{
+		Addr:    config.GetConfig().GetString("server.address"),
+		Handler: NewRouter(env, startTime, deps),
+		BaseContext: func(net.Listener) context.Context {
+			return baseCtx
+		},
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case err := <-serveErr:
+		log.Printf("server stopped: %s", err.Error())
+		return
+	case sig := <-quit:
+		log.Printf("received %s, shutting down", sig.String())
+	}
+
+	cancel()
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), config.GetConfig().GetDuration("server.shutdown_timeout"))
+	defer cancelShutdown()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("could not shut down server gracefully: %s", err.Error())
+	}
 }
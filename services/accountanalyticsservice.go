@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+)
+
+const (
+	// defaultGrowthWindow is the number of days growth rates are computed over
+	// when no window is given.
+	defaultGrowthWindow = 7
+
+	// defaultAnomalyThreshold is the modified z-score beyond which a net change
+	// is reported as an anomaly, as recommended by Iglewicz and Hoaglin.
+	defaultAnomalyThreshold = 3.5
+
+	// minAnomalyDays is the fewest net changes needed to tell what is unusual.
+	minAnomalyDays = 5
+)
+
+// AccountAnalyticsService analyzes the metrics recorded for tracked accounts.
+type AccountAnalyticsService struct {
+	MetricsService domain.AccountMetricsService
+	Threshold      float64 // Modified z-score beyond which a net change is an anomaly.
+}
+
+// NewAccountAnalyticsService creates an AccountAnalyticsService that analyzes
+// the metrics presented by the given service.
+func NewAccountAnalyticsService(metricsService domain.AccountMetricsService) domain.AccountAnalyticsService {
+	return &AccountAnalyticsService{
+		MetricsService: metricsService,
+		Threshold:      defaultAnomalyThreshold,
+	}
+}
+
+// Get computes the daily growth of the tracked account from the last metrics
+// recorded on each day. Days whose metrics hide the number of followers are
+// skipped, since their zero count isn't a loss of followers. Days are compared
+// with the previous day a number of followers was recorded on, so the first
+// day only serves as a baseline. Net changes are
+// flagged as anomalies by their modified z-score, which is based on the median
+// absolute deviation so that the anomalies themselves don't hide each other.
+func (s *AccountAnalyticsService) Get(ctx context.Context, id int64, from time.Time, to time.Time, window int) (*domain.AccountAnalytics, error) {
+	if window <= 0 {
+		window = defaultGrowthWindow
+	}
+
+	recorded, err := s.MetricsService.Get(ctx, id, from, to, domain.DailyResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := make([]domain.AccountMetrics, 0, len(recorded))
+	for _, m := range recorded {
+		if !m.FollowersHidden {
+			metrics = append(metrics, m)
+		}
+	}
+	if len(metrics) == 0 {
+		return nil, fmt.Errorf("could not analyze account %d: %w", id, domain.ErrNoAccountMetrics)
+	}
+
+	analytics := &domain.AccountAnalytics{
+		From:      metrics[0].RecordedAt,
+		To:        metrics[len(metrics)-1].RecordedAt,
+		Window:    window,
+		Days:      make([]domain.DailyGrowth, 0, len(metrics)-1),
+		Anomalies: []domain.GrowthAnomaly{},
+	}
+
+	baseline := 0
+	for i := 1; i < len(metrics); i++ {
+		windowStart := metrics[i].RecordedAt.AddDate(0, 0, -window)
+		for baseline+1 < i && !metrics[baseline+1].RecordedAt.After(windowStart) {
+			baseline++
+		}
+
+		analytics.Days = append(analytics.Days, domain.DailyGrowth{
+			Date:       metrics[i].RecordedAt,
+			Followers:  metrics[i].Followers,
+			NetChange:  metrics[i].Followers - metrics[i-1].Followers,
+			GrowthRate: growthRate(metrics[baseline].Followers, metrics[i].Followers),
+		})
+	}
+
+	analytics.Anomalies = append(analytics.Anomalies, s.anomalies(analytics.Days)...)
+
+	return analytics, nil
+}
+
+// anomalies returns the days whose net change has a modified z-score beyond
+// the threshold.
+func (s *AccountAnalyticsService) anomalies(days []domain.DailyGrowth) []domain.GrowthAnomaly {
+	if len(days) < minAnomalyDays {
+		return nil
+	}
+
+	changes := make([]float64, 0, len(days))
+	for _, day := range days {
+		changes = append(changes, float64(day.NetChange))
+	}
+	score := modifiedZScore(changes)
+	if score == nil {
+		return nil
+	}
+
+	var anomalies []domain.GrowthAnomaly
+	for i, day := range days {
+		z := score(changes[i])
+		if math.Abs(z) <= s.Threshold {
+			continue
+		}
+
+		kind := domain.SpikeAnomaly
+		if z < 0 {
+			kind = domain.DropAnomaly
+		}
+		anomalies = append(anomalies, domain.GrowthAnomaly{
+			Date:      day.Date,
+			Kind:      kind,
+			NetChange: day.NetChange,
+			Score:     z,
+		})
+	}
+	return anomalies
+}
+
+// modifiedZScore returns the func that scores values by how far they are from
+// the median of the given values, scaled by their median absolute deviation.
+// If at least half of the values are the median, the mean absolute deviation
+// is used instead. If every value is the median, nil is returned.
+func modifiedZScore(values []float64) func(float64) float64 {
+	center := median(values)
+
+	deviations := make([]float64, 0, len(values))
+	var sum float64
+	for _, value := range values {
+		deviation := math.Abs(value - center)
+		deviations = append(deviations, deviation)
+		sum += deviation
+	}
+
+	if mad := median(deviations); mad > 0 {
+		return func(value float64) float64 {
+			return 0.6745 * (value - center) / mad
+		}
+	}
+	if meanAD := sum / float64(len(values)); meanAD > 0 {
+		return func(value float64) float64 {
+			return (value - center) / (1.253314 * meanAD)
+		}
+	}
+	return nil
+}
+
+// median returns the median of the given values without reordering them.
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// growthRate returns the fractional change from previous to current followers.
+// It is zero if there were no previous followers.
+func growthRate(previous int64, current int64) float64 {
+	if previous == 0 {
+		return 0
+	}
+	return float64(current-previous) / float64(previous)
+}
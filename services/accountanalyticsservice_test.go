@@ -0,0 +1,156 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/services"
+	"github.com/jake-hansen/followrs/services/mocks"
+)
+
+// TestGetAnalytics tests AccountAnalyticsService's Get func.
+func TestGetAnalytics(t *testing.T) {
+	start := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	from, to := start, start.AddDate(0, 1, 0)
+
+	// daily returns metrics recorded on consecutive days with the given follower counts.
+	daily := func(followers ...int64) []domain.AccountMetrics {
+		var metrics []domain.AccountMetrics
+		for i, count := range followers {
+			metrics = append(metrics, domain.AccountMetrics{Platform: "twitter", AccountID: "2", RecordedAt: start.AddDate(0, 0, i), Followers: count})
+		}
+		return metrics
+	}
+
+	t.Run("net-change-and-growth-rate", func(t *testing.T) {
+		metricsService := new(mocks.AccountMetricsService)
+		metricsService.On("Get", mock.Anything, int64(1), from, to, domain.DailyResolution).Return(daily(100, 110, 120, 150), nil)
+		service := services.NewAccountAnalyticsService(metricsService)
+
+		analytics, err := service.Get(context.Background(), 1, from, to, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, start, analytics.From)
+		assert.Equal(t, start.AddDate(0, 0, 3), analytics.To)
+		assert.Equal(t, 2, analytics.Window)
+		assert.Equal(t, []domain.DailyGrowth{
+			{Date: start.AddDate(0, 0, 1), Followers: 110, NetChange: 10, GrowthRate: 0.1},
+			{Date: start.AddDate(0, 0, 2), Followers: 120, NetChange: 10, GrowthRate: 0.2},
+			{Date: start.AddDate(0, 0, 3), Followers: 150, NetChange: 30, GrowthRate: 40.0 / 110},
+		}, analytics.Days)
+		assert.Empty(t, analytics.Anomalies)
+	})
+
+	t.Run("default-window", func(t *testing.T) {
+		metricsService := new(mocks.AccountMetricsService)
+		metricsService.On("Get", mock.Anything, int64(1), from, to, domain.DailyResolution).Return(daily(100, 200), nil)
+		service := services.NewAccountAnalyticsService(metricsService)
+
+		analytics, err := service.Get(context.Background(), 1, from, to, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, analytics.Window)
+	})
+
+	t.Run("spike-and-drop", func(t *testing.T) {
+		metricsService := new(mocks.AccountMetricsService)
+		metricsService.On("Get", mock.Anything, int64(1), from, to, domain.DailyResolution).Return(daily(100, 102, 105, 106, 500, 502, 504, 300, 303, 305), nil)
+		service := services.NewAccountAnalyticsService(metricsService)
+
+		analytics, err := service.Get(context.Background(), 1, from, to, 7)
+
+		assert.NoError(t, err)
+		if assert.Len(t, analytics.Anomalies, 2) {
+			assert.Equal(t, start.AddDate(0, 0, 4), analytics.Anomalies[0].Date)
+			assert.Equal(t, domain.SpikeAnomaly, analytics.Anomalies[0].Kind)
+			assert.Equal(t, int64(394), analytics.Anomalies[0].NetChange)
+			assert.Equal(t, start.AddDate(0, 0, 7), analytics.Anomalies[1].Date)
+			assert.Equal(t, domain.DropAnomaly, analytics.Anomalies[1].Kind)
+			assert.Equal(t, int64(-204), analytics.Anomalies[1].NetChange)
+		}
+	})
+
+	t.Run("steady-growth-has-no-anomalies", func(t *testing.T) {
+		metricsService := new(mocks.AccountMetricsService)
+		metricsService.On("Get", mock.Anything, int64(1), from, to, domain.DailyResolution).Return(daily(100, 110, 120, 130, 140, 150, 160), nil)
+		service := services.NewAccountAnalyticsService(metricsService)
+
+		analytics, err := service.Get(context.Background(), 1, from, to, 7)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, analytics.Anomalies)
+		assert.Empty(t, analytics.Anomalies)
+	})
+
+	t.Run("spike-among-unchanged-days", func(t *testing.T) {
+		metricsService := new(mocks.AccountMetricsService)
+		metricsService.On("Get", mock.Anything, int64(1), from, to, domain.DailyResolution).Return(daily(100, 100, 100, 100, 100, 180, 180), nil)
+		service := services.NewAccountAnalyticsService(metricsService)
+
+		analytics, err := service.Get(context.Background(), 1, from, to, 7)
+
+		assert.NoError(t, err)
+		if assert.Len(t, analytics.Anomalies, 1) {
+			assert.Equal(t, domain.SpikeAnomaly, analytics.Anomalies[0].Kind)
+			assert.Equal(t, int64(80), analytics.Anomalies[0].NetChange)
+		}
+	})
+
+	t.Run("hidden-followers-skipped", func(t *testing.T) {
+		metrics := daily(100, 101, 102, 103, 0, 104, 105, 106)
+		metrics[4].FollowersHidden = true
+		metricsService := new(mocks.AccountMetricsService)
+		metricsService.On("Get", mock.Anything, int64(1), from, to, domain.DailyResolution).Return(metrics, nil)
+		service := services.NewAccountAnalyticsService(metricsService)
+
+		analytics, err := service.Get(context.Background(), 1, from, to, 2)
+
+		assert.NoError(t, err)
+		assert.Len(t, analytics.Days, 6)
+		assert.Equal(t, domain.DailyGrowth{Date: start.AddDate(0, 0, 5), Followers: 104, NetChange: 1, GrowthRate: 1.0 / 103}, analytics.Days[3])
+		assert.Equal(t, domain.DailyGrowth{Date: start.AddDate(0, 0, 6), Followers: 105, NetChange: 1, GrowthRate: 2.0 / 103}, analytics.Days[4])
+		assert.Empty(t, analytics.Anomalies)
+	})
+
+	t.Run("only-hidden-followers", func(t *testing.T) {
+		metrics := daily(0, 0)
+		metrics[0].FollowersHidden = true
+		metrics[1].FollowersHidden = true
+		metricsService := new(mocks.AccountMetricsService)
+		metricsService.On("Get", mock.Anything, int64(1), from, to, domain.DailyResolution).Return(metrics, nil)
+		service := services.NewAccountAnalyticsService(metricsService)
+
+		analytics, err := service.Get(context.Background(), 1, from, to, 7)
+
+		assert.Nil(t, analytics)
+		assert.True(t, errors.Is(err, domain.ErrNoAccountMetrics))
+	})
+
+	t.Run("no-metrics", func(t *testing.T) {
+		metricsService := new(mocks.AccountMetricsService)
+		metricsService.On("Get", mock.Anything, int64(1), from, to, domain.DailyResolution).Return(nil, nil)
+		service := services.NewAccountAnalyticsService(metricsService)
+
+		analytics, err := service.Get(context.Background(), 1, from, to, 7)
+
+		assert.Nil(t, analytics)
+		assert.True(t, errors.Is(err, domain.ErrNoAccountMetrics))
+	})
+
+	t.Run("account-not-tracked", func(t *testing.T) {
+		metricsService := new(mocks.AccountMetricsService)
+		metricsService.On("Get", mock.Anything, int64(1), from, to, domain.DailyResolution).Return(nil, domain.ErrTrackedAccountNotFound)
+		service := services.NewAccountAnalyticsService(metricsService)
+
+		analytics, err := service.Get(context.Background(), 1, from, to, 7)
+
+		assert.Nil(t, analytics)
+		assert.True(t, errors.Is(err, domain.ErrTrackedAccountNotFound))
+	})
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+)
+
+// AccountMetricsService records and presents the metrics of tracked accounts.
+type AccountMetricsService struct {
+	AccountRepo domain.TrackedAccountRepository
+	MetricsRepo domain.AccountMetricsRepository
+	Providers   domain.ProviderRegistry
+}
+
+// NewAccountMetricsService creates an AccountMetricsService that finds tracked
+// accounts and their metrics in the given repositories, and retrieves the
+// metrics it records from the given Providers.
+func NewAccountMetricsService(accountRepo domain.TrackedAccountRepository, metricsRepo domain.AccountMetricsRepository, providers domain.ProviderRegistry) domain.AccountMetricsService {
+	return &AccountMetricsService{
+		AccountRepo: accountRepo,
+		MetricsRepo: metricsRepo,
+		Providers:   providers,
+	}
+}
+
+// Record retrieves the current metrics of the account from the Provider of its
+// platform and stores them. The metrics of Twitter accounts are also recorded
+// along with each snapshot of their followers, by FollowerDiffService.
+func (s *AccountMetricsService) Record(ctx context.Context, platform string, username string) (*domain.AccountMetrics, error) {
+	provider, err := s.Providers.Provider(platform)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics, err := provider.GetMetrics(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return s.save(ctx, metrics, username)
+}
+
+// RecordByID is like Record, but looks the account up by its ID if the
+// Provider of its platform can, so that it is still recorded after its
+// username changes, such as when a Bluesky handle or Twitch login is renamed.
+func (s *AccountMetricsService) RecordByID(ctx context.Context, platform string, id string, username string) (*domain.AccountMetrics, error) {
+	provider, err := s.Providers.Provider(platform)
+	if err != nil {
+		return nil, err
+	}
+
+	idProvider, ok := provider.(domain.IDProvider)
+	if !ok {
+		return s.Record(ctx, platform, username)
+	}
+
+	metrics, err := idProvider.GetMetricsByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.save(ctx, metrics, username)
+}
+
+// save stores the given metrics of the account with the given username.
+func (s *AccountMetricsService) save(ctx context.Context, metrics *domain.AccountMetrics, username string) (*domain.AccountMetrics, error) {
+	if err := s.MetricsRepo.Save(ctx, metrics); err != nil {
+		return nil, fmt.Errorf("could not record metrics of %s on %s: %w", username, metrics.Platform, err)
+	}
+	return metrics, nil
+}
+
+// Get returns the metrics of the tracked account recorded between from and to,
+// downsampled to the given resolution. An empty resolution is treated as
+// domain.RawResolution.
+func (s *AccountMetricsService) Get(ctx context.Context, id int64, from time.Time, to time.Time, resolution domain.Resolution) ([]domain.AccountMetrics, error) {
+	truncate, err := bucketStart(resolution)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.AccountRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics, err := s.MetricsRepo.List(ctx, account.Platform, account.PlatformUserID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return downsample(metrics, truncate), nil
+}
+
+// bucketStart returns the func that finds the start of the bucket a time falls
+// in at the given resolution. The func is nil if metrics are not downsampled.
+func bucketStart(resolution domain.Resolution) (func(time.Time) time.Time, error) {
+	switch resolution {
+	case "", domain.RawResolution:
+		return nil, nil
+	case domain.HourlyResolution:
+		return func(t time.Time) time.Time {
+			return t.UTC().Truncate(time.Hour)
+		}, nil
+	case domain.DailyResolution:
+		return startOfDay, nil
+	case domain.WeeklyResolution:
+		return func(t time.Time) time.Time {
+			day := startOfDay(t)
+			daysSinceMonday := (int(day.Weekday()) + 6) % 7
+			return day.AddDate(0, 0, -daysSinceMonday)
+		}, nil
+	}
+	return nil, fmt.Errorf("could not downsample metrics to %q: %w", resolution, domain.ErrInvalidResolution)
+}
+
+// startOfDay returns midnight UTC of the day t falls on.
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// downsample keeps the last of the given metrics recorded in each bucket and
+// timestamps it with the start of the bucket. The metrics must be ordered from
+// oldest to newest. If truncate is nil, the metrics are returned unchanged.
+func downsample(metrics []domain.AccountMetrics, truncate func(time.Time) time.Time) []domain.AccountMetrics {
+	if truncate == nil {
+		return metrics
+	}
+
+	var buckets []domain.AccountMetrics
+	for _, recorded := range metrics {
+		recorded.RecordedAt = truncate(recorded.RecordedAt)
+		if last := len(buckets) - 1; last >= 0 && buckets[last].RecordedAt.Equal(recorded.RecordedAt) {
+			buckets[last] = recorded
+		} else {
+			buckets = append(buckets, recorded)
+		}
+	}
+	return buckets
+}
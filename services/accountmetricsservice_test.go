@@ -0,0 +1,172 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories"
+	"github.com/jake-hansen/followrs/repositories/apis/bluesky"
+	repomocks "github.com/jake-hansen/followrs/repositories/mocks"
+	"github.com/jake-hansen/followrs/services"
+	"github.com/jake-hansen/followrs/services/mocks"
+)
+
+// TestGetMetrics tests AccountMetricsService's Get func.
+func TestGetMetrics(t *testing.T) {
+	// Monday, 1 March 2021.
+	monday := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	recorded := []domain.AccountMetrics{
+		{Platform: "twitter", AccountID: "2", RecordedAt: monday.Add(10 * time.Minute), Followers: 1},
+		{Platform: "twitter", AccountID: "2", RecordedAt: monday.Add(50 * time.Minute), Followers: 2},
+		{Platform: "twitter", AccountID: "2", RecordedAt: monday.Add(90 * time.Minute), Followers: 3},
+		{Platform: "twitter", AccountID: "2", RecordedAt: monday.Add(26 * time.Hour), Followers: 4},
+		{Platform: "twitter", AccountID: "2", RecordedAt: monday.AddDate(0, 0, 7).Add(time.Hour), Followers: 5},
+	}
+
+	newService := func(t *testing.T) domain.AccountMetricsService {
+		accountRepo := repositories.NewInMemoryTrackedAccountRepository()
+		assert.NoError(t, accountRepo.Add(context.Background(), &domain.TrackedAccount{Platform: "twitter", PlatformUserID: "2", Username: "test"}))
+		metricsRepo := repositories.NewInMemoryAccountMetricsRepository()
+		for i := range recorded {
+			assert.NoError(t, metricsRepo.Save(context.Background(), &recorded[i]))
+		}
+		return services.NewAccountMetricsService(accountRepo, metricsRepo, services.NewProviderRegistry())
+	}
+
+	followers := func(metrics []domain.AccountMetrics) (counts []int64, times []time.Time) {
+		for _, m := range metrics {
+			counts = append(counts, m.Followers)
+			times = append(times, m.RecordedAt)
+		}
+		return counts, times
+	}
+
+	from, to := monday.AddDate(0, 0, -1), monday.AddDate(0, 0, 14)
+
+	t.Run("raw", func(t *testing.T) {
+		metrics, err := newService(t).Get(context.Background(), 1, from, to, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, recorded, metrics)
+	})
+
+	t.Run("hourly", func(t *testing.T) {
+		metrics, err := newService(t).Get(context.Background(), 1, from, to, domain.HourlyResolution)
+
+		counts, times := followers(metrics)
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{2, 3, 4, 5}, counts)
+		assert.Equal(t, []time.Time{monday, monday.Add(time.Hour), monday.Add(26 * time.Hour), monday.AddDate(0, 0, 7).Add(time.Hour)}, times)
+	})
+
+	t.Run("daily", func(t *testing.T) {
+		metrics, err := newService(t).Get(context.Background(), 1, from, to, domain.DailyResolution)
+
+		counts, times := followers(metrics)
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{3, 4, 5}, counts)
+		assert.Equal(t, []time.Time{monday, monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 7)}, times)
+	})
+
+	t.Run("weekly", func(t *testing.T) {
+		metrics, err := newService(t).Get(context.Background(), 1, from, to, domain.WeeklyResolution)
+
+		counts, times := followers(metrics)
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{4, 5}, counts)
+		assert.Equal(t, []time.Time{monday, monday.AddDate(0, 0, 7)}, times)
+	})
+
+	t.Run("bounded-by-from-and-to", func(t *testing.T) {
+		metrics, err := newService(t).Get(context.Background(), 1, monday.Add(time.Hour), monday.Add(2*time.Hour), "")
+
+		counts, _ := followers(metrics)
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{3}, counts)
+	})
+
+	t.Run("invalid-resolution", func(t *testing.T) {
+		metrics, err := newService(t).Get(context.Background(), 1, from, to, "monthly")
+
+		assert.Nil(t, metrics)
+		assert.True(t, errors.Is(err, domain.ErrInvalidResolution))
+	})
+
+	t.Run("account-not-tracked", func(t *testing.T) {
+		metrics, err := newService(t).Get(context.Background(), 2, from, to, "")
+
+		assert.Nil(t, metrics)
+		assert.True(t, errors.Is(err, domain.ErrTrackedAccountNotFound))
+	})
+}
+
+// TestRecordMetrics tests AccountMetricsService's Record func.
+func TestRecordMetrics(t *testing.T) {
+	recordedAt := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		provider := new(mocks.Provider)
+		provider.On("Platform").Return("youtube")
+		provider.On("GetMetrics", mock.Anything, "@test").Return(&domain.AccountMetrics{
+			Platform: "youtube", AccountID: "UC1", RecordedAt: recordedAt, Views: 100, FollowersHidden: true,
+		}, nil)
+		metricsRepo := repositories.NewInMemoryAccountMetricsRepository()
+		service := services.NewAccountMetricsService(repositories.NewInMemoryTrackedAccountRepository(), metricsRepo, services.NewProviderRegistry(provider))
+
+		metrics, err := service.Record(context.Background(), "youtube", "@test")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(100), metrics.Views)
+		saved, err := metricsRepo.List(context.Background(), "youtube", "UC1", recordedAt, recordedAt)
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.AccountMetrics{*metrics}, saved)
+	})
+
+	t.Run("unsupported-platform", func(t *testing.T) {
+		service := services.NewAccountMetricsService(repositories.NewInMemoryTrackedAccountRepository(), repositories.NewInMemoryAccountMetricsRepository(), services.NewProviderRegistry())
+
+		metrics, err := service.Record(context.Background(), "youtube", "@test")
+
+		assert.Nil(t, metrics)
+		assert.True(t, errors.Is(err, domain.ErrUnsupportedPlatform))
+	})
+}
+
+// TestRecordMetricsByID tests AccountMetricsService's RecordByID func.
+func TestRecordMetricsByID(t *testing.T) {
+	t.Run("looks-up-by-id", func(t *testing.T) {
+		repo := new(repomocks.BlueskyRepository)
+		repo.On("GetProfile", mock.Anything, "did:plc:alice").Return(&bluesky.Profile{DID: "did:plc:alice", Handle: "renamed.bsky.social", FollowersCount: 10}, nil)
+		metricsRepo := repositories.NewInMemoryAccountMetricsRepository()
+		service := services.NewAccountMetricsService(repositories.NewInMemoryTrackedAccountRepository(), metricsRepo, services.NewProviderRegistry(services.NewBlueskyProvider(repo)))
+
+		metrics, err := service.RecordByID(context.Background(), "bluesky", "did:plc:alice", "alice.bsky.social")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "did:plc:alice", metrics.AccountID)
+		assert.Equal(t, int64(10), metrics.Followers)
+		repo.AssertNotCalled(t, "GetProfile", mock.Anything, "alice.bsky.social")
+		saved, err := metricsRepo.List(context.Background(), "bluesky", "did:plc:alice", metrics.RecordedAt, metrics.RecordedAt)
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.AccountMetrics{*metrics}, saved)
+	})
+
+	t.Run("falls-back-to-username", func(t *testing.T) {
+		provider := new(mocks.Provider)
+		provider.On("Platform").Return("mastodon")
+		provider.On("GetMetrics", mock.Anything, "alice@mastodon.social").Return(&domain.AccountMetrics{Platform: "mastodon", AccountID: "1"}, nil)
+		service := services.NewAccountMetricsService(repositories.NewInMemoryTrackedAccountRepository(), repositories.NewInMemoryAccountMetricsRepository(), services.NewProviderRegistry(provider))
+
+		metrics, err := service.RecordByID(context.Background(), "mastodon", "1", "alice@mastodon.social")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1", metrics.AccountID)
+		provider.AssertExpectations(t)
+	})
+}
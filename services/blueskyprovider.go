@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories/apis/bluesky"
+)
+
+// blueskyPlatform is the name of the platform of Bluesky accounts.
+const blueskyPlatform = "bluesky"
+
+// blueskyProfileURL is the URL of the page of the profile with the given handle.
+const blueskyProfileURL = "https://bsky.app/profile/%s"
+
+// BlueskyRepository retrieves the profiles of Bluesky accounts in the types
+// the Bluesky API describes them with. Accounts are looked up by their handle,
+// such as "alice.bsky.social", or DID.
+type BlueskyRepository interface {
+	GetProfile(ctx context.Context, actor string) (*bluesky.Profile, error)
+	GetFollowers(ctx context.Context, actor string) ([]bluesky.Profile, error)
+	GetFollows(ctx context.Context, actor string) ([]bluesky.Profile, error)
+}
+
+// BlueskyProvider is the Provider of Bluesky accounts. Usernames are the
+// handles of accounts, such as "alice.bsky.social", and IDs are their DIDs.
+type BlueskyProvider struct {
+	Repo BlueskyRepository
+	now  func() time.Time
+}
+
+// NewBlueskyProvider creates a BlueskyProvider that looks up accounts with the
+// given BlueskyRepository.
+func NewBlueskyProvider(repo BlueskyRepository) domain.Provider {
+	return &BlueskyProvider{
+		Repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Platform returns "bluesky".
+func (p *BlueskyProvider) Platform() string {
+	return blueskyPlatform
+}
+
+// LookupUser returns the Bluesky account with the given handle.
+func (p *BlueskyProvider) LookupUser(ctx context.Context, username string) (*domain.Account, error) {
+	profile, err := p.getProfile(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &domain.Account{
+		Platform:        blueskyPlatform,
+		ID:              profile.DID,
+		Username:        profile.Handle,
+		Name:            profile.DisplayName,
+		Description:     profile.Description,
+		ProfileImageURL: profile.Avatar,
+		URL:             fmt.Sprintf(blueskyProfileURL, profile.Handle),
+		CreatedAt:       profile.CreatedAt,
+		Metrics:         p.metrics(profile),
+	}
+	return account, nil
+}
+
+// ListFollowers returns the followers of the Bluesky account with the given
+// handle.
+func (p *BlueskyProvider) ListFollowers(ctx context.Context, username string) ([]domain.Follower, error) {
+	followers, err := p.Repo.GetFollowers(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred retrieving the followers of %s from Bluesky: %w", username, err)
+	}
+	return newBlueskyFollowers(followers), nil
+}
+
+// ListFollowing returns the accounts the Bluesky account with the given handle
+// follows.
+func (p *BlueskyProvider) ListFollowing(ctx context.Context, username string) ([]domain.Follower, error) {
+	follows, err := p.Repo.GetFollows(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred retrieving the accounts %s follows from Bluesky: %w", username, err)
+	}
+	return newBlueskyFollowers(follows), nil
+}
+
+// GetMetrics returns the counts of the Bluesky account with the given handle.
+// Posts are counted as Tweets.
+func (p *BlueskyProvider) GetMetrics(ctx context.Context, username string) (*domain.AccountMetrics, error) {
+	profile, err := p.getProfile(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return p.metrics(profile), nil
+}
+
+// GetMetricsByID returns the counts of the Bluesky account with the given DID,
+// which stays the same when the account changes its handle.
+func (p *BlueskyProvider) GetMetricsByID(ctx context.Context, id string) (*domain.AccountMetrics, error) {
+	return p.GetMetrics(ctx, id)
+}
+
+func (p *BlueskyProvider) getProfile(ctx context.Context, username string) (*bluesky.Profile, error) {
+	profile, err := p.Repo.GetProfile(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred retrieving the account %s from Bluesky: %w", username, err)
+	}
+	return profile, nil
+}
+
+// metrics returns the counts of the profile as recorded now.
+func (p *BlueskyProvider) metrics(profile *bluesky.Profile) *domain.AccountMetrics {
+	return &domain.AccountMetrics{
+		Platform:   blueskyPlatform,
+		AccountID:  profile.DID,
+		RecordedAt: p.now().UTC(),
+		Followers:  profile.FollowersCount,
+		Following:  profile.FollowsCount,
+		Tweets:     profile.PostsCount,
+	}
+}
+
+func newBlueskyFollowers(profiles []bluesky.Profile) []domain.Follower {
+	followers := make([]domain.Follower, 0, len(profiles))
+	for _, profile := range profiles {
+		followers = append(followers, domain.Follower{
+			ID:              profile.DID,
+			Username:        profile.Handle,
+			Name:            profile.DisplayName,
+			ProfileImageURL: profile.Avatar,
+		})
+	}
+	return followers
+}
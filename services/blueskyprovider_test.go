@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories/apis/bluesky"
+	"github.com/jake-hansen/followrs/repositories/mocks"
+	"github.com/jake-hansen/followrs/services"
+)
+
+// TestBlueskyProvider tests the funcs of BlueskyProvider.
+func TestBlueskyProvider(t *testing.T) {
+	createdAt := time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)
+	profile := &bluesky.Profile{
+		DID:            "did:plc:alice",
+		Handle:         "alice.bsky.social",
+		DisplayName:    "Alice",
+		CreatedAt:      &createdAt,
+		FollowersCount: 10,
+		FollowsCount:   20,
+		PostsCount:     30,
+	}
+
+	t.Run("lookup-user", func(t *testing.T) {
+		repo := new(mocks.BlueskyRepository)
+		repo.On("GetProfile", mock.Anything, "alice.bsky.social").Return(profile, nil)
+		provider := services.NewBlueskyProvider(repo)
+
+		account, err := provider.LookupUser(context.Background(), "alice.bsky.social")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "bluesky", account.Platform)
+		assert.Equal(t, "did:plc:alice", account.ID)
+		assert.Equal(t, "alice.bsky.social", account.Username)
+		assert.Equal(t, "https://bsky.app/profile/alice.bsky.social", account.URL)
+		assert.Equal(t, &createdAt, account.CreatedAt)
+		assert.Equal(t, int64(10), account.Metrics.Followers)
+		assert.Equal(t, int64(30), account.Metrics.Tweets)
+	})
+
+	t.Run("lookup-user-failed", func(t *testing.T) {
+		repo := new(mocks.BlueskyRepository)
+		repo.On("GetProfile", mock.Anything, "alice.bsky.social").Return(nil, apperrors.ErrNotFound)
+		provider := services.NewBlueskyProvider(repo)
+
+		account, err := provider.LookupUser(context.Background(), "alice.bsky.social")
+
+		assert.Nil(t, account)
+		assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+	})
+
+	t.Run("list-followers", func(t *testing.T) {
+		repo := new(mocks.BlueskyRepository)
+		repo.On("GetFollowers", mock.Anything, "alice.bsky.social").Return([]bluesky.Profile{{DID: "did:plc:bob", Handle: "bob.bsky.social", DisplayName: "Bob", Avatar: "https://cdn.bsky.app/bob.jpg"}}, nil)
+		provider := services.NewBlueskyProvider(repo)
+
+		followers, err := provider.ListFollowers(context.Background(), "alice.bsky.social")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Follower{{ID: "did:plc:bob", Username: "bob.bsky.social", Name: "Bob", ProfileImageURL: "https://cdn.bsky.app/bob.jpg"}}, followers)
+	})
+
+	t.Run("list-following", func(t *testing.T) {
+		repo := new(mocks.BlueskyRepository)
+		repo.On("GetFollows", mock.Anything, "alice.bsky.social").Return([]bluesky.Profile{}, nil)
+		provider := services.NewBlueskyProvider(repo)
+
+		following, err := provider.ListFollowing(context.Background(), "alice.bsky.social")
+
+		assert.NoError(t, err)
+		assert.Empty(t, following)
+	})
+
+	t.Run("get-metrics", func(t *testing.T) {
+		repo := new(mocks.BlueskyRepository)
+		repo.On("GetProfile", mock.Anything, "alice.bsky.social").Return(profile, nil)
+		provider := services.NewBlueskyProvider(repo)
+
+		metrics, err := provider.GetMetrics(context.Background(), "alice.bsky.social")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "bluesky", metrics.Platform)
+		assert.Equal(t, "did:plc:alice", metrics.AccountID)
+		assert.Equal(t, int64(20), metrics.Following)
+	})
+}
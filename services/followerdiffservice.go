@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+)
+
+// twitterPlatform is the platform name recorded on snapshots of Twitter accounts.
+const twitterPlatform = "twitter"
+
+// FollowerDiffService records snapshots of the followers of Twitter users and
+// compares them to find who followed and unfollowed. The public metrics of the
+// users are recorded alongside every snapshot.
+type FollowerDiffService struct {
+	TwitterService domain.TwitterService
+	SnapshotRepo   domain.FollowerSnapshotRepository
+	MetricsRepo    domain.AccountMetricsRepository
+	now            func() time.Time
+}
+
+// NewFollowerDiffService creates a FollowerDiffService that stores snapshots
+// and metrics in the given repositories.
+func NewFollowerDiffService(twitterService domain.TwitterService, snapshotRepo domain.FollowerSnapshotRepository, metricsRepo domain.AccountMetricsRepository) domain.FollowerDiffService {
+	return &FollowerDiffService{
+		TwitterService: twitterService,
+		SnapshotRepo:   snapshotRepo,
+		MetricsRepo:    metricsRepo,
+		now:            time.Now,
+	}
+}
+
+// RecordSnapshot retrieves the current followers of the given user and stores
+// them as a new snapshot, along with the user's current public metrics.
+func (d *FollowerDiffService) RecordSnapshot(ctx context.Context, username string) (*domain.FollowerSnapshot, error) {
+	user, err := d.TwitterService.GetUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.recordSnapshot(ctx, user)
+}
+
+// RecordSnapshotByID is like RecordSnapshot, but looks up the user with the
+// given ID, so that the same account is recorded after it is renamed.
+func (d *FollowerDiffService) RecordSnapshotByID(ctx context.Context, id string) (*domain.FollowerSnapshot, error) {
+	user, err := d.TwitterService.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.recordSnapshot(ctx, user)
+}
+
+// GetChanges compares the most recent snapshot of the given user's followers
+// with the most recent snapshot taken at or before since. If there is no
+// snapshot that old, the oldest snapshot taken after since is used instead. If
+// since isn't before the most recent snapshot, it is compared with the one
+// before it. No snapshot is taken, so the changes only move on when the
+// scheduler or RecordSnapshot records a new one. The followers gained and lost
+// are looked up again so that their details can be returned, with the given
+// options; users that can no longer be found are returned with only their ID.
+func (d *FollowerDiffService) GetChanges(ctx context.Context, username string, since time.Time, opts ...domain.TwitterLookupOptions) (*domain.FollowerChanges, error) {
+	user, err := d.TwitterService.GetUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := d.SnapshotRepo.At(ctx, twitterPlatform, user.ID, d.now())
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, fmt.Errorf("could not compare followers of %s: %w", username, domain.ErrNoFollowerSnapshot)
+	}
+
+	if !since.Before(current.TakenAt) {
+		since = current.TakenAt.Add(-time.Nanosecond)
+	}
+	baseline, err := d.baseline(ctx, user.ID, since, current.TakenAt)
+	if err != nil {
+		return nil, err
+	}
+	if baseline == nil {
+		return nil, fmt.Errorf("could not compare followers of %s: %w", username, domain.ErrNoFollowerSnapshot)
+	}
+
+	gainedIDs, lostIDs := diffFollowerIDs(baseline.FollowerIDs, current.FollowerIDs)
+	changes := &domain.FollowerChanges{
+		From:   baseline.TakenAt,
+		To:     current.TakenAt,
+		Gained: d.hydrate(ctx, gainedIDs, opts),
+		Lost:   d.hydrate(ctx, lostIDs, opts),
+	}
+	return changes, nil
+}
+
+func (d *FollowerDiffService) recordSnapshot(ctx context.Context, user *domain.TwitterUser) (*domain.FollowerSnapshot, error) {
+	followers, err := d.TwitterService.GetFollowersByID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &domain.FollowerSnapshot{
+		Platform:    twitterPlatform,
+		AccountID:   user.ID,
+		TakenAt:     d.now().UTC(),
+		FollowerIDs: make([]string, 0, len(followers)),
+	}
+	for _, follower := range followers {
+		snapshot.FollowerIDs = append(snapshot.FollowerIDs, follower.ID)
+	}
+
+	err = d.SnapshotRepo.Save(ctx, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("could not record follower snapshot of %s: %w", user.Username, err)
+	}
+
+	err = d.recordMetrics(ctx, user, snapshot.TakenAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// recordMetrics stores the public metrics of the given user, if they were
+// looked up, as recorded at the given time.
+func (d *FollowerDiffService) recordMetrics(ctx context.Context, user *domain.TwitterUser, recordedAt time.Time) error {
+	if user.PublicMetrics == nil {
+		return nil
+	}
+
+	metrics := &domain.AccountMetrics{
+		Platform:   twitterPlatform,
+		AccountID:  user.ID,
+		RecordedAt: recordedAt,
+		Followers:  user.PublicMetrics.Followers,
+		Following:  user.PublicMetrics.Following,
+		Tweets:     user.PublicMetrics.Tweets,
+		Listed:     user.PublicMetrics.Listed,
+	}
+	if err := d.MetricsRepo.Save(ctx, metrics); err != nil {
+		return fmt.Errorf("could not record metrics of %s: %w", user.Username, err)
+	}
+	return nil
+}
+
+// baseline finds the snapshot taken before current that the current
+// followers should be compared with.
+func (d *FollowerDiffService) baseline(ctx context.Context, accountID string, since time.Time, current time.Time) (*domain.FollowerSnapshot, error) {
+	snapshot, err := d.SnapshotRepo.At(ctx, twitterPlatform, accountID, since)
+	if err != nil || snapshot != nil {
+		return snapshot, err
+	}
+
+	snapshots, err := d.SnapshotRepo.List(ctx, twitterPlatform, accountID, since, current)
+	if err != nil || len(snapshots) == 0 || !snapshots[0].TakenAt.Before(current) {
+		return nil, err
+	}
+	return &snapshots[0], nil
+}
+
+// hydrate looks up the details of the users with the given IDs at once. Users
+// that can't be looked up are returned with only their ID.
+func (d *FollowerDiffService) hydrate(ctx context.Context, ids []string, opts []domain.TwitterLookupOptions) []domain.TwitterUser {
+	usersByID := make(map[string]domain.TwitterUser, len(ids))
+	if len(ids) > 0 {
+		if lookup, err := d.TwitterService.GetUsersByID(ctx, ids, opts...); err == nil {
+			for _, user := range lookup.Users {
+				usersByID[user.ID] = user
+			}
+		}
+	}
+
+	users := make([]domain.TwitterUser, 0, len(ids))
+	for _, id := range ids {
+		user, ok := usersByID[id]
+		if !ok {
+			user = domain.TwitterUser{ID: id}
+		}
+		users = append(users, user)
+	}
+	return users
+}
+
+// diffFollowerIDs returns the IDs present in current but not in previous, and
+// the IDs present in previous but not in current.
+func diffFollowerIDs(previous []string, current []string) (gained []string, lost []string) {
+	previousIDs := make(map[string]bool, len(previous))
+	for _, id := range previous {
+		previousIDs[id] = true
+	}
+	currentIDs := make(map[string]bool, len(current))
+	for _, id := range current {
+		currentIDs[id] = true
+		if !previousIDs[id] {
+			gained = append(gained, id)
+		}
+	}
+	for _, id := range previous {
+		if !currentIDs[id] {
+			lost = append(lost, id)
+		}
+	}
+	return gained, lost
+}
@@ -0,0 +1,221 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories"
+	"github.com/jake-hansen/followrs/services"
+	"github.com/jake-hansen/followrs/services/mocks"
+)
+
+// TestGetChanges tests FollowerDiffService's GetChanges func.
+func TestGetChanges(t *testing.T) {
+	user := &domain.TwitterUser{ID: "1", Username: "test"}
+
+	t.Run("success", func(t *testing.T) {
+		baselineTime := time.Now().Add(-time.Hour).UTC()
+		currentTime := time.Now().Add(-time.Minute).UTC()
+		repo := repositories.NewInMemoryFollowerSnapshotRepository()
+		repo.Save(context.Background(), &domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: baselineTime, FollowerIDs: []string{"2", "3"}})
+		repo.Save(context.Background(), &domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: currentTime, FollowerIDs: []string{"3", "4"}})
+
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetUser", mock.Anything, "test").Return(user, nil)
+		twitterService.On("GetUsersByID", mock.Anything, []string{"4"}).Return(&domain.TwitterUsers{Users: []domain.TwitterUser{{ID: "4", Username: "four"}}}, nil)
+		twitterService.On("GetUsersByID", mock.Anything, []string{"2"}).Return(&domain.TwitterUsers{Users: []domain.TwitterUser{{ID: "2", Username: "two"}}}, nil)
+		service := services.NewFollowerDiffService(twitterService, repo, repositories.NewInMemoryAccountMetricsRepository())
+
+		changes, err := service.GetChanges(context.Background(), "test", baselineTime)
+
+		assert.NoError(t, err)
+		assert.Equal(t, baselineTime, changes.From)
+		assert.Equal(t, currentTime, changes.To)
+		assert.Equal(t, []domain.TwitterUser{{ID: "4", Username: "four"}}, changes.Gained)
+		assert.Equal(t, []domain.TwitterUser{{ID: "2", Username: "two"}}, changes.Lost)
+		twitterService.AssertExpectations(t)
+	})
+
+	t.Run("takes-no-snapshot", func(t *testing.T) {
+		repo := repositories.NewInMemoryFollowerSnapshotRepository()
+		repo.Save(context.Background(), &domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: time.Now().Add(-time.Hour), FollowerIDs: []string{"2"}})
+		repo.Save(context.Background(), &domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: time.Now().Add(-time.Minute), FollowerIDs: []string{"2"}})
+
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetUser", mock.Anything, "test").Return(user, nil)
+		service := services.NewFollowerDiffService(twitterService, repo, repositories.NewInMemoryAccountMetricsRepository())
+
+		for i := 0; i < 2; i++ {
+			_, err := service.GetChanges(context.Background(), "test", time.Now().Add(-2*time.Hour))
+			assert.NoError(t, err)
+		}
+
+		twitterService.AssertNotCalled(t, "GetFollowers", mock.Anything, mock.Anything)
+		snapshots, _ := repo.List(context.Background(), "twitter", "1", time.Now().Add(-2*time.Hour), time.Now())
+		assert.Len(t, snapshots, 2)
+	})
+
+	t.Run("compares-previous-snapshot-when-since-omitted", func(t *testing.T) {
+		previousTime := time.Now().Add(-2 * time.Hour).UTC()
+		currentTime := time.Now().Add(-time.Hour).UTC()
+		repo := repositories.NewInMemoryFollowerSnapshotRepository()
+		repo.Save(context.Background(), &domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: time.Now().Add(-3 * time.Hour), FollowerIDs: []string{}})
+		repo.Save(context.Background(), &domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: previousTime, FollowerIDs: []string{"2"}})
+		repo.Save(context.Background(), &domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: currentTime, FollowerIDs: []string{"2"}})
+
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetUser", mock.Anything, "test").Return(user, nil)
+		service := services.NewFollowerDiffService(twitterService, repo, repositories.NewInMemoryAccountMetricsRepository())
+
+		changes, err := service.GetChanges(context.Background(), "test", time.Now())
+
+		assert.NoError(t, err)
+		assert.Equal(t, previousTime, changes.From)
+		assert.Equal(t, currentTime, changes.To)
+		assert.Empty(t, changes.Gained)
+		assert.Empty(t, changes.Lost)
+	})
+
+	t.Run("lost-follower-no-longer-exists", func(t *testing.T) {
+		repo := repositories.NewInMemoryFollowerSnapshotRepository()
+		repo.Save(context.Background(), &domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: time.Now().Add(-time.Hour), FollowerIDs: []string{"2"}})
+		repo.Save(context.Background(), &domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: time.Now().Add(-time.Minute), FollowerIDs: []string{}})
+
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetUser", mock.Anything, "test").Return(user, nil)
+		twitterService.On("GetUsersByID", mock.Anything, []string{"2"}).Return(&domain.TwitterUsers{Failures: []domain.TwitterLookupFailure{{Value: "2", Err: errors.New("user not found")}}}, nil)
+		service := services.NewFollowerDiffService(twitterService, repo, repositories.NewInMemoryAccountMetricsRepository())
+
+		changes, err := service.GetChanges(context.Background(), "test", time.Now())
+
+		assert.NoError(t, err)
+		assert.Empty(t, changes.Gained)
+		assert.Equal(t, []domain.TwitterUser{{ID: "2"}}, changes.Lost)
+	})
+
+	t.Run("lost-followers-looked-up-at-once", func(t *testing.T) {
+		repo := repositories.NewInMemoryFollowerSnapshotRepository()
+		repo.Save(context.Background(), &domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: time.Now().Add(-time.Hour), FollowerIDs: []string{"2", "3", "4"}})
+		repo.Save(context.Background(), &domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: time.Now().Add(-time.Minute), FollowerIDs: []string{}})
+
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetUser", mock.Anything, "test").Return(user, nil)
+		twitterService.On("GetUsersByID", mock.Anything, []string{"2", "3", "4"}).Return(&domain.TwitterUsers{Users: []domain.TwitterUser{{ID: "4", Username: "four"}, {ID: "2", Username: "two"}}}, nil).Once()
+		service := services.NewFollowerDiffService(twitterService, repo, repositories.NewInMemoryAccountMetricsRepository())
+
+		changes, err := service.GetChanges(context.Background(), "test", time.Now())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.TwitterUser{{ID: "2", Username: "two"}, {ID: "3"}, {ID: "4", Username: "four"}}, changes.Lost)
+		twitterService.AssertNumberOfCalls(t, "GetUsersByID", 1)
+	})
+
+	t.Run("uses-oldest-snapshot-after-since", func(t *testing.T) {
+		snapshotTime := time.Now().Add(-time.Hour).UTC()
+		repo := repositories.NewInMemoryFollowerSnapshotRepository()
+		repo.Save(context.Background(), &domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: snapshotTime, FollowerIDs: []string{"2"}})
+		repo.Save(context.Background(), &domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: time.Now().Add(-time.Minute), FollowerIDs: []string{"2"}})
+
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetUser", mock.Anything, "test").Return(user, nil)
+		service := services.NewFollowerDiffService(twitterService, repo, repositories.NewInMemoryAccountMetricsRepository())
+
+		changes, err := service.GetChanges(context.Background(), "test", time.Now().Add(-24*time.Hour))
+
+		assert.NoError(t, err)
+		assert.Equal(t, snapshotTime, changes.From)
+		assert.Empty(t, changes.Gained)
+		assert.Empty(t, changes.Lost)
+	})
+
+	t.Run("single-snapshot", func(t *testing.T) {
+		repo := repositories.NewInMemoryFollowerSnapshotRepository()
+		repo.Save(context.Background(), &domain.FollowerSnapshot{Platform: "twitter", AccountID: "1", TakenAt: time.Now().Add(-time.Hour), FollowerIDs: []string{"2"}})
+
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetUser", mock.Anything, "test").Return(user, nil)
+		service := services.NewFollowerDiffService(twitterService, repo, repositories.NewInMemoryAccountMetricsRepository())
+
+		changes, err := service.GetChanges(context.Background(), "test", time.Now().Add(-24*time.Hour))
+
+		assert.Nil(t, changes)
+		assert.True(t, errors.Is(err, domain.ErrNoFollowerSnapshot))
+	})
+
+	t.Run("no-snapshot", func(t *testing.T) {
+		repo := repositories.NewInMemoryFollowerSnapshotRepository()
+
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetUser", mock.Anything, "test").Return(user, nil)
+		service := services.NewFollowerDiffService(twitterService, repo, repositories.NewInMemoryAccountMetricsRepository())
+
+		changes, err := service.GetChanges(context.Background(), "test", time.Now())
+
+		assert.Nil(t, changes)
+		assert.True(t, errors.Is(err, domain.ErrNoFollowerSnapshot))
+
+		snapshot, _ := repo.At(context.Background(), "twitter", "1", time.Now())
+		assert.Nil(t, snapshot)
+	})
+}
+
+// TestRecordSnapshot tests FollowerDiffService's RecordSnapshot func.
+func TestRecordSnapshot(t *testing.T) {
+	t.Run("records-metrics", func(t *testing.T) {
+		metricsUser := &domain.TwitterUser{ID: "1", Username: "test", PublicMetrics: &domain.TwitterPublicMetrics{Followers: 1, Following: 2, Tweets: 3, Listed: 4}}
+		repo := repositories.NewInMemoryFollowerSnapshotRepository()
+		metricsRepo := repositories.NewInMemoryAccountMetricsRepository()
+
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetUser", mock.Anything, "test").Return(metricsUser, nil)
+		twitterService.On("GetFollowersByID", mock.Anything, "1").Return([]domain.TwitterUser{{ID: "2"}}, nil)
+		service := services.NewFollowerDiffService(twitterService, repo, metricsRepo)
+
+		snapshot, err := service.RecordSnapshot(context.Background(), "test")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"2"}, snapshot.FollowerIDs)
+		latest, _ := repo.At(context.Background(), "twitter", "1", time.Now())
+		assert.Equal(t, snapshot, latest)
+		metrics, _ := metricsRepo.List(context.Background(), "twitter", "1", time.Now().Add(-time.Hour), time.Now())
+		assert.Equal(t, []domain.AccountMetrics{{Platform: "twitter", AccountID: "1", RecordedAt: snapshot.TakenAt, Followers: 1, Following: 2, Tweets: 3, Listed: 4}}, metrics)
+	})
+}
+
+// TestRecordSnapshotByID tests FollowerDiffService's RecordSnapshotByID func.
+func TestRecordSnapshotByID(t *testing.T) {
+	t.Run("renamed-account", func(t *testing.T) {
+		repo := repositories.NewInMemoryFollowerSnapshotRepository()
+
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetUserByID", mock.Anything, "1").Return(&domain.TwitterUser{ID: "1", Username: "renamed"}, nil)
+		twitterService.On("GetFollowersByID", mock.Anything, "1").Return([]domain.TwitterUser{{ID: "2"}}, nil)
+		service := services.NewFollowerDiffService(twitterService, repo, repositories.NewInMemoryAccountMetricsRepository())
+
+		snapshot, err := service.RecordSnapshotByID(context.Background(), "1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1", snapshot.AccountID)
+		assert.Equal(t, []string{"2"}, snapshot.FollowerIDs)
+		twitterService.AssertNotCalled(t, "GetUser", mock.Anything, mock.Anything)
+		twitterService.AssertNumberOfCalls(t, "GetUserByID", 1)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetUserByID", mock.Anything, "1").Return(nil, apperrors.ErrNotFound)
+		service := services.NewFollowerDiffService(twitterService, repositories.NewInMemoryFollowerSnapshotRepository(), repositories.NewInMemoryAccountMetricsRepository())
+
+		snapshot, err := service.RecordSnapshotByID(context.Background(), "1")
+
+		assert.Nil(t, snapshot)
+		assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+	})
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories/apis/github"
+)
+
+// githubPlatform is the name of the platform of GitHub accounts.
+const githubPlatform = "github"
+
+// GitHubRepository retrieves users from the GitHub API in the types the API
+// describes them with. Users are looked up by their login.
+type GitHubRepository interface {
+	GetUser(ctx context.Context, login string) (*github.User, error)
+	GetUserByID(ctx context.Context, id int64) (*github.User, error)
+	GetFollowers(ctx context.Context, login string) ([]github.User, error)
+	GetFollowing(ctx context.Context, login string) ([]github.User, error)
+	GetStargazers(ctx context.Context, owner string, repo string) ([]github.User, error)
+}
+
+// GitHubProvider is the Provider of GitHub accounts. Usernames are the logins
+// of users, such as "octocat".
+type GitHubProvider struct {
+	Repo GitHubRepository
+	now  func() time.Time
+}
+
+// NewGitHubProvider creates a GitHubProvider that looks up users with the
+// given GitHubRepository.
+func NewGitHubProvider(repo GitHubRepository) domain.Provider {
+	return &GitHubProvider{
+		Repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Platform returns "github".
+func (p *GitHubProvider) Platform() string {
+	return githubPlatform
+}
+
+// LookupUser returns the GitHub user with the given login.
+func (p *GitHubProvider) LookupUser(ctx context.Context, username string) (*domain.Account, error) {
+	user, err := p.getUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &domain.Account{
+		Platform:        githubPlatform,
+		ID:              strconv.FormatInt(user.ID, 10),
+		Username:        user.Login,
+		Name:            user.Name,
+		Description:     user.Bio,
+		Location:        user.Location,
+		ProfileImageURL: user.AvatarURL,
+		URL:             user.Blog,
+		CreatedAt:       user.CreatedAt,
+		Metrics:         p.metrics(user),
+	}
+	return account, nil
+}
+
+// ListFollowers returns the followers of the GitHub user with the given login.
+func (p *GitHubProvider) ListFollowers(ctx context.Context, username string) ([]domain.Follower, error) {
+	followers, err := p.Repo.GetFollowers(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred retrieving the followers of %s from GitHub: %w", username, err)
+	}
+	return newGitHubFollowers(followers), nil
+}
+
+// ListFollowing returns the users the GitHub user with the given login follows.
+func (p *GitHubProvider) ListFollowing(ctx context.Context, username string) ([]domain.Follower, error) {
+	following, err := p.Repo.GetFollowing(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred retrieving the users %s follows from GitHub: %w", username, err)
+	}
+	return newGitHubFollowers(following), nil
+}
+
+// ListStargazers returns the users that have starred the repository with the
+// given name that belongs to the GitHub user or organization with the given
+// login.
+func (p *GitHubProvider) ListStargazers(ctx context.Context, username string, repository string) ([]domain.Follower, error) {
+	stargazers, err := p.Repo.GetStargazers(ctx, username, repository)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred retrieving the stargazers of %s/%s from GitHub: %w", username, repository, err)
+	}
+	return newGitHubFollowers(stargazers), nil
+}
+
+// GetMetrics returns the follower and following counts of the GitHub user
+// with the given login.
+func (p *GitHubProvider) GetMetrics(ctx context.Context, username string) (*domain.AccountMetrics, error) {
+	user, err := p.getUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return p.metrics(user), nil
+}
+
+// GetMetricsByID returns the follower and following counts of the GitHub user
+// with the given ID, which stays the same when the user changes their login.
+func (p *GitHubProvider) GetMetricsByID(ctx context.Context, id string) (*domain.AccountMetrics, error) {
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not the ID of a GitHub user: %w", id, apperrors.ErrInvalidUsername)
+	}
+
+	user, err := p.Repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred retrieving the user with id %s from GitHub: %w", id, err)
+	}
+	return p.metrics(user), nil
+}
+
+func (p *GitHubProvider) getUser(ctx context.Context, username string) (*github.User, error) {
+	user, err := p.Repo.GetUser(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred retrieving the user %s from GitHub: %w", username, err)
+	}
+	return user, nil
+}
+
+// metrics returns the counts of the user as recorded now.
+func (p *GitHubProvider) metrics(user *github.User) *domain.AccountMetrics {
+	return &domain.AccountMetrics{
+		Platform:   githubPlatform,
+		AccountID:  strconv.FormatInt(user.ID, 10),
+		RecordedAt: p.now().UTC(),
+		Followers:  user.Followers,
+		Following:  user.Following,
+	}
+}
+
+func newGitHubFollowers(users []github.User) []domain.Follower {
+	followers := make([]domain.Follower, 0, len(users))
+	for _, user := range users {
+		followers = append(followers, domain.Follower{
+			ID:              strconv.FormatInt(user.ID, 10),
+			Username:        user.Login,
+			Name:            user.Name,
+			ProfileImageURL: user.AvatarURL,
+		})
+	}
+	return followers
+}
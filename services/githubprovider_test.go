@@ -0,0 +1,129 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories/apis/github"
+	"github.com/jake-hansen/followrs/repositories/mocks"
+	"github.com/jake-hansen/followrs/services"
+)
+
+// TestGitHubProvider tests the funcs of GitHubProvider.
+func TestGitHubProvider(t *testing.T) {
+	createdAt := time.Date(2011, time.January, 25, 18, 44, 36, 0, time.UTC)
+	user := &github.User{
+		Login:     "octocat",
+		ID:        583231,
+		Name:      "The Octocat",
+		Bio:       "Mascot",
+		Blog:      "https://github.blog",
+		CreatedAt: &createdAt,
+		Followers: 20,
+		Following: 9,
+	}
+
+	t.Run("lookup-user", func(t *testing.T) {
+		repo := new(mocks.GitHubRepository)
+		repo.On("GetUser", mock.Anything, "octocat").Return(user, nil)
+		provider := services.NewGitHubProvider(repo)
+
+		account, err := provider.LookupUser(context.Background(), "octocat")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "github", account.Platform)
+		assert.Equal(t, "583231", account.ID)
+		assert.Equal(t, "octocat", account.Username)
+		assert.Equal(t, "Mascot", account.Description)
+		assert.Equal(t, "https://github.blog", account.URL)
+		assert.Equal(t, &createdAt, account.CreatedAt)
+		assert.Equal(t, int64(20), account.Metrics.Followers)
+	})
+
+	t.Run("metrics-by-id", func(t *testing.T) {
+		repo := new(mocks.GitHubRepository)
+		repo.On("GetUserByID", mock.Anything, int64(583231)).Return(user, nil)
+		provider := services.NewGitHubProvider(repo).(domain.IDProvider)
+
+		metrics, err := provider.GetMetricsByID(context.Background(), "583231")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "583231", metrics.AccountID)
+		assert.Equal(t, int64(20), metrics.Followers)
+		repo.AssertNotCalled(t, "GetUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("metrics-by-invalid-id", func(t *testing.T) {
+		repo := new(mocks.GitHubRepository)
+		provider := services.NewGitHubProvider(repo).(domain.IDProvider)
+
+		metrics, err := provider.GetMetricsByID(context.Background(), "octocat")
+
+		assert.Nil(t, metrics)
+		assert.True(t, errors.Is(err, apperrors.ErrInvalidUsername))
+	})
+
+	t.Run("lookup-user-failed", func(t *testing.T) {
+		repo := new(mocks.GitHubRepository)
+		repo.On("GetUser", mock.Anything, "octocat").Return(nil, apperrors.ErrRateLimited)
+		provider := services.NewGitHubProvider(repo)
+
+		account, err := provider.LookupUser(context.Background(), "octocat")
+
+		assert.Nil(t, account)
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+	})
+
+	t.Run("list-followers", func(t *testing.T) {
+		repo := new(mocks.GitHubRepository)
+		repo.On("GetFollowers", mock.Anything, "octocat").Return([]github.User{{Login: "hubot", ID: 1, AvatarURL: "https://avatars.githubusercontent.com/u/1"}}, nil)
+		provider := services.NewGitHubProvider(repo)
+
+		followers, err := provider.ListFollowers(context.Background(), "octocat")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Follower{{ID: "1", Username: "hubot", ProfileImageURL: "https://avatars.githubusercontent.com/u/1"}}, followers)
+	})
+
+	t.Run("list-following", func(t *testing.T) {
+		repo := new(mocks.GitHubRepository)
+		repo.On("GetFollowing", mock.Anything, "octocat").Return([]github.User{}, nil)
+		provider := services.NewGitHubProvider(repo)
+
+		following, err := provider.ListFollowing(context.Background(), "octocat")
+
+		assert.NoError(t, err)
+		assert.Empty(t, following)
+	})
+
+	t.Run("list-stargazers", func(t *testing.T) {
+		repo := new(mocks.GitHubRepository)
+		repo.On("GetStargazers", mock.Anything, "octocat", "hello-world").Return([]github.User{{Login: "hubot", ID: 1}}, nil)
+		provider := services.NewGitHubProvider(repo).(domain.StargazerProvider)
+
+		stargazers, err := provider.ListStargazers(context.Background(), "octocat", "hello-world")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Follower{{ID: "1", Username: "hubot"}}, stargazers)
+	})
+
+	t.Run("get-metrics", func(t *testing.T) {
+		repo := new(mocks.GitHubRepository)
+		repo.On("GetUser", mock.Anything, "octocat").Return(user, nil)
+		provider := services.NewGitHubProvider(repo)
+
+		metrics, err := provider.GetMetrics(context.Background(), "octocat")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "github", metrics.Platform)
+		assert.Equal(t, "583231", metrics.AccountID)
+		assert.Equal(t, int64(9), metrics.Following)
+	})
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories/apis/mastodon"
+)
+
+// mastodonPlatform is the name of the platform of Mastodon accounts.
+const mastodonPlatform = "mastodon"
+
+// MastodonRepository retrieves accounts from the instances of Mastodon in the
+// types the Mastodon API describes them with. Accounts are looked up by their
+// address, such as "@user@mastodon.social".
+type MastodonRepository interface {
+	LookupAccount(ctx context.Context, acct string) (*mastodon.Account, error)
+	Followers(ctx context.Context, account *mastodon.Account) ([]mastodon.Account, error)
+	Following(ctx context.Context, account *mastodon.Account) ([]mastodon.Account, error)
+}
+
+// MastodonProvider is the Provider of Mastodon accounts. Usernames are the
+// addresses of accounts, such as "@user@mastodon.social".
+type MastodonProvider struct {
+	Repo MastodonRepository
+	now  func() time.Time
+}
+
+// NewMastodonProvider creates a MastodonProvider that looks up accounts with
+// the given MastodonRepository.
+func NewMastodonProvider(repo MastodonRepository) domain.Provider {
+	return &MastodonProvider{
+		Repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Platform returns "mastodon".
+func (p *MastodonProvider) Platform() string {
+	return mastodonPlatform
+}
+
+// LookupUser returns the Mastodon account with the given address. Its
+// Username is the address of the account including the domain of its
+// instance, such as "user@mastodon.social".
+func (p *MastodonProvider) LookupUser(ctx context.Context, username string) (*domain.Account, error) {
+	account, err := p.lookupAccount(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := account.CreatedAt
+	result := &domain.Account{
+		Platform:        mastodonPlatform,
+		ID:              account.ID,
+		Username:        account.Handle(),
+		Name:            account.DisplayName,
+		Description:     account.Note,
+		ProfileImageURL: account.Avatar,
+		URL:             account.URL,
+		Protected:       account.Locked,
+		Metrics:         p.metrics(account),
+	}
+	if !createdAt.IsZero() {
+		result.CreatedAt = &createdAt
+	}
+	return result, nil
+}
+
+// ListFollowers returns the followers of the Mastodon account with the given
+// address.
+func (p *MastodonProvider) ListFollowers(ctx context.Context, username string) ([]domain.Follower, error) {
+	account, err := p.lookupAccount(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	followers, err := p.Repo.Followers(ctx, account)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred retrieving the followers of %s from Mastodon: %w", username, err)
+	}
+	return newMastodonFollowers(followers), nil
+}
+
+// ListFollowing returns the accounts the Mastodon account with the given
+// address follows.
+func (p *MastodonProvider) ListFollowing(ctx context.Context, username string) ([]domain.Follower, error) {
+	account, err := p.lookupAccount(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	following, err := p.Repo.Following(ctx, account)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred retrieving the accounts %s follows from Mastodon: %w", username, err)
+	}
+	return newMastodonFollowers(following), nil
+}
+
+// GetMetrics returns the counts of the Mastodon account with the given
+// address. Statuses are counted as Tweets.
+func (p *MastodonProvider) GetMetrics(ctx context.Context, username string) (*domain.AccountMetrics, error) {
+	account, err := p.lookupAccount(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return p.metrics(account), nil
+}
+
+func (p *MastodonProvider) lookupAccount(ctx context.Context, username string) (*mastodon.Account, error) {
+	account, err := p.Repo.LookupAccount(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred retrieving the account %s from Mastodon: %w", username, err)
+	}
+	return account, nil
+}
+
+// metrics returns the counts of the account as recorded now.
+func (p *MastodonProvider) metrics(account *mastodon.Account) *domain.AccountMetrics {
+	return &domain.AccountMetrics{
+		Platform:   mastodonPlatform,
+		AccountID:  account.ID,
+		RecordedAt: p.now().UTC(),
+		Followers:  account.FollowersCount,
+		Following:  account.FollowingCount,
+		Tweets:     account.StatusesCount,
+	}
+}
+
+func newMastodonFollowers(accounts []mastodon.Account) []domain.Follower {
+	followers := make([]domain.Follower, 0, len(accounts))
+	for _, account := range accounts {
+		followers = append(followers, domain.Follower{
+			ID:              account.ID,
+			Username:        account.Handle(),
+			Name:            account.DisplayName,
+			ProfileImageURL: account.Avatar,
+		})
+	}
+	return followers
+}
@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories/apis/mastodon"
+	"github.com/jake-hansen/followrs/repositories/mocks"
+	"github.com/jake-hansen/followrs/services"
+)
+
+// TestMastodonProvider tests the funcs of MastodonProvider.
+func TestMastodonProvider(t *testing.T) {
+	createdAt := time.Date(2017, time.April, 1, 0, 0, 0, 0, time.UTC)
+	account := &mastodon.Account{
+		ID:             "1",
+		Username:       "alice",
+		Acct:           "alice",
+		DisplayName:    "Alice",
+		Locked:         true,
+		CreatedAt:      createdAt,
+		FollowersCount: 10,
+		FollowingCount: 20,
+		StatusesCount:  30,
+		Instance:       "mastodon.example",
+	}
+
+	t.Run("lookup-user", func(t *testing.T) {
+		repo := new(mocks.MastodonRepository)
+		repo.On("LookupAccount", mock.Anything, "@alice@mastodon.example").Return(account, nil)
+		provider := services.NewMastodonProvider(repo)
+
+		result, err := provider.LookupUser(context.Background(), "@alice@mastodon.example")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "mastodon", result.Platform)
+		assert.Equal(t, "alice@mastodon.example", result.Username)
+		assert.Equal(t, "Alice", result.Name)
+		assert.Equal(t, &createdAt, result.CreatedAt)
+		assert.True(t, result.Protected)
+		assert.Equal(t, int64(10), result.Metrics.Followers)
+		assert.Equal(t, int64(30), result.Metrics.Tweets)
+	})
+
+	t.Run("lookup-user-failed", func(t *testing.T) {
+		repo := new(mocks.MastodonRepository)
+		repo.On("LookupAccount", mock.Anything, "alice").Return(nil, apperrors.ErrNotFound)
+		provider := services.NewMastodonProvider(repo)
+
+		result, err := provider.LookupUser(context.Background(), "alice")
+
+		assert.Nil(t, result)
+		assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+	})
+
+	t.Run("list-followers", func(t *testing.T) {
+		repo := new(mocks.MastodonRepository)
+		repo.On("LookupAccount", mock.Anything, "alice").Return(account, nil)
+		repo.On("Followers", mock.Anything, account).Return([]mastodon.Account{
+			{ID: "2", Acct: "bob", DisplayName: "Bob", Avatar: "https://mastodon.example/2.png", Instance: "mastodon.example"},
+			{ID: "3", Acct: "carol@elsewhere.example", Instance: "mastodon.example"},
+		}, nil)
+		provider := services.NewMastodonProvider(repo)
+
+		followers, err := provider.ListFollowers(context.Background(), "alice")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Follower{
+			{ID: "2", Username: "bob@mastodon.example", Name: "Bob", ProfileImageURL: "https://mastodon.example/2.png"},
+			{ID: "3", Username: "carol@elsewhere.example"},
+		}, followers)
+	})
+
+	t.Run("list-following-failed", func(t *testing.T) {
+		repo := new(mocks.MastodonRepository)
+		repo.On("LookupAccount", mock.Anything, "alice").Return(account, nil)
+		repo.On("Following", mock.Anything, account).Return(nil, apperrors.ErrRateLimited)
+		provider := services.NewMastodonProvider(repo)
+
+		following, err := provider.ListFollowing(context.Background(), "alice")
+
+		assert.Nil(t, following)
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+	})
+
+	t.Run("get-metrics", func(t *testing.T) {
+		repo := new(mocks.MastodonRepository)
+		repo.On("LookupAccount", mock.Anything, "alice").Return(account, nil)
+		provider := services.NewMastodonProvider(repo)
+
+		metrics, err := provider.GetMetrics(context.Background(), "alice")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "mastodon", metrics.Platform)
+		assert.Equal(t, "1", metrics.AccountID)
+		assert.Equal(t, int64(20), metrics.Following)
+	})
+}
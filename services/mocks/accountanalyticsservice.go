@@ -0,0 +1,19 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type AccountAnalyticsService struct {
+	mock.Mock
+}
+
+func (m *AccountAnalyticsService) Get(ctx context.Context, id int64, from time.Time, to time.Time, window int) (*domain.AccountAnalytics, error) {
+	args := m.Called(ctx, id, from, to, window)
+	analytics, _ := args.Get(0).(*domain.AccountAnalytics)
+	return analytics, args.Error(1)
+}
@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type AccountMetricsService struct {
+	mock.Mock
+}
+
+func (m *AccountMetricsService) Get(ctx context.Context, id int64, from time.Time, to time.Time, resolution domain.Resolution) ([]domain.AccountMetrics, error) {
+	args := m.Called(ctx, id, from, to, resolution)
+	metrics, _ := args.Get(0).([]domain.AccountMetrics)
+	return metrics, args.Error(1)
+}
+
+func (m *AccountMetricsService) Record(ctx context.Context, platform string, username string) (*domain.AccountMetrics, error) {
+	args := m.Called(ctx, platform, username)
+	metrics, _ := args.Get(0).(*domain.AccountMetrics)
+	return metrics, args.Error(1)
+}
+
+func (m *AccountMetricsService) RecordByID(ctx context.Context, platform string, id string, username string) (*domain.AccountMetrics, error) {
+	args := m.Called(ctx, platform, id, username)
+	metrics, _ := args.Get(0).(*domain.AccountMetrics)
+	return metrics, args.Error(1)
+}
@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type FollowerDiffService struct {
+	mock.Mock
+}
+
+func (m *FollowerDiffService) RecordSnapshot(ctx context.Context, username string) (*domain.FollowerSnapshot, error) {
+	args := m.Called(ctx, username)
+	snapshot, _ := args.Get(0).(*domain.FollowerSnapshot)
+	return snapshot, args.Error(1)
+}
+
+func (m *FollowerDiffService) RecordSnapshotByID(ctx context.Context, id string) (*domain.FollowerSnapshot, error) {
+	args := m.Called(ctx, id)
+	snapshot, _ := args.Get(0).(*domain.FollowerSnapshot)
+	return snapshot, args.Error(1)
+}
+
+func (m *FollowerDiffService) GetChanges(ctx context.Context, username string, since time.Time, opts ...domain.TwitterLookupOptions) (*domain.FollowerChanges, error) {
+	args := m.Called(withOptions([]interface{}{ctx, username, since}, opts)...)
+	changes, _ := args.Get(0).(*domain.FollowerChanges)
+	return changes, args.Error(1)
+}
@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type Provider struct {
+	mock.Mock
+}
+
+func (m *Provider) Platform() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *Provider) LookupUser(ctx context.Context, username string) (*domain.Account, error) {
+	args := m.Called(ctx, username)
+	account, _ := args.Get(0).(*domain.Account)
+	return account, args.Error(1)
+}
+
+func (m *Provider) ListFollowers(ctx context.Context, username string) ([]domain.Follower, error) {
+	args := m.Called(ctx, username)
+	followers, _ := args.Get(0).([]domain.Follower)
+	return followers, args.Error(1)
+}
+
+func (m *Provider) ListFollowing(ctx context.Context, username string) ([]domain.Follower, error) {
+	args := m.Called(ctx, username)
+	following, _ := args.Get(0).([]domain.Follower)
+	return following, args.Error(1)
+}
+
+func (m *Provider) GetMetrics(ctx context.Context, username string) (*domain.AccountMetrics, error) {
+	args := m.Called(ctx, username)
+	metrics, _ := args.Get(0).(*domain.AccountMetrics)
+	return metrics, args.Error(1)
+}
@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"context"
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type TrackedAccountService struct {
+	mock.Mock
+}
+
+func (m *TrackedAccountService) Track(ctx context.Context, platform string, username string, poll domain.PollSettings) (*domain.TrackedAccount, error) {
+	args := m.Called(ctx, platform, username, poll)
+	account, _ := args.Get(0).(*domain.TrackedAccount)
+	return account, args.Error(1)
+}
+
+func (m *TrackedAccountService) Get(ctx context.Context, id int64) (*domain.TrackedAccount, error) {
+	args := m.Called(ctx, id)
+	account, _ := args.Get(0).(*domain.TrackedAccount)
+	return account, args.Error(1)
+}
+
+func (m *TrackedAccountService) List(ctx context.Context) ([]domain.TrackedAccount, error) {
+	args := m.Called(ctx)
+	accounts, _ := args.Get(0).([]domain.TrackedAccount)
+	return accounts, args.Error(1)
+}
+
+func (m *TrackedAccountService) Untrack(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"context"
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type TwitterConnectService struct {
+	mock.Mock
+}
+
+func (m *TwitterConnectService) Authorize(ctx context.Context) (string, string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *TwitterConnectService) Complete(ctx context.Context, state string, code string) (*domain.TwitterConnection, error) {
+	args := m.Called(ctx, state, code)
+	connection, _ := args.Get(0).(*domain.TwitterConnection)
+	return connection, args.Error(1)
+}
+
+func (m *TwitterConnectService) OnBehalfOf(ctx context.Context, userID string) (context.Context, error) {
+	args := m.Called(ctx, userID)
+	onBehalfOf, _ := args.Get(0).(context.Context)
+	return onBehalfOf, args.Error(1)
+}
+
+func (m *TwitterConnectService) Session(ctx context.Context, userID string) (string, error) {
+	args := m.Called(ctx, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *TwitterConnectService) SessionUser(ctx context.Context, token string) (string, error) {
+	args := m.Called(ctx, token)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,79 @@
+package mocks
+
+import (
+	"context"
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type TwitterService struct {
+	mock.Mock
+}
+
+func (m *TwitterService) GetUser(ctx context.Context, username string, opts ...domain.TwitterLookupOptions) (*domain.TwitterUser, error) {
+	args := m.Called(withOptions([]interface{}{ctx, username}, opts)...)
+	user, _ := args.Get(0).(*domain.TwitterUser)
+	return user, args.Error(1)
+}
+
+func (m *TwitterService) GetUserByID(ctx context.Context, id string, opts ...domain.TwitterLookupOptions) (*domain.TwitterUser, error) {
+	args := m.Called(withOptions([]interface{}{ctx, id}, opts)...)
+	user, _ := args.Get(0).(*domain.TwitterUser)
+	return user, args.Error(1)
+}
+
+func (m *TwitterService) GetUsers(ctx context.Context, usernames []string, opts ...domain.TwitterLookupOptions) (*domain.TwitterUsers, error) {
+	args := m.Called(withOptions([]interface{}{ctx, usernames}, opts)...)
+	users, _ := args.Get(0).(*domain.TwitterUsers)
+	return users, args.Error(1)
+}
+
+func (m *TwitterService) GetUsersByID(ctx context.Context, ids []string, opts ...domain.TwitterLookupOptions) (*domain.TwitterUsers, error) {
+	args := m.Called(withOptions([]interface{}{ctx, ids}, opts)...)
+	users, _ := args.Get(0).(*domain.TwitterUsers)
+	return users, args.Error(1)
+}
+
+func (m *TwitterService) GetAuthenticatedUser(ctx context.Context) (*domain.TwitterUser, error) {
+	args := m.Called(ctx)
+	user, _ := args.Get(0).(*domain.TwitterUser)
+	return user, args.Error(1)
+}
+
+func (m *TwitterService) GetFollowers(ctx context.Context, username string, opts ...domain.TwitterLookupOptions) ([]domain.TwitterUser, error) {
+	args := m.Called(withOptions([]interface{}{ctx, username}, opts)...)
+	users, _ := args.Get(0).([]domain.TwitterUser)
+	return users, args.Error(1)
+}
+
+func (m *TwitterService) GetFollowersByID(ctx context.Context, id string, opts ...domain.TwitterLookupOptions) ([]domain.TwitterUser, error) {
+	args := m.Called(withOptions([]interface{}{ctx, id}, opts)...)
+	users, _ := args.Get(0).([]domain.TwitterUser)
+	return users, args.Error(1)
+}
+
+func (m *TwitterService) GetFollowersRateLimit() domain.RateLimit {
+	args := m.Called()
+	return args.Get(0).(domain.RateLimit)
+}
+
+func (m *TwitterService) GetFollowing(ctx context.Context, username string, opts ...domain.TwitterLookupOptions) ([]domain.TwitterUser, error) {
+	args := m.Called(withOptions([]interface{}{ctx, username}, opts)...)
+	users, _ := args.Get(0).([]domain.TwitterUser)
+	return users, args.Error(1)
+}
+
+func (m *TwitterService) GetRelationships(ctx context.Context, username string, opts ...domain.TwitterLookupOptions) (*domain.TwitterRelationships, error) {
+	args := m.Called(withOptions([]interface{}{ctx, username}, opts)...)
+	relationships, _ := args.Get(0).(*domain.TwitterRelationships)
+	return relationships, args.Error(1)
+}
+
+// withOptions appends the lookup options of a call, if any were given, to its
+// arguments, so that calls without options match expectations without them.
+func withOptions(args []interface{}, opts []domain.TwitterLookupOptions) []interface{} {
+	for _, opt := range opts {
+		args = append(args, opt)
+	}
+	return args
+}
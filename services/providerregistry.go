@@ -0,0 +1,44 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jake-hansen/followrs/domain"
+)
+
+// ProviderRegistry finds Providers by the name of their platform.
+type ProviderRegistry struct {
+	providers map[string]domain.Provider
+}
+
+// NewProviderRegistry creates a ProviderRegistry of the given Providers. A
+// Provider replaces any earlier Provider of the same platform.
+func NewProviderRegistry(providers ...domain.Provider) domain.ProviderRegistry {
+	registry := &ProviderRegistry{
+		providers: make(map[string]domain.Provider, len(providers)),
+	}
+	for _, provider := range providers {
+		registry.providers[provider.Platform()] = provider
+	}
+	return registry
+}
+
+// Provider returns the Provider of the given platform.
+func (r *ProviderRegistry) Provider(platform string) (domain.Provider, error) {
+	provider, ok := r.providers[platform]
+	if !ok {
+		return nil, fmt.Errorf("could not find provider of %s: %w", platform, domain.ErrUnsupportedPlatform)
+	}
+	return provider, nil
+}
+
+// Platforms returns the names of the platforms of every registered Provider.
+func (r *ProviderRegistry) Platforms() []string {
+	platforms := make([]string, 0, len(r.providers))
+	for platform := range r.providers {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	return platforms
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+)
+
+// TrackedAccountService manages the accounts tracked by the server.
+type TrackedAccountService struct {
+	Repo           domain.TrackedAccountRepository
+	TwitterService domain.TwitterService
+	Providers      domain.ProviderRegistry
+	now            func() time.Time
+}
+
+// NewTrackedAccountService creates a TrackedAccountService that stores accounts
+// in the given repository. Twitter accounts are looked up with the given
+// TwitterService, and accounts of other platforms with their Provider.
+func NewTrackedAccountService(repo domain.TrackedAccountRepository, twitterService domain.TwitterService, providers domain.ProviderRegistry) domain.TrackedAccountService {
+	return &TrackedAccountService{
+		Repo:           repo,
+		TwitterService: twitterService,
+		Providers:      providers,
+		now:            time.Now,
+	}
+}
+
+// Track starts tracking the account with the given username on the given
+// platform. The account is looked up on its platform so that it is tracked
+// by its platform user ID.
+func (s *TrackedAccountService) Track(ctx context.Context, platform string, username string, poll domain.PollSettings) (*domain.TrackedAccount, error) {
+	id, username, err := s.lookup(ctx, platform, username)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &domain.TrackedAccount{
+		Platform:       platform,
+		PlatformUserID: id,
+		Username:       username,
+		AddedAt:        s.now(),
+		Poll:           poll,
+	}
+
+	err = s.Repo.Add(ctx, account)
+	if err != nil {
+		return nil, fmt.Errorf("could not track %s on %s: %w", username, platform, err)
+	}
+
+	return account, nil
+}
+
+// Get returns the tracked account with the given ID.
+func (s *TrackedAccountService) Get(ctx context.Context, id int64) (*domain.TrackedAccount, error) {
+	return s.Repo.Get(ctx, id)
+}
+
+// List returns every tracked account.
+func (s *TrackedAccountService) List(ctx context.Context) ([]domain.TrackedAccount, error) {
+	return s.Repo.List(ctx)
+}
+
+// Untrack stops tracking the account with the given ID.
+func (s *TrackedAccountService) Untrack(ctx context.Context, id int64) error {
+	return s.Repo.Delete(ctx, id)
+}
+
+// lookup returns the ID and username of the account with the given username
+// on the given platform.
+func (s *TrackedAccountService) lookup(ctx context.Context, platform string, username string) (string, string, error) {
+	if platform == twitterPlatform {
+		user, err := s.TwitterService.GetUser(ctx, username)
+		if err != nil {
+			return "", "", err
+		}
+		return user.ID, user.Username, nil
+	}
+
+	provider, err := s.Providers.Provider(platform)
+	if err != nil {
+		return "", "", fmt.Errorf("could not track %s on %s: %w", username, platform, err)
+	}
+	account, err := provider.LookupUser(ctx, username)
+	if err != nil {
+		return "", "", err
+	}
+	return account.ID, account.Username, nil
+}
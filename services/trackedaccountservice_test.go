@@ -0,0 +1,53 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories"
+	"github.com/jake-hansen/followrs/services"
+	"github.com/jake-hansen/followrs/services/mocks"
+)
+
+// TestTrack tests TrackedAccountService's Track func.
+func TestTrack(t *testing.T) {
+	t.Run("twitter", func(t *testing.T) {
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetUser", mock.Anything, "test").Return(&domain.TwitterUser{ID: "1", Username: "Test"}, nil)
+		service := services.NewTrackedAccountService(repositories.NewInMemoryTrackedAccountRepository(), twitterService, services.NewProviderRegistry())
+
+		account, err := service.Track(context.Background(), "twitter", "test", domain.PollSettings{Enabled: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1", account.PlatformUserID)
+		assert.Equal(t, "Test", account.Username)
+	})
+
+	t.Run("other-platform", func(t *testing.T) {
+		provider := new(mocks.Provider)
+		provider.On("Platform").Return("youtube")
+		provider.On("LookupUser", mock.Anything, "test").Return(&domain.Account{Platform: "youtube", ID: "UC1", Username: "@test"}, nil)
+		service := services.NewTrackedAccountService(repositories.NewInMemoryTrackedAccountRepository(), new(mocks.TwitterService), services.NewProviderRegistry(provider))
+
+		account, err := service.Track(context.Background(), "youtube", "test", domain.PollSettings{Enabled: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "youtube", account.Platform)
+		assert.Equal(t, "UC1", account.PlatformUserID)
+		assert.Equal(t, "@test", account.Username)
+	})
+
+	t.Run("unsupported-platform", func(t *testing.T) {
+		service := services.NewTrackedAccountService(repositories.NewInMemoryTrackedAccountRepository(), new(mocks.TwitterService), services.NewProviderRegistry())
+
+		account, err := service.Track(context.Background(), "myspace", "test", domain.PollSettings{})
+
+		assert.Nil(t, account)
+		assert.True(t, errors.Is(err, domain.ErrUnsupportedPlatform))
+	})
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories/apis/twitch"
+)
+
+// twitchPlatform is the name of the platform of Twitch accounts.
+const twitchPlatform = "twitch"
+
+// TwitchRepository retrieves users and their followers from the Twitch API in
+// the types the API describes them with. Users are looked up by their login,
+// and their followers by their ID.
+type TwitchRepository interface {
+	GetUser(ctx context.Context, login string) (*twitch.User, error)
+	GetFollowerCount(ctx context.Context, broadcasterID string) (int64, error)
+	GetFollowers(ctx context.Context, broadcasterID string) ([]twitch.Follower, int64, error)
+}
+
+// TwitchProvider is the Provider of Twitch accounts. Usernames are the logins
+// of users, such as "twitchdev".
+type TwitchProvider struct {
+	Repo TwitchRepository
+	now  func() time.Time
+}
+
+// NewTwitchProvider creates a TwitchProvider that looks up users with the
+// given TwitchRepository.
+func NewTwitchProvider(repo TwitchRepository) domain.Provider {
+	return &TwitchProvider{
+		Repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Platform returns "twitch".
+func (p *TwitchProvider) Platform() string {
+	return twitchPlatform
+}
+
+// LookupUser returns the Twitch user with the given login.
+func (p *TwitchProvider) LookupUser(ctx context.Context, username string) (*domain.Account, error) {
+	user, metrics, err := p.getUserMetrics(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &domain.Account{
+		Platform:        twitchPlatform,
+		ID:              user.ID,
+		Username:        user.Login,
+		Name:            user.DisplayName,
+		Description:     user.Description,
+		ProfileImageURL: user.ProfileImageURL,
+		URL:             fmt.Sprintf("https://www.twitch.tv/%s", user.Login),
+		CreatedAt:       user.CreatedAt,
+		Metrics:         metrics,
+	}
+	return account, nil
+}
+
+// ListFollowers returns the followers of the Twitch user with the given
+// login. Twitch only lists them to the broadcaster and moderators of the
+// channel, so domain.ErrUnsupportedPlatform is returned when it withholds
+// them.
+func (p *TwitchProvider) ListFollowers(ctx context.Context, username string) ([]domain.Follower, error) {
+	user, err := p.getUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	followers, total, err := p.Repo.GetFollowers(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred retrieving the followers of %s from Twitch: %w", username, err)
+	}
+	if len(followers) == 0 && total > 0 {
+		return nil, fmt.Errorf("Twitch does not list the followers of %s: %w", username, domain.ErrUnsupportedPlatform)
+	}
+	return newTwitchFollowers(followers), nil
+}
+
+// ListFollowing fails with domain.ErrUnsupportedPlatform, since Twitch only
+// lists the channels a user follows to that user.
+func (p *TwitchProvider) ListFollowing(ctx context.Context, username string) ([]domain.Follower, error) {
+	return nil, fmt.Errorf("Twitch does not list the channels %s follows: %w", username, domain.ErrUnsupportedPlatform)
+}
+
+// GetMetrics returns the follower count of the Twitch user with the given
+// login.
+func (p *TwitchProvider) GetMetrics(ctx context.Context, username string) (*domain.AccountMetrics, error) {
+	_, metrics, err := p.getUserMetrics(ctx, username)
+	return metrics, err
+}
+
+// GetMetricsByID returns the follower count of the Twitch user with the given
+// ID, which stays the same when the user changes their login. Twitch counts
+// followers by the ID of the broadcaster, so the user isn't looked up.
+func (p *TwitchProvider) GetMetricsByID(ctx context.Context, id string) (*domain.AccountMetrics, error) {
+	followers, err := p.Repo.GetFollowerCount(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred retrieving the follower count of the user with id %s from Twitch: %w", id, err)
+	}
+
+	metrics := &domain.AccountMetrics{
+		Platform:   twitchPlatform,
+		AccountID:  id,
+		RecordedAt: p.now().UTC(),
+		Followers:  followers,
+	}
+	return metrics, nil
+}
+
+func (p *TwitchProvider) getUser(ctx context.Context, username string) (*twitch.User, error) {
+	user, err := p.Repo.GetUser(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred retrieving the user %s from Twitch: %w", username, err)
+	}
+	return user, nil
+}
+
+// getUserMetrics returns the user with the given login and its counts as
+// recorded now. Twitch doesn't count followers in users, so they are counted
+// separately.
+func (p *TwitchProvider) getUserMetrics(ctx context.Context, username string) (*twitch.User, *domain.AccountMetrics, error) {
+	user, err := p.getUser(ctx, username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	followers, err := p.Repo.GetFollowerCount(ctx, user.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("an error occurred retrieving the follower count of %s from Twitch: %w", username, err)
+	}
+
+	metrics := &domain.AccountMetrics{
+		Platform:   twitchPlatform,
+		AccountID:  user.ID,
+		RecordedAt: p.now().UTC(),
+		Followers:  followers,
+	}
+	return user, metrics, nil
+}
+
+func newTwitchFollowers(followers []twitch.Follower) []domain.Follower {
+	accounts := make([]domain.Follower, 0, len(followers))
+	for _, follower := range followers {
+		accounts = append(accounts, domain.Follower{
+			ID:       follower.UserID,
+			Username: follower.UserLogin,
+			Name:     follower.UserName,
+		})
+	}
+	return accounts
+}
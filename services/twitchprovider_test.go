@@ -0,0 +1,119 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories/apis/twitch"
+	"github.com/jake-hansen/followrs/repositories/mocks"
+	"github.com/jake-hansen/followrs/services"
+)
+
+// TestTwitchProvider tests the funcs of TwitchProvider.
+func TestTwitchProvider(t *testing.T) {
+	createdAt := time.Date(2016, time.December, 14, 20, 32, 28, 0, time.UTC)
+	user := &twitch.User{
+		ID:              "141981764",
+		Login:           "twitchdev",
+		DisplayName:     "TwitchDev",
+		Description:     "Supporting third-party developers",
+		ProfileImageURL: "https://static-cdn.jtvnw.net/profile.png",
+		CreatedAt:       &createdAt,
+	}
+
+	t.Run("lookup-user", func(t *testing.T) {
+		repo := new(mocks.TwitchRepository)
+		repo.On("GetUser", mock.Anything, "twitchdev").Return(user, nil)
+		repo.On("GetFollowerCount", mock.Anything, "141981764").Return(int64(8), nil)
+		provider := services.NewTwitchProvider(repo)
+
+		account, err := provider.LookupUser(context.Background(), "twitchdev")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "twitch", account.Platform)
+		assert.Equal(t, "141981764", account.ID)
+		assert.Equal(t, "twitchdev", account.Username)
+		assert.Equal(t, "TwitchDev", account.Name)
+		assert.Equal(t, "https://www.twitch.tv/twitchdev", account.URL)
+		assert.Equal(t, &createdAt, account.CreatedAt)
+		assert.Equal(t, int64(8), account.Metrics.Followers)
+	})
+
+	t.Run("metrics-by-id", func(t *testing.T) {
+		repo := new(mocks.TwitchRepository)
+		repo.On("GetFollowerCount", mock.Anything, "141981764").Return(int64(8), nil)
+		provider := services.NewTwitchProvider(repo).(domain.IDProvider)
+
+		metrics, err := provider.GetMetricsByID(context.Background(), "141981764")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "twitch", metrics.Platform)
+		assert.Equal(t, "141981764", metrics.AccountID)
+		assert.Equal(t, int64(8), metrics.Followers)
+		repo.AssertNotCalled(t, "GetUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("lookup-user-failed", func(t *testing.T) {
+		repo := new(mocks.TwitchRepository)
+		repo.On("GetUser", mock.Anything, "twitchdev").Return(nil, apperrors.ErrNotFound)
+		provider := services.NewTwitchProvider(repo)
+
+		account, err := provider.LookupUser(context.Background(), "twitchdev")
+
+		assert.Nil(t, account)
+		assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+		repo.AssertNotCalled(t, "GetFollowerCount", mock.Anything, mock.Anything)
+	})
+
+	t.Run("list-followers", func(t *testing.T) {
+		repo := new(mocks.TwitchRepository)
+		repo.On("GetUser", mock.Anything, "twitchdev").Return(user, nil)
+		repo.On("GetFollowers", mock.Anything, "141981764").Return([]twitch.Follower{{UserID: "1", UserLogin: "one", UserName: "One"}}, int64(1), nil)
+		provider := services.NewTwitchProvider(repo)
+
+		followers, err := provider.ListFollowers(context.Background(), "twitchdev")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Follower{{ID: "1", Username: "one", Name: "One"}}, followers)
+	})
+
+	t.Run("list-followers-withheld", func(t *testing.T) {
+		repo := new(mocks.TwitchRepository)
+		repo.On("GetUser", mock.Anything, "twitchdev").Return(user, nil)
+		repo.On("GetFollowers", mock.Anything, "141981764").Return([]twitch.Follower(nil), int64(8), nil)
+		provider := services.NewTwitchProvider(repo)
+
+		followers, err := provider.ListFollowers(context.Background(), "twitchdev")
+
+		assert.Nil(t, followers)
+		assert.True(t, errors.Is(err, domain.ErrUnsupportedPlatform))
+	})
+
+	t.Run("list-following", func(t *testing.T) {
+		provider := services.NewTwitchProvider(new(mocks.TwitchRepository))
+
+		following, err := provider.ListFollowing(context.Background(), "twitchdev")
+
+		assert.Nil(t, following)
+		assert.True(t, errors.Is(err, domain.ErrUnsupportedPlatform))
+	})
+
+	t.Run("get-metrics", func(t *testing.T) {
+		repo := new(mocks.TwitchRepository)
+		repo.On("GetUser", mock.Anything, "twitchdev").Return(user, nil)
+		repo.On("GetFollowerCount", mock.Anything, "141981764").Return(int64(0), apperrors.ErrRateLimited)
+		provider := services.NewTwitchProvider(repo)
+
+		metrics, err := provider.GetMetrics(context.Background(), "twitchdev")
+
+		assert.Nil(t, metrics)
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+	})
+}
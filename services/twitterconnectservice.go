@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories/apis/twitter"
+)
+
+// authorizationTimeout is how long the owner of a Twitter account has to
+// authorize a connection before it must be started again.
+const authorizationTimeout = 10 * time.Minute
+
+// defaultMaxPendingAuthorizations is how many connections may be waiting to
+// be authorized at once unless MaxPendingAuthorizations says otherwise.
+const defaultMaxPendingAuthorizations = 10000
+
+// sessionTimeout is how long a session token identifies the owner of a
+// connected Twitter account before they must connect it again.
+const sessionTimeout = 30 * 24 * time.Hour
+
+// pendingAuthorization is a connection that is waiting to be authorized.
+type pendingAuthorization struct {
+	codeVerifier string
+	expires      time.Time
+}
+
+// TwitterAuthorizer performs Twitter's OAuth 2.0 authorization code flow with
+// PKCE and authenticates requests with the resulting tokens.
+type TwitterAuthorizer interface {
+	AuthorizationURL(state string, codeChallenge string) string
+	Exchange(ctx context.Context, code string, codeVerifier string) (*twitter.Token, error)
+	WithToken(ctx context.Context, token twitter.Token) context.Context
+	WithUser(ctx context.Context, userID string, token twitter.Token, onRefresh func(twitter.Token)) context.Context
+}
+
+// TwitterConnectService connects Twitter accounts using OAuth 2.0 and makes
+// requests on their behalf.
+type TwitterConnectService struct {
+	Authorizer     TwitterAuthorizer
+	Repo           domain.TwitterConnectionRepository
+	TwitterService domain.TwitterService
+	SessionKey     []byte // Key that session tokens are signed with.
+
+	// MaxPendingAuthorizations is how many connections may be waiting to be
+	// authorized at once. Beginning another discards the one that was begun
+	// first, so that abandoned authorizations can't use up memory before
+	// they expire.
+	MaxPendingAuthorizations int
+
+	now func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]pendingAuthorization
+}
+
+// NewTwitterConnectService creates a TwitterConnectService that stores
+// connections in the given repository and signs session tokens with the given
+// key.
+func NewTwitterConnectService(authorizer TwitterAuthorizer, repo domain.TwitterConnectionRepository, twitterService domain.TwitterService, sessionKey []byte) domain.TwitterConnectService {
+	return &TwitterConnectService{
+		Authorizer:               authorizer,
+		Repo:                     repo,
+		TwitterService:           twitterService,
+		SessionKey:               sessionKey,
+		MaxPendingAuthorizations: defaultMaxPendingAuthorizations,
+		now:                      time.Now,
+		pending:                  make(map[string]pendingAuthorization),
+	}
+}
+
+// Authorize begins connecting a Twitter account. A random state identifies
+// the authorization when its owner is redirected back, and a random code
+// verifier proves that the authorization code is exchanged by followrs.
+func (s *TwitterConnectService) Authorize(ctx context.Context) (string, string, error) {
+	state, err := randomString()
+	if err != nil {
+		return "", "", fmt.Errorf("could not begin authorization: %w", err)
+	}
+	codeVerifier, err := randomString()
+	if err != nil {
+		return "", "", fmt.Errorf("could not begin authorization: %w", err)
+	}
+
+	s.mu.Lock()
+	s.prunePending()
+	s.pending[state] = pendingAuthorization{
+		codeVerifier: codeVerifier,
+		expires:      s.now().Add(authorizationTimeout),
+	}
+	s.mu.Unlock()
+
+	challenge := sha256.Sum256([]byte(codeVerifier))
+	return s.Authorizer.AuthorizationURL(state, base64.RawURLEncoding.EncodeToString(challenge[:])), state, nil
+}
+
+// prunePending discards pending authorizations that have expired, and then
+// the ones that expire soonest until there is room for another. s.mu must be
+// held.
+func (s *TwitterConnectService) prunePending() {
+	now := s.now()
+	for state, authorization := range s.pending {
+		if !now.Before(authorization.expires) {
+			delete(s.pending, state)
+		}
+	}
+
+	for len(s.pending) > 0 && len(s.pending) >= s.MaxPendingAuthorizations {
+		var oldest string
+		for state, authorization := range s.pending {
+			if oldest == "" || authorization.expires.Before(s.pending[oldest].expires) {
+				oldest = state
+			}
+		}
+		delete(s.pending, oldest)
+	}
+}
+
+// Complete exchanges the authorization code for a token, looks up the account
+// the token belongs to, and stores the connection.
+func (s *TwitterConnectService) Complete(ctx context.Context, state string, code string) (*domain.TwitterConnection, error) {
+	s.mu.Lock()
+	authorization, ok := s.pending[state]
+	delete(s.pending, state)
+	s.mu.Unlock()
+
+	if !ok || !s.now().Before(authorization.expires) {
+		return nil, domain.ErrInvalidAuthorizationState
+	}
+
+	token, err := s.Authorizer.Exchange(ctx, code, authorization.codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.TwitterService.GetAuthenticatedUser(s.Authorizer.WithToken(ctx, *token))
+	if err != nil {
+		return nil, err
+	}
+
+	connection := &domain.TwitterConnection{
+		UserID:      user.ID,
+		Username:    user.Username,
+		ConnectedAt: s.now(),
+	}
+	setConnectionToken(connection, *token)
+
+	err = s.Repo.Save(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	return connection, nil
+}
+
+// OnBehalfOf returns a copy of ctx whose requests to Twitter are made on
+// behalf of the connected account with the given user ID. The connection is
+// updated whenever its token is refreshed.
+func (s *TwitterConnectService) OnBehalfOf(ctx context.Context, userID string) (context.Context, error) {
+	connection, err := s.Repo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	token := twitter.Token{
+		AccessToken:  connection.AccessToken,
+		RefreshToken: connection.RefreshToken,
+		Expiry:       connection.Expiry,
+		Scopes:       connection.Scopes,
+	}
+
+	onRefresh := func(token twitter.Token) {
+		refreshed := *connection
+		setConnectionToken(&refreshed, token)
+		// The token must be saved even if the request that refreshed it is
+		// cancelled, since the previous refresh token is no longer valid.
+		if err := s.Repo.Save(context.Background(), &refreshed); err != nil {
+			log.Printf("could not save refreshed token of Twitter user %s: %s", userID, err.Error())
+		}
+	}
+
+	return s.Authorizer.WithUser(ctx, userID, token, onRefresh), nil
+}
+
+// Session returns a token that identifies the owner of the connected account
+// with the given user ID. The token carries the user ID and its expiry, signed
+// with the SessionKey so that it can't be forged or extended.
+func (s *TwitterConnectService) Session(ctx context.Context, userID string) (string, error) {
+	if len(s.SessionKey) == 0 {
+		return "", fmt.Errorf("could not issue session: no session key is configured")
+	}
+
+	payload := userID + "." + strconv.FormatInt(s.now().Add(sessionTimeout).Unix(), 10)
+	return payload + "." + s.sessionSignature(payload), nil
+}
+
+// SessionUser returns the user ID of the connected account whose owner the
+// session token was issued to. It fails with ErrInvalidSession if the token's
+// signature doesn't match or it has expired.
+func (s *TwitterConnectService) SessionUser(ctx context.Context, token string) (string, error) {
+	separator := strings.LastIndex(token, ".")
+	if len(s.SessionKey) == 0 || separator < 0 {
+		return "", domain.ErrInvalidSession
+	}
+
+	payload, signature := token[:separator], token[separator+1:]
+	if !hmac.Equal([]byte(signature), []byte(s.sessionSignature(payload))) {
+		return "", domain.ErrInvalidSession
+	}
+
+	parts := strings.Split(payload, ".")
+	if len(parts) != 2 {
+		return "", domain.ErrInvalidSession
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || !s.now().Before(time.Unix(expires, 0)) {
+		return "", domain.ErrInvalidSession
+	}
+	return parts[0], nil
+}
+
+// sessionSignature signs the payload of a session token with the SessionKey.
+func (s *TwitterConnectService) sessionSignature(payload string) string {
+	mac := hmac.New(sha256.New, s.SessionKey)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// setConnectionToken sets the token of the connection.
+func setConnectionToken(connection *domain.TwitterConnection, token twitter.Token) {
+	connection.AccessToken = token.AccessToken
+	connection.RefreshToken = token.RefreshToken
+	connection.Expiry = token.Expiry
+	connection.Scopes = token.Scopes
+}
+
+// randomString returns a random URL-safe string that is long enough to be
+// used as an OAuth 2.0 state or PKCE code verifier.
+func randomString() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
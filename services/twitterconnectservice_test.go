@@ -0,0 +1,189 @@
+package services_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories"
+	"github.com/jake-hansen/followrs/repositories/apis/twitter"
+	"github.com/jake-hansen/followrs/services"
+	"github.com/jake-hansen/followrs/services/mocks"
+)
+
+// sessionKey is the key that session tokens are signed with in tests.
+var sessionKey = []byte("session-key")
+
+// tokenContextKey is the key of the token carried by contexts from fakeAuthorizer.
+type tokenContextKey struct{}
+
+// fakeAuthorizer is a TwitterAuthorizer that issues the token it is given and
+// records the code verifier each code is exchanged with.
+type fakeAuthorizer struct {
+	token         twitter.Token
+	codeVerifiers map[string]string
+	onRefresh     func(twitter.Token)
+}
+
+func (a *fakeAuthorizer) AuthorizationURL(state string, codeChallenge string) string {
+	return "https://twitter.test/authorize?" + url.Values{"state": {state}, "code_challenge": {codeChallenge}}.Encode()
+}
+
+func (a *fakeAuthorizer) Exchange(ctx context.Context, code string, codeVerifier string) (*twitter.Token, error) {
+	a.codeVerifiers[code] = codeVerifier
+	token := a.token
+	return &token, nil
+}
+
+func (a *fakeAuthorizer) WithToken(ctx context.Context, token twitter.Token) context.Context {
+	return context.WithValue(ctx, tokenContextKey{}, token)
+}
+
+func (a *fakeAuthorizer) WithUser(ctx context.Context, userID string, token twitter.Token, onRefresh func(twitter.Token)) context.Context {
+	a.onRefresh = onRefresh
+	return context.WithValue(ctx, tokenContextKey{}, token)
+}
+
+// TestTwitterConnectService tests connecting Twitter accounts.
+func TestTwitterConnectService(t *testing.T) {
+	token := twitter.Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour).UTC(), Scopes: []string{"users.read"}}
+	withToken := mock.MatchedBy(func(ctx context.Context) bool {
+		token, ok := ctx.Value(tokenContextKey{}).(twitter.Token)
+		return ok && token.AccessToken == "access"
+	})
+
+	t.Run("success", func(t *testing.T) {
+		authorizer := &fakeAuthorizer{token: token, codeVerifiers: make(map[string]string)}
+		repo := repositories.NewInMemoryTwitterConnectionRepository()
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetAuthenticatedUser", withToken).Return(&domain.TwitterUser{ID: "1", Username: "test"}, nil)
+		service := services.NewTwitterConnectService(authorizer, repo, twitterService, sessionKey)
+
+		authorizationURL, state, err := service.Authorize(context.Background())
+		assert.NoError(t, err)
+		parsed, _ := url.Parse(authorizationURL)
+		assert.NotEmpty(t, state)
+		assert.Equal(t, state, parsed.Query().Get("state"))
+
+		connection, err := service.Complete(context.Background(), state, "code")
+		assert.NoError(t, err)
+		assert.Equal(t, "1", connection.UserID)
+		assert.Equal(t, "test", connection.Username)
+		assert.Equal(t, "access", connection.AccessToken)
+
+		challenge := sha256.Sum256([]byte(authorizer.codeVerifiers["code"]))
+		assert.Equal(t, base64.RawURLEncoding.EncodeToString(challenge[:]), parsed.Query().Get("code_challenge"))
+
+		stored, err := repo.Get(context.Background(), "1")
+		assert.NoError(t, err)
+		assert.Equal(t, connection, stored)
+
+		_, err = service.Complete(context.Background(), state, "code")
+		assert.Equal(t, domain.ErrInvalidAuthorizationState, err)
+		twitterService.AssertExpectations(t)
+	})
+
+	t.Run("unknown-state", func(t *testing.T) {
+		authorizer := &fakeAuthorizer{token: token, codeVerifiers: make(map[string]string)}
+		service := services.NewTwitterConnectService(authorizer, repositories.NewInMemoryTwitterConnectionRepository(), new(mocks.TwitterService), sessionKey)
+
+		connection, err := service.Complete(context.Background(), "unknown", "code")
+
+		assert.Nil(t, connection)
+		assert.Equal(t, domain.ErrInvalidAuthorizationState, err)
+		assert.Empty(t, authorizer.codeVerifiers)
+	})
+
+	t.Run("oldest-pending-authorization-discarded", func(t *testing.T) {
+		authorizer := &fakeAuthorizer{token: token, codeVerifiers: make(map[string]string)}
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetAuthenticatedUser", withToken).Return(&domain.TwitterUser{ID: "1", Username: "test"}, nil)
+		service := services.NewTwitterConnectService(authorizer, repositories.NewInMemoryTwitterConnectionRepository(), twitterService, sessionKey)
+		service.(*services.TwitterConnectService).MaxPendingAuthorizations = 2
+
+		var states []string
+		for i := 0; i < 3; i++ {
+			_, state, err := service.Authorize(context.Background())
+			assert.NoError(t, err)
+			states = append(states, state)
+			time.Sleep(time.Millisecond)
+		}
+
+		_, err := service.Complete(context.Background(), states[0], "code")
+		assert.Equal(t, domain.ErrInvalidAuthorizationState, err)
+		for _, state := range states[1:] {
+			_, err := service.Complete(context.Background(), state, "code")
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("on-behalf-of-saves-refreshed-token", func(t *testing.T) {
+		authorizer := &fakeAuthorizer{}
+		repo := repositories.NewInMemoryTwitterConnectionRepository()
+		repo.Save(context.Background(), &domain.TwitterConnection{UserID: "1", Username: "test", AccessToken: "access", RefreshToken: "refresh"})
+		service := services.NewTwitterConnectService(authorizer, repo, new(mocks.TwitterService), sessionKey)
+
+		ctx, err := service.OnBehalfOf(context.Background(), "1")
+		assert.NoError(t, err)
+		assert.Equal(t, "access", ctx.Value(tokenContextKey{}).(twitter.Token).AccessToken)
+
+		authorizer.onRefresh(twitter.Token{AccessToken: "refreshed", RefreshToken: "next"})
+		stored, _ := repo.Get(context.Background(), "1")
+		assert.Equal(t, "refreshed", stored.AccessToken)
+		assert.Equal(t, "next", stored.RefreshToken)
+		assert.Equal(t, "test", stored.Username)
+	})
+
+	t.Run("on-behalf-of-unconnected-account", func(t *testing.T) {
+		service := services.NewTwitterConnectService(&fakeAuthorizer{}, repositories.NewInMemoryTwitterConnectionRepository(), new(mocks.TwitterService), sessionKey)
+
+		ctx, err := service.OnBehalfOf(context.Background(), "1")
+
+		assert.Nil(t, ctx)
+		assert.True(t, errors.Is(err, domain.ErrTwitterConnectionNotFound))
+	})
+	t.Run("session", func(t *testing.T) {
+		service := services.NewTwitterConnectService(&fakeAuthorizer{}, repositories.NewInMemoryTwitterConnectionRepository(), new(mocks.TwitterService), sessionKey)
+
+		token, err := service.Session(context.Background(), "1")
+		assert.NoError(t, err)
+
+		userID, err := service.SessionUser(context.Background(), token)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", userID)
+	})
+
+	invalidSessions := map[string]func(token string) string{
+		"forged-user": func(token string) string { return "2" + token[1:] },
+		"extended": func(token string) string {
+			parts := strings.Split(token, ".")
+			return parts[0] + ".99999999999." + parts[2]
+		},
+		"other-key": func(token string) string {
+			other := services.NewTwitterConnectService(&fakeAuthorizer{}, repositories.NewInMemoryTwitterConnectionRepository(), new(mocks.TwitterService), []byte("other"))
+			token, _ = other.Session(context.Background(), "1")
+			return token
+		},
+		"malformed": func(token string) string { return "1" },
+	}
+	for name, tamper := range invalidSessions {
+		t.Run("invalid-session-"+name, func(t *testing.T) {
+			service := services.NewTwitterConnectService(&fakeAuthorizer{}, repositories.NewInMemoryTwitterConnectionRepository(), new(mocks.TwitterService), sessionKey)
+			token, _ := service.Session(context.Background(), "1")
+
+			userID, err := service.SessionUser(context.Background(), tamper(token))
+
+			assert.Empty(t, userID)
+			assert.Equal(t, domain.ErrInvalidSession, err)
+		})
+	}
+}
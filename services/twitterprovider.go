@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+)
+
+// TwitterProvider is the Provider of Twitter accounts.
+type TwitterProvider struct {
+	TwitterService domain.TwitterService
+	now            func() time.Time
+}
+
+// NewTwitterProvider creates a TwitterProvider that looks up accounts with
+// the given TwitterService.
+func NewTwitterProvider(twitterService domain.TwitterService) domain.Provider {
+	return &TwitterProvider{
+		TwitterService: twitterService,
+		now:            time.Now,
+	}
+}
+
+// Platform returns "twitter".
+func (p *TwitterProvider) Platform() string {
+	return twitterPlatform
+}
+
+// LookupUser returns the Twitter user with the given username.
+func (p *TwitterProvider) LookupUser(ctx context.Context, username string) (*domain.Account, error) {
+	user, err := p.TwitterService.GetUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &domain.Account{
+		Platform:        twitterPlatform,
+		ID:              user.ID,
+		Username:        user.Username,
+		Name:            user.Name,
+		Description:     user.Description,
+		Location:        user.Location,
+		ProfileImageURL: user.ProfileImageURL,
+		URL:             user.URL,
+		CreatedAt:       user.CreatedAt,
+		Protected:       user.Protected,
+		Verified:        user.Verified,
+		Metrics:         p.metrics(user),
+	}
+	return account, nil
+}
+
+// ListFollowers returns the followers of the Twitter user with the given username.
+func (p *TwitterProvider) ListFollowers(ctx context.Context, username string) ([]domain.Follower, error) {
+	followers, err := p.TwitterService.GetFollowers(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return newTwitterFollowers(followers), nil
+}
+
+// ListFollowing returns the users the Twitter user with the given username follows.
+func (p *TwitterProvider) ListFollowing(ctx context.Context, username string) ([]domain.Follower, error) {
+	following, err := p.TwitterService.GetFollowing(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return newTwitterFollowers(following), nil
+}
+
+// GetMetrics returns the public metrics of the Twitter user with the given
+// username, looking up no other optional fields.
+func (p *TwitterProvider) GetMetrics(ctx context.Context, username string) (*domain.AccountMetrics, error) {
+	user, err := p.TwitterService.GetUser(ctx, username, domain.TwitterLookupOptions{Fields: []string{"public_metrics"}})
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := p.metrics(user)
+	if metrics == nil {
+		return nil, fmt.Errorf("could not retrieve metrics of %s: no public metrics were returned", username)
+	}
+	return metrics, nil
+}
+
+// metrics returns the public metrics of the user as recorded now, or nil if
+// they were not looked up.
+func (p *TwitterProvider) metrics(user *domain.TwitterUser) *domain.AccountMetrics {
+	if user.PublicMetrics == nil {
+		return nil
+	}
+	return &domain.AccountMetrics{
+		Platform:   twitterPlatform,
+		AccountID:  user.ID,
+		RecordedAt: p.now().UTC(),
+		Followers:  user.PublicMetrics.Followers,
+		Following:  user.PublicMetrics.Following,
+		Tweets:     user.PublicMetrics.Tweets,
+		Listed:     user.PublicMetrics.Listed,
+	}
+}
+
+func newTwitterFollowers(users []domain.TwitterUser) []domain.Follower {
+	followers := make([]domain.Follower, 0, len(users))
+	for _, user := range users {
+		followers = append(followers, domain.Follower{
+			ID:              user.ID,
+			Username:        user.Username,
+			Name:            user.Name,
+			ProfileImageURL: user.ProfileImageURL,
+		})
+	}
+	return followers
+}
@@ -0,0 +1,130 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/services"
+	"github.com/jake-hansen/followrs/services/mocks"
+)
+
+// TestProviderRegistry tests ProviderRegistry's Provider and Platforms funcs.
+func TestProviderRegistry(t *testing.T) {
+	twitterProvider := services.NewTwitterProvider(new(mocks.TwitterService))
+	exampleProvider := new(mocks.Provider)
+	exampleProvider.On("Platform").Return("example")
+	registry := services.NewProviderRegistry(twitterProvider, exampleProvider)
+
+	t.Run("registered", func(t *testing.T) {
+		provider, err := registry.Provider("twitter")
+
+		assert.NoError(t, err)
+		assert.Equal(t, twitterProvider, provider)
+	})
+
+	t.Run("unsupported-platform", func(t *testing.T) {
+		provider, err := registry.Provider("myspace")
+
+		assert.Nil(t, provider)
+		assert.True(t, errors.Is(err, domain.ErrUnsupportedPlatform))
+	})
+
+	t.Run("platforms", func(t *testing.T) {
+		assert.Equal(t, []string{"example", "twitter"}, registry.Platforms())
+	})
+}
+
+// TestTwitterProvider tests the funcs of TwitterProvider.
+func TestTwitterProvider(t *testing.T) {
+	createdAt := time.Date(2010, time.June, 1, 0, 0, 0, 0, time.UTC)
+	user := &domain.TwitterUser{
+		ID:            "1",
+		Name:          "Test",
+		Username:      "test",
+		CreatedAt:     &createdAt,
+		Verified:      true,
+		PublicMetrics: &domain.TwitterPublicMetrics{Followers: 10, Following: 20, Tweets: 30, Listed: 40},
+	}
+
+	t.Run("lookup-user", func(t *testing.T) {
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetUser", mock.Anything, "test").Return(user, nil)
+		provider := services.NewTwitterProvider(twitterService)
+
+		account, err := provider.LookupUser(context.Background(), "test")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "twitter", account.Platform)
+		assert.Equal(t, "1", account.ID)
+		assert.Equal(t, "Test", account.Name)
+		assert.Equal(t, &createdAt, account.CreatedAt)
+		assert.True(t, account.Verified)
+		assert.Equal(t, int64(10), account.Metrics.Followers)
+		assert.Equal(t, int64(40), account.Metrics.Listed)
+	})
+
+	t.Run("lookup-user-failed", func(t *testing.T) {
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetUser", mock.Anything, "test").Return(nil, apperrors.ErrNotFound)
+		provider := services.NewTwitterProvider(twitterService)
+
+		account, err := provider.LookupUser(context.Background(), "test")
+
+		assert.Nil(t, account)
+		assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+	})
+
+	t.Run("list-followers", func(t *testing.T) {
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetFollowers", mock.Anything, "test").Return([]domain.TwitterUser{{ID: "2", Username: "two", Name: "Two", ProfileImageURL: "https://example.com/2.png"}}, nil)
+		provider := services.NewTwitterProvider(twitterService)
+
+		followers, err := provider.ListFollowers(context.Background(), "test")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Follower{{ID: "2", Username: "two", Name: "Two", ProfileImageURL: "https://example.com/2.png"}}, followers)
+	})
+
+	t.Run("list-following", func(t *testing.T) {
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetFollowing", mock.Anything, "test").Return([]domain.TwitterUser{}, nil)
+		provider := services.NewTwitterProvider(twitterService)
+
+		following, err := provider.ListFollowing(context.Background(), "test")
+
+		assert.NoError(t, err)
+		assert.Empty(t, following)
+	})
+
+	t.Run("get-metrics", func(t *testing.T) {
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetUser", mock.Anything, "test", domain.TwitterLookupOptions{Fields: []string{"public_metrics"}}).Return(user, nil)
+		provider := services.NewTwitterProvider(twitterService)
+
+		metrics, err := provider.GetMetrics(context.Background(), "test")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1", metrics.AccountID)
+		assert.Equal(t, int64(20), metrics.Following)
+		assert.Equal(t, int64(30), metrics.Tweets)
+		twitterService.AssertExpectations(t)
+	})
+
+	t.Run("get-metrics-not-returned", func(t *testing.T) {
+		twitterService := new(mocks.TwitterService)
+		twitterService.On("GetUser", mock.Anything, "test", mock.Anything).Return(&domain.TwitterUser{ID: "1", Username: "test"}, nil)
+		provider := services.NewTwitterProvider(twitterService)
+
+		metrics, err := provider.GetMetrics(context.Background(), "test")
+
+		assert.Nil(t, metrics)
+		assert.Error(t, err)
+	})
+}
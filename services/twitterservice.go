@@ -1,32 +1,296 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"time"
+
 	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories/apis/twitter"
 )
 
+// TwitterRepository retrieves users from the Twitter API in the types the API
+// describes them with, which TwitterService converts into domain types.
+// The optional fields of the users looked up are given by each lookup; all of
+// them are looked up if they are nil.
+type TwitterRepository interface {
+	GetUser(ctx context.Context, username string, fields []string) (*twitter.User, error)
+	GetUserByID(ctx context.Context, id string, fields []string) (*twitter.User, error)
+	GetUsers(ctx context.Context, usernames []string, fields []string) ([]twitter.User, []twitter.LookupError, error)
+	GetUsersByID(ctx context.Context, ids []string, fields []string) ([]twitter.User, []twitter.LookupError, error)
+	GetAuthenticatedUser(ctx context.Context) (*twitter.User, error)
+	GetFollowers(ctx context.Context, id string, fields []string) ([]twitter.User, error)
+	GetFollowersRateLimit() (int64, time.Time)
+	GetFollowing(ctx context.Context, id string, fields []string) ([]twitter.User, error)
+}
+
 type TwitterService struct {
-	Repo *domain.TwitterRepository
+	Repo *TwitterRepository
 }
 
-func NewTwitterService(repo *domain.TwitterRepository) domain.TwitterService{
+func NewTwitterService(repo *TwitterRepository) domain.TwitterService{
 	service := &TwitterService{
 		Repo: repo,
 	}
 	return service
 }
 
-func (t *TwitterService) GetUser(username string) (*domain.TwitterUser, error) {
-	user, err := (*t.Repo).GetUser(username)
+func (t *TwitterService) GetUser(ctx context.Context, username string, opts ...domain.TwitterLookupOptions) (*domain.TwitterUser, error) {
+	fields, err := userFields(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := (*t.Repo).GetUser(ctx, username, fields)
 	if err != nil {
 		return nil, fmt.Errorf("an error ocurred retreiving the user %s from Twitter: %w", username, err)
 	}
 
-	domainUser := &domain.TwitterUser{
-		ID:       user.ID,
-		Name:     user.Name,
-		Username: user.Username,
+	domainUser := newTwitterUser(user)
+
+	return &domainUser, nil
+}
+
+func (t *TwitterService) GetUserByID(ctx context.Context, id string, opts ...domain.TwitterLookupOptions) (*domain.TwitterUser, error) {
+	fields, err := userFields(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := (*t.Repo).GetUserByID(ctx, id, fields)
+	if err != nil {
+		return nil, fmt.Errorf("an error ocurred retreiving the user with id %s from Twitter: %w", id, err)
+	}
+
+	domainUser := newTwitterUser(user)
+
+	return &domainUser, nil
+}
+
+// GetUsers looks up the users with the given usernames.
+func (t *TwitterService) GetUsers(ctx context.Context, usernames []string, opts ...domain.TwitterLookupOptions) (*domain.TwitterUsers, error) {
+	fields, err := userFields(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	users, lookupErrors, err := (*t.Repo).GetUsers(ctx, usernames, fields)
+	if err != nil {
+		return nil, fmt.Errorf("an error ocurred retreiving users from Twitter: %w", err)
+	}
+
+	return newTwitterUsersLookup(users, lookupErrors), nil
+}
+
+// GetUsersByID looks up the users with the given IDs.
+func (t *TwitterService) GetUsersByID(ctx context.Context, ids []string, opts ...domain.TwitterLookupOptions) (*domain.TwitterUsers, error) {
+	fields, err := userFields(opts)
+	if err != nil {
+		return nil, err
 	}
 
-	return domainUser, nil
+	users, lookupErrors, err := (*t.Repo).GetUsersByID(ctx, ids, fields)
+	if err != nil {
+		return nil, fmt.Errorf("an error ocurred retreiving users by id from Twitter: %w", err)
+	}
+
+	return newTwitterUsersLookup(users, lookupErrors), nil
+}
+
+// GetAuthenticatedUser returns the user that requests made with the given
+// context are authenticated on behalf of.
+func (t *TwitterService) GetAuthenticatedUser(ctx context.Context) (*domain.TwitterUser, error) {
+	user, err := (*t.Repo).GetAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("an error ocurred retreiving the authenticated user from Twitter: %w", err)
+	}
+
+	domainUser := newTwitterUser(user)
+
+	return &domainUser, nil
+}
+
+func (t *TwitterService) GetFollowers(ctx context.Context, username string, opts ...domain.TwitterLookupOptions) ([]domain.TwitterUser, error) {
+	fields, err := userFields(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := t.GetUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	followers, err := (*t.Repo).GetFollowers(ctx, user.ID, fields)
+	if err != nil {
+		return nil, fmt.Errorf("an error ocurred retreiving the followers of %s from Twitter: %w", username, err)
+	}
+
+	return newTwitterUsers(followers), nil
+}
+
+// GetFollowersByID is like GetFollowers, but lists the followers of the user
+// with the given ID, which saves looking the user up when the ID is known.
+func (t *TwitterService) GetFollowersByID(ctx context.Context, id string, opts ...domain.TwitterLookupOptions) ([]domain.TwitterUser, error) {
+	fields, err := userFields(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	followers, err := (*t.Repo).GetFollowers(ctx, id, fields)
+	if err != nil {
+		return nil, fmt.Errorf("an error ocurred retreiving the followers of the user with id %s from Twitter: %w", id, err)
+	}
+
+	return newTwitterUsers(followers), nil
+}
+
+// GetFollowersRateLimit returns the rate limit state of the followers lookup,
+// which is the most restricted request made when tracking followers.
+func (t *TwitterService) GetFollowersRateLimit() domain.RateLimit {
+	remaining, reset := (*t.Repo).GetFollowersRateLimit()
+	return domain.RateLimit{
+		Remaining: remaining,
+		Reset:     reset,
+	}
+}
+
+func (t *TwitterService) GetFollowing(ctx context.Context, username string, opts ...domain.TwitterLookupOptions) ([]domain.TwitterUser, error) {
+	fields, err := userFields(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := t.GetUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	following, err := (*t.Repo).GetFollowing(ctx, user.ID, fields)
+	if err != nil {
+		return nil, fmt.Errorf("an error ocurred retreiving the users %s follows from Twitter: %w", username, err)
+	}
+
+	return newTwitterUsers(following), nil
+}
+
+// GetRelationships compares the followers of the given user with the users
+// they follow and sorts everyone into mutuals, fans and non-followers.
+func (t *TwitterService) GetRelationships(ctx context.Context, username string, opts ...domain.TwitterLookupOptions) (*domain.TwitterRelationships, error) {
+	fields, err := userFields(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := t.GetUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	followers, err := (*t.Repo).GetFollowers(ctx, user.ID, fields)
+	if err != nil {
+		return nil, fmt.Errorf("an error ocurred retreiving the followers of %s from Twitter: %w", username, err)
+	}
+
+	following, err := (*t.Repo).GetFollowing(ctx, user.ID, fields)
+	if err != nil {
+		return nil, fmt.Errorf("an error ocurred retreiving the users %s follows from Twitter: %w", username, err)
+	}
+
+	followingIDs := make(map[string]bool, len(following))
+	for _, u := range following {
+		followingIDs[u.ID] = true
+	}
+	followerIDs := make(map[string]bool, len(followers))
+	for _, u := range followers {
+		followerIDs[u.ID] = true
+	}
+
+	relationships := &domain.TwitterRelationships{
+		Mutuals:      []domain.TwitterUser{},
+		Fans:         []domain.TwitterUser{},
+		NonFollowers: []domain.TwitterUser{},
+	}
+	for i := range followers {
+		if followingIDs[followers[i].ID] {
+			relationships.Mutuals = append(relationships.Mutuals, newTwitterUser(&followers[i]))
+		} else {
+			relationships.Fans = append(relationships.Fans, newTwitterUser(&followers[i]))
+		}
+	}
+	for i := range following {
+		if !followerIDs[following[i].ID] {
+			relationships.NonFollowers = append(relationships.NonFollowers, newTwitterUser(&following[i]))
+		}
+	}
+
+	return relationships, nil
+}
+
+// userFields returns the optional fields selected by the lookup options, or nil
+// to look up all of them. It fails with ErrUnknownUserField if any of the
+// fields is unknown.
+func userFields(opts []domain.TwitterLookupOptions) ([]string, error) {
+	var fields []string
+	for _, opt := range opts {
+		if opt.Fields == nil {
+			continue
+		}
+		for _, field := range opt.Fields {
+			if !twitter.IsUserField(field) {
+				return nil, fmt.Errorf("%w %q", domain.ErrUnknownUserField, field)
+			}
+		}
+		fields = append([]string{}, opt.Fields...)
+	}
+	return fields, nil
+}
+
+func newTwitterUser(user *twitter.User) domain.TwitterUser {
+	domainUser := domain.TwitterUser{
+		ID:              user.ID,
+		Name:            user.Name,
+		Username:        user.Username,
+		Description:     user.Description,
+		Location:        user.Location,
+		ProfileImageURL: user.ProfileImageURL,
+		Protected:       user.Protected,
+		URL:             user.URL,
+		Verified:        user.Verified,
+	}
+	if !user.CreatedAt.IsZero() {
+		createdAt := user.CreatedAt.UTC()
+		domainUser.CreatedAt = &createdAt
+	}
+	if user.PublicMetrics != nil {
+		domainUser.PublicMetrics = &domain.TwitterPublicMetrics{
+			Followers: user.PublicMetrics.FollowersCount,
+			Following: user.PublicMetrics.FollowingCount,
+			Tweets:    user.PublicMetrics.TweetCount,
+			Listed:    user.PublicMetrics.ListedCount,
+		}
+	}
+	return domainUser
+}
+
+func newTwitterUsers(users []twitter.User) []domain.TwitterUser {
+	domainUsers := make([]domain.TwitterUser, 0, len(users))
+	for i := range users {
+		domainUsers = append(domainUsers, newTwitterUser(&users[i]))
+	}
+	return domainUsers
+}
+
+func newTwitterUsersLookup(users []twitter.User, lookupErrors []twitter.LookupError) *domain.TwitterUsers {
+	lookup := &domain.TwitterUsers{
+		Users:    newTwitterUsers(users),
+		Failures: make([]domain.TwitterLookupFailure, 0, len(lookupErrors)),
+	}
+	for _, lookupError := range lookupErrors {
+		lookup.Failures = append(lookup.Failures, domain.TwitterLookupFailure{
+			Value: lookupError.Value,
+			Err:   lookupError.Err,
+		})
+	}
+	return lookup
 }
@@ -0,0 +1,183 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories/apis/twitter"
+	"github.com/jake-hansen/followrs/repositories/mocks"
+	"github.com/jake-hansen/followrs/services"
+)
+
+func newTwitterService(repo *mocks.TwitterRepository) domain.TwitterService {
+	twitterRepo := services.TwitterRepository(repo)
+	return services.NewTwitterService(&twitterRepo)
+}
+
+// TestGetFollowers tests TwitterService's GetFollowers func.
+func TestGetFollowers(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		repo := new(mocks.TwitterRepository)
+		repo.On("GetUser", mock.Anything, "test", []string(nil)).Return(&twitter.User{ID: "1", Username: "test"}, nil)
+		repo.On("GetFollowers", mock.Anything, "1", []string(nil)).Return([]twitter.User{{ID: "2", Name: "two", Username: "two"}}, nil)
+		service := newTwitterService(repo)
+
+		followers, err := service.GetFollowers(context.Background(), "test")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.TwitterUser{{ID: "2", Name: "two", Username: "two"}}, followers)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("user-lookup-failed", func(t *testing.T) {
+		repo := new(mocks.TwitterRepository)
+		repo.On("GetUser", mock.Anything, "test", []string(nil)).Return(nil, errors.New("user not found"))
+		service := newTwitterService(repo)
+
+		followers, err := service.GetFollowers(context.Background(), "test")
+
+		assert.Nil(t, followers)
+		assert.Error(t, err)
+		repo.AssertNotCalled(t, "GetFollowers", mock.Anything, "1")
+	})
+}
+
+// TestGetFollowersByID tests TwitterService's GetFollowersByID func.
+func TestGetFollowersByID(t *testing.T) {
+	repo := new(mocks.TwitterRepository)
+	repo.On("GetFollowers", mock.Anything, "1", []string(nil)).Return([]twitter.User{{ID: "2", Name: "two", Username: "two"}}, nil)
+	service := newTwitterService(repo)
+
+	followers, err := service.GetFollowersByID(context.Background(), "1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.TwitterUser{{ID: "2", Name: "two", Username: "two"}}, followers)
+	repo.AssertNotCalled(t, "GetUser", mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestGetUser tests TwitterService's GetUser func.
+func TestGetUser(t *testing.T) {
+	createdAt := time.Date(2013, 12, 14, 4, 35, 55, 0, time.UTC)
+	repo := new(mocks.TwitterRepository)
+	repo.On("GetUser", mock.Anything, "test", []string(nil)).Return(&twitter.User{
+		ID:              "1",
+		Name:            "Test",
+		Username:        "test",
+		CreatedAt:       createdAt,
+		Description:     "bio",
+		ProfileImageURL: "https://example.com/test.png",
+		Verified:        true,
+		PublicMetrics:   &twitter.PublicMetrics{FollowersCount: 10, FollowingCount: 20, TweetCount: 30, ListedCount: 4},
+	}, nil)
+	service := newTwitterService(repo)
+
+	user, err := service.GetUser(context.Background(), "test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.TwitterUser{
+		ID:              "1",
+		Name:            "Test",
+		Username:        "test",
+		CreatedAt:       &createdAt,
+		Description:     "bio",
+		ProfileImageURL: "https://example.com/test.png",
+		Verified:        true,
+		PublicMetrics:   &domain.TwitterPublicMetrics{Followers: 10, Following: 20, Tweets: 30, Listed: 4},
+	}, user)
+}
+
+// TestGetUser_LookupOptions tests looking up the fields selected by lookup
+// options.
+func TestGetUser_LookupOptions(t *testing.T) {
+	t.Run("selected-fields", func(t *testing.T) {
+		repo := new(mocks.TwitterRepository)
+		repo.On("GetUser", mock.Anything, "test", []string{"public_metrics"}).Return(&twitter.User{ID: "1", Username: "test"}, nil)
+		service := newTwitterService(repo)
+
+		_, err := service.GetUser(context.Background(), "test", domain.TwitterLookupOptions{Fields: []string{"public_metrics"}})
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("no-fields", func(t *testing.T) {
+		repo := new(mocks.TwitterRepository)
+		repo.On("GetUser", mock.Anything, "test", []string{}).Return(&twitter.User{ID: "1", Username: "test"}, nil)
+		service := newTwitterService(repo)
+
+		_, err := service.GetUser(context.Background(), "test", domain.TwitterLookupOptions{Fields: []string{}})
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("unknown-field", func(t *testing.T) {
+		repo := new(mocks.TwitterRepository)
+		service := newTwitterService(repo)
+
+		user, err := service.GetUser(context.Background(), "test", domain.TwitterLookupOptions{Fields: []string{"pinned_tweet"}})
+
+		assert.Nil(t, user)
+		assert.True(t, errors.Is(err, domain.ErrUnknownUserField))
+		repo.AssertNotCalled(t, "GetUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// TestGetUsers tests TwitterService's GetUsers func.
+func TestGetUsers(t *testing.T) {
+	repo := new(mocks.TwitterRepository)
+	notFound := errors.New("user not found")
+	repo.On("GetUsers", mock.Anything, []string{"one", "two"}, []string(nil)).Return(
+		[]twitter.User{{ID: "1", Username: "one"}},
+		[]twitter.LookupError{{Value: "two", Err: notFound}},
+		nil)
+	service := newTwitterService(repo)
+
+	users, err := service.GetUsers(context.Background(), []string{"one", "two"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.TwitterUsers{
+		Users:    []domain.TwitterUser{{ID: "1", Username: "one"}},
+		Failures: []domain.TwitterLookupFailure{{Value: "two", Err: notFound}},
+	}, users)
+}
+
+// TestGetRelationships tests TwitterService's GetRelationships func.
+func TestGetRelationships(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		repo := new(mocks.TwitterRepository)
+		repo.On("GetUser", mock.Anything, "test", []string(nil)).Return(&twitter.User{ID: "1", Username: "test"}, nil)
+		repo.On("GetFollowers", mock.Anything, "1", []string(nil)).Return([]twitter.User{{ID: "2"}, {ID: "3"}}, nil)
+		repo.On("GetFollowing", mock.Anything, "1", []string(nil)).Return([]twitter.User{{ID: "3"}, {ID: "4"}}, nil)
+		service := newTwitterService(repo)
+
+		relationships, err := service.GetRelationships(context.Background(), "test")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.TwitterUser{{ID: "3"}}, relationships.Mutuals)
+		assert.Equal(t, []domain.TwitterUser{{ID: "2"}}, relationships.Fans)
+		assert.Equal(t, []domain.TwitterUser{{ID: "4"}}, relationships.NonFollowers)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("following-lookup-failed", func(t *testing.T) {
+		repo := new(mocks.TwitterRepository)
+		repo.On("GetUser", mock.Anything, "test", []string(nil)).Return(&twitter.User{ID: "1", Username: "test"}, nil)
+		repo.On("GetFollowers", mock.Anything, "1", []string(nil)).Return([]twitter.User{{ID: "2"}}, nil)
+		repo.On("GetFollowing", mock.Anything, "1", []string(nil)).Return(nil, errors.New("example error"))
+		service := newTwitterService(repo)
+
+		relationships, err := service.GetRelationships(context.Background(), "test")
+
+		assert.Nil(t, relationships)
+		assert.Error(t, err)
+		repo.AssertExpectations(t)
+	})
+}
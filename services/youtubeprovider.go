@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories/apis/youtube"
+)
+
+// youtubePlatform is the name of the platform of YouTube channels.
+const youtubePlatform = "youtube"
+
+// YouTubeRepository retrieves channels from the YouTube Data API in the types
+// the API describes them with. Channels are looked up by their handle or ID.
+type YouTubeRepository interface {
+	GetChannel(ctx context.Context, handleOrID string) (*youtube.Channel, error)
+}
+
+// YouTubeProvider is the Provider of YouTube channels. Usernames are the
+// handles of channels, such as "@youtube", or their IDs. The subscribers of
+// channels can't be listed, so followers are subscribers only in counts.
+type YouTubeProvider struct {
+	Repo YouTubeRepository
+	now  func() time.Time
+}
+
+// NewYouTubeProvider creates a YouTubeProvider that looks up channels with the
+// given YouTubeRepository.
+func NewYouTubeProvider(repo YouTubeRepository) domain.Provider {
+	return &YouTubeProvider{
+		Repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Platform returns "youtube".
+func (p *YouTubeProvider) Platform() string {
+	return youtubePlatform
+}
+
+// LookupUser returns the YouTube channel with the given handle or ID.
+func (p *YouTubeProvider) LookupUser(ctx context.Context, username string) (*domain.Account, error) {
+	channel, err := p.getChannel(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &domain.Account{
+		Platform:        youtubePlatform,
+		ID:              channel.ID,
+		Username:        channel.Handle(),
+		Name:            channel.Snippet.Title,
+		Description:     channel.Snippet.Description,
+		ProfileImageURL: channel.Snippet.Thumbnails["default"].URL,
+		URL:             youtubeChannelURL(channel),
+		CreatedAt:       channel.Snippet.PublishedAt,
+		Metrics:         p.metrics(channel),
+	}
+	return account, nil
+}
+
+// ListFollowers fails with domain.ErrUnsupportedPlatform, since YouTube only
+// lists the subscribers of the channel of the account making the request.
+func (p *YouTubeProvider) ListFollowers(ctx context.Context, username string) ([]domain.Follower, error) {
+	return nil, fmt.Errorf("YouTube does not list the subscribers of %s: %w", username, domain.ErrUnsupportedPlatform)
+}
+
+// ListFollowing fails with domain.ErrUnsupportedPlatform, since YouTube only
+// lists the subscriptions of channels that have made them public.
+func (p *YouTubeProvider) ListFollowing(ctx context.Context, username string) ([]domain.Follower, error) {
+	return nil, fmt.Errorf("YouTube does not list the subscriptions of %s: %w", username, domain.ErrUnsupportedPlatform)
+}
+
+// GetMetrics returns the subscriber, view and video counts of the YouTube
+// channel with the given handle or ID.
+func (p *YouTubeProvider) GetMetrics(ctx context.Context, username string) (*domain.AccountMetrics, error) {
+	channel, err := p.getChannel(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return p.metrics(channel), nil
+}
+
+// GetMetricsByID returns the counts of the YouTube channel with the given ID,
+// which stays the same when the channel changes its handle.
+func (p *YouTubeProvider) GetMetricsByID(ctx context.Context, id string) (*domain.AccountMetrics, error) {
+	return p.GetMetrics(ctx, id)
+}
+
+func (p *YouTubeProvider) getChannel(ctx context.Context, username string) (*youtube.Channel, error) {
+	channel, err := p.Repo.GetChannel(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred retrieving the channel %s from YouTube: %w", username, err)
+	}
+	return channel, nil
+}
+
+// metrics returns the counts of the channel as recorded now. Subscribers are
+// counted as followers, unless the channel hides how many it has, and videos
+// are counted as posts.
+func (p *YouTubeProvider) metrics(channel *youtube.Channel) *domain.AccountMetrics {
+	return &domain.AccountMetrics{
+		Platform:        youtubePlatform,
+		AccountID:       channel.ID,
+		RecordedAt:      p.now().UTC(),
+		Followers:       channel.Statistics.SubscriberCount,
+		FollowersHidden: channel.Statistics.HiddenSubscriberCount,
+		Tweets:          channel.Statistics.VideoCount,
+		Views:           channel.Statistics.ViewCount,
+	}
+}
+
+// youtubeChannelURL returns the URL of the channel on YouTube.
+func youtubeChannelURL(channel *youtube.Channel) string {
+	if channel.Snippet.CustomURL != "" {
+		return fmt.Sprintf("https://www.youtube.com/%s", channel.Snippet.CustomURL)
+	}
+	return fmt.Sprintf("https://www.youtube.com/channel/%s", channel.ID)
+}
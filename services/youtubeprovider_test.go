@@ -0,0 +1,103 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jake-hansen/followrs/apperrors"
+	"github.com/jake-hansen/followrs/domain"
+	"github.com/jake-hansen/followrs/repositories/apis/youtube"
+	"github.com/jake-hansen/followrs/repositories/mocks"
+	"github.com/jake-hansen/followrs/services"
+)
+
+// TestYouTubeProvider tests the funcs of YouTubeProvider.
+func TestYouTubeProvider(t *testing.T) {
+	publishedAt := time.Date(2005, time.September, 18, 22, 37, 10, 0, time.UTC)
+	channel := &youtube.Channel{
+		ID: "UCBR8-60-B28hp2BmDPdntcQ",
+		Snippet: youtube.ChannelSnippet{
+			Title:       "YouTube",
+			Description: "The official YouTube channel",
+			CustomURL:   "@youtube",
+			PublishedAt: &publishedAt,
+			Thumbnails:  map[string]youtube.Thumbnail{"default": {URL: "https://yt3.ggpht.com/default.jpg"}},
+		},
+		Statistics: youtube.ChannelStatistics{ViewCount: 3000, SubscriberCount: 40, VideoCount: 500},
+	}
+
+	t.Run("lookup-user", func(t *testing.T) {
+		repo := new(mocks.YouTubeRepository)
+		repo.On("GetChannel", mock.Anything, "@youtube").Return(channel, nil)
+		provider := services.NewYouTubeProvider(repo)
+
+		account, err := provider.LookupUser(context.Background(), "@youtube")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "youtube", account.Platform)
+		assert.Equal(t, "UCBR8-60-B28hp2BmDPdntcQ", account.ID)
+		assert.Equal(t, "youtube", account.Username)
+		assert.Equal(t, "YouTube", account.Name)
+		assert.Equal(t, "https://yt3.ggpht.com/default.jpg", account.ProfileImageURL)
+		assert.Equal(t, "https://www.youtube.com/@youtube", account.URL)
+		assert.Equal(t, &publishedAt, account.CreatedAt)
+		assert.Equal(t, int64(40), account.Metrics.Followers)
+	})
+
+	t.Run("lookup-user-failed", func(t *testing.T) {
+		repo := new(mocks.YouTubeRepository)
+		repo.On("GetChannel", mock.Anything, "youtube").Return(nil, apperrors.ErrRateLimited)
+		provider := services.NewYouTubeProvider(repo)
+
+		account, err := provider.LookupUser(context.Background(), "youtube")
+
+		assert.Nil(t, account)
+		assert.True(t, errors.Is(err, apperrors.ErrRateLimited))
+	})
+
+	t.Run("list-followers", func(t *testing.T) {
+		provider := services.NewYouTubeProvider(new(mocks.YouTubeRepository))
+
+		followers, err := provider.ListFollowers(context.Background(), "youtube")
+		assert.Nil(t, followers)
+		assert.True(t, errors.Is(err, domain.ErrUnsupportedPlatform))
+
+		following, err := provider.ListFollowing(context.Background(), "youtube")
+		assert.Nil(t, following)
+		assert.True(t, errors.Is(err, domain.ErrUnsupportedPlatform))
+	})
+
+	t.Run("get-metrics", func(t *testing.T) {
+		repo := new(mocks.YouTubeRepository)
+		repo.On("GetChannel", mock.Anything, "youtube").Return(channel, nil)
+		provider := services.NewYouTubeProvider(repo)
+
+		metrics, err := provider.GetMetrics(context.Background(), "youtube")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "UCBR8-60-B28hp2BmDPdntcQ", metrics.AccountID)
+		assert.Equal(t, int64(40), metrics.Followers)
+		assert.False(t, metrics.FollowersHidden)
+		assert.Equal(t, int64(500), metrics.Tweets)
+		assert.Equal(t, int64(3000), metrics.Views)
+	})
+
+	t.Run("get-metrics-hidden-subscribers", func(t *testing.T) {
+		hidden := *channel
+		hidden.Statistics = youtube.ChannelStatistics{ViewCount: 3000, HiddenSubscriberCount: true, VideoCount: 500}
+		repo := new(mocks.YouTubeRepository)
+		repo.On("GetChannel", mock.Anything, "youtube").Return(&hidden, nil)
+		provider := services.NewYouTubeProvider(repo)
+
+		metrics, err := provider.GetMetrics(context.Background(), "youtube")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), metrics.Followers)
+		assert.True(t, metrics.FollowersHidden)
+	})
+}